@@ -10,11 +10,13 @@ import (
 	"github.com/devblac/prw/internal/config"
 	"github.com/devblac/prw/internal/github"
 	"github.com/devblac/prw/internal/notify"
+	"github.com/devblac/prw/internal/watcher"
 )
 
 var (
 	broadcastFilter  string
 	broadcastWebhook string
+	broadcastFormat  string
 	broadcastDryRun  bool
 )
 
@@ -22,6 +24,7 @@ func init() {
 	rootCmd.AddCommand(broadcastCmd)
 	broadcastCmd.Flags().StringVar(&broadcastFilter, "filter", "all", "statuses to include: all, changed, failing")
 	broadcastCmd.Flags().StringVar(&broadcastWebhook, "webhook", "", "override webhook URL for this broadcast")
+	broadcastCmd.Flags().StringVar(&broadcastFormat, "format", "", "webhook payload format: json, slack, discord, teams, or auto (default: webhook_format, or auto with --webhook)")
 	broadcastCmd.Flags().BoolVar(&broadcastDryRun, "dry-run", false, "print statuses without sending to webhook")
 }
 
@@ -40,9 +43,9 @@ var broadcastCmd = &cobra.Command{
 			return nil
 		}
 
-		token := cfg.GetToken()
-		if token == "" {
-			return fmt.Errorf("missing GITHUB_TOKEN; set it as an environment variable or configure it with 'prw config set github_token <token>'")
+		clients, err := newHostClients(cfg)
+		if err != nil {
+			return err
 		}
 
 		filter := strings.ToLower(strings.TrimSpace(broadcastFilter))
@@ -53,22 +56,27 @@ var broadcastCmd = &cobra.Command{
 			return fmt.Errorf("invalid --filter value %q (expected all, changed, or failing)", broadcastFilter)
 		}
 
-		client := newGitHubClient(token)
-
-		webhookURL := broadcastWebhook
+		webhookURL, webhookFormat := broadcastWebhook, broadcastFormat
 		if webhookURL == "" {
 			webhookURL = cfg.WebhookURL
+			if webhookFormat == "" {
+				webhookFormat = cfg.WebhookFormat
+			}
+		}
+		if !notify.IsValidWebhookFormat(strings.ToLower(webhookFormat)) {
+			return fmt.Errorf("invalid --format value %q (expected json, slack, discord, teams, or auto)", broadcastFormat)
 		}
 
 		notifiers := []notify.Notifier{notify.NewConsoleNotifier()}
 		if !broadcastDryRun && webhookURL != "" {
-			notifiers = append(notifiers, notify.NewWebhookNotifier(webhookURL))
+			notifiers = append(notifiers, newWebhookNotifier(cfg, webhookURL, webhookFormat))
 		}
 		notifier := notify.NewMultiNotifier(notifiers...)
 
 		var anySent bool
 		for i := range cfg.WatchedPRs {
 			pr := &cfg.WatchedPRs[i]
+			client := clients[pr.HostName()]
 
 			ghPR, err := client.GetPullRequest(pr.Owner, pr.Repo, pr.Number)
 			if err != nil {
@@ -76,13 +84,12 @@ var broadcastCmd = &cobra.Command{
 				continue
 			}
 
-			status, err := client.GetCombinedStatus(pr.Owner, pr.Repo, ghPR.Head.SHA)
+			currentState, checks, err := watcher.CommitState(client, pr.Owner, pr.Repo, ghPR.Head.SHA)
 			if err != nil {
 				fmt.Printf("Error fetching status for %s/%s#%d: %v\n", pr.Owner, pr.Repo, pr.Number, err)
 				continue
 			}
 
-			currentState := github.NormalizeState(status.State)
 			previousState := github.NormalizeState(pr.LastKnownState)
 			changed := previousState != "" && previousState != currentState
 
@@ -110,6 +117,7 @@ var broadcastCmd = &cobra.Command{
 			}
 
 			event := &notify.StatusChangeEvent{
+				Host:          pr.Host,
 				Owner:         pr.Owner,
 				Repo:          pr.Repo,
 				Number:        pr.Number,
@@ -117,6 +125,7 @@ var broadcastCmd = &cobra.Command{
 				PreviousState: previousState,
 				CurrentState:  currentState,
 				SHA:           ghPR.Head.SHA,
+				Checks:        checks,
 				Timestamp:     time.Now(),
 			}
 
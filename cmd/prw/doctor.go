@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+	"github.com/devblac/prw/internal/watcher"
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check configuration, token, and GitHub API budget",
+	Long: `Run a series of checks to diagnose common setup problems:
+config file, GitHub token, API reachability, and the remaining rate limit budget.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems := 0
+		report := func(ok bool, format string, a ...interface{}) {
+			mark := "✓"
+			if !ok {
+				mark = "✗"
+				problems++
+			}
+			fmt.Printf("%s %s\n", mark, fmt.Sprintf(format, a...))
+		}
+
+		path, err := config.ConfigPath()
+		if err != nil {
+			return fmt.Errorf("failed to determine config path: %w", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			report(false, "Config file: %s (%v)", path, err)
+			return fmt.Errorf("doctor found %d problem(s)", problems)
+		}
+		report(true, "Config file: %s (%d watched PRs)", path, len(cfg.WatchedPRs))
+
+		for _, host := range doctorHosts(cfg) {
+			checkHost(cfg, host, report)
+		}
+
+		if cfg.WebhookURL != "" {
+			report(true, "Webhook: %s", cfg.WebhookURL)
+		} else {
+			fmt.Println("- Webhook: not configured")
+		}
+
+		switch {
+		case cfg.SlackBotToken != "" && cfg.SlackChannel != "":
+			report(true, "Slack: bot posting to %s", cfg.SlackChannel)
+		case cfg.SlackBotToken != "":
+			report(false, "Slack: slack_bot_token is set but slack_channel is not")
+		case cfg.SlackWebhookURL != "":
+			report(true, "Slack: incoming webhook")
+		}
+
+		switch email := cfg.Email; {
+		case email.Enabled():
+			report(true, "Email: %s to %d recipient(s) via %s", email.From, len(email.To), email.SMTPHost)
+		case email != nil && email.SMTPHost != "":
+			report(false, "Email: email_smtp_host is set but email_from or email_to is not")
+		}
+
+		for _, notifier := range notify.TemplateNotifiers {
+			if len(cfg.Templates[notifier]) == 0 {
+				continue
+			}
+			if _, err := notify.NewMessageTemplate(notifier, cfg.Templates[notifier]); err != nil {
+				report(false, "Templates: %v", err)
+			} else {
+				report(true, "Templates: %s (%s)", notifier, strings.Join(cfg.TemplateKeys(notifier), ", "))
+			}
+		}
+
+		for i, route := range cfg.Routes {
+			if err := cfg.ValidateRoute(route); err != nil {
+				report(false, "Route %d: %v", i+1, err)
+				continue
+			}
+			var inactive []string
+			for _, name := range route.Notify {
+				if reason := inactiveNotifier(cfg, name); reason != "" {
+					inactive = append(inactive, fmt.Sprintf("%s (%s)", name, reason))
+				}
+			}
+			if len(inactive) > 0 {
+				report(false, "Route %d: inactive notifiers: %s", i+1, strings.Join(inactive, ", "))
+			} else {
+				report(true, "Route %d: %s", i+1, describeRoute(route))
+			}
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("doctor found %d problem(s)", problems)
+		}
+		fmt.Println("\nAll checks passed.")
+		return nil
+	},
+}
+
+// doctorHosts returns github.com followed by every configured or watched Enterprise host.
+func doctorHosts(cfg *config.Config) []string {
+	hosts := []string{github.DefaultHost}
+	seen := map[string]bool{github.DefaultHost: true}
+	add := func(host string) {
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	for _, host := range cfg.WatchedHosts() {
+		add(host)
+	}
+	names := make([]string, 0, len(cfg.Hosts))
+	for host := range cfg.Hosts {
+		names = append(names, github.NormalizeHost(host))
+	}
+	sort.Strings(names)
+	for _, host := range names {
+		add(host)
+	}
+	return hosts
+}
+
+// checkHost reports the token source, API reachability, and rate limit for one host.
+func checkHost(cfg *config.Config, host string, report func(ok bool, format string, a ...interface{})) {
+	watched := 0
+	for _, pr := range cfg.WatchedPRs {
+		if pr.HostName() == host {
+			watched++
+		}
+	}
+
+	label := "GitHub"
+	if host != github.DefaultHost {
+		label = host
+	}
+
+	token := cfg.TokenForHost(host)
+	switch {
+	case host != github.DefaultHost && token != "":
+		report(true, "%s token: config file", label)
+	case host != github.DefaultHost:
+		report(false, "%s token: not set (run 'prw config set github_token <token> --host %s')", label, host)
+	case cfg.GitHubToken != "":
+		report(true, "GitHub token: config file")
+	case os.Getenv("GITHUB_TOKEN") != "":
+		report(true, "GitHub token: environment variable")
+	default:
+		report(false, "GitHub token: not set (export GITHUB_TOKEN or run 'prw config set github_token <token>')")
+	}
+	if token == "" {
+		return
+	}
+
+	client := newConfiguredClient(cfg, host, token)
+	limit, err := client.GetRateLimit()
+	if err != nil {
+		report(false, "%s API: %v", label, err)
+		return
+	}
+	report(true, "%s API: reachable (%s)", label, client.BaseURL)
+
+	if !limit.Known() {
+		fmt.Printf("- %s rate limit: not enforced\n", label)
+		return
+	}
+
+	// Estimate whether the budget covers an hour of polling at the current interval.
+	interval := cfg.PollIntervalSeconds
+	if interval <= 0 {
+		interval = 20
+	}
+	perHour := watched * watcher.RequestsPerPR * (3600 / interval)
+	report(limit.Remaining > 0, "%s rate limit: %s", label, limit)
+	if perHour > limit.Limit {
+		fmt.Printf("  note: polling %d PRs every %ds may need up to %d requests/hour; prw slows down automatically when the budget runs low\n",
+			watched, interval, perHour)
+	}
+}
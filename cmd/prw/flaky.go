@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+)
+
+var flakyJSON bool
+
+func init() {
+	rootCmd.AddCommand(flakyCmd)
+	flakyCmd.Flags().BoolVar(&flakyJSON, "json", false, "output flaky checks as JSON")
+}
+
+var flakyCmd = &cobra.Command{
+	Use:   "flaky [owner/repo]",
+	Short: "Show checks that flip between failure and success",
+	Long: `Show the checks of watched PRs that both failed and passed on the same commit,
+e.g. after a rerun. The score is the share of the check's last 50 commits it
+flipped on. Failures of checks that flipped on at least 2 of them, or that are
+listed in flaky_checks, are tagged as likely flaky in notifications.
+
+Pass owner/repo, or just owner, to limit the report.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var owner, repo string
+		if len(args) == 1 {
+			owner, repo, _ = strings.Cut(strings.Trim(args[0], "/"), "/")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		store, err := history.OpenCheckStats()
+		if err != nil {
+			return fmt.Errorf("failed to open check stats: %w", err)
+		}
+		stats := flakyStats(store.All(), owner, repo)
+
+		if flakyJSON {
+			return outputJSONFlaky(cfg, stats)
+		}
+
+		if len(stats) == 0 {
+			fmt.Println("No flaky checks detected.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REPO\tCHECK\tSCORE\tFLAKY COMMITS\tLIKELY FLAKY\tLAST FLAKY")
+		fmt.Fprintln(w, "----\t-----\t-----\t-------------\t------------\t----------")
+		for _, s := range stats {
+			repo := fmt.Sprintf("%s/%s", s.Owner, s.Repo)
+			if host := s.HostName(); host != github.DefaultHost {
+				repo = host + "/" + repo
+			}
+			likely := "no"
+			if isLikelyFlaky(cfg, s) {
+				likely = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%.0f%%\t%d/%d\t%s\t%s\n",
+				repo, s.Check, s.Score()*100, s.FlakyCommits(), len(s.Recent), likely,
+				s.LastFlaky.Local().Format("2006-01-02 15:04"))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// flakyStats returns the checks that flipped on at least one recent commit,
+// optionally limited to an owner or owner/repo, most flaky first.
+func flakyStats(all []history.CheckStats, owner, repo string) []history.CheckStats {
+	var stats []history.CheckStats
+	for _, s := range all {
+		if s.FlakyCommits() == 0 {
+			continue
+		}
+		if owner != "" && !strings.EqualFold(s.Owner, owner) {
+			continue
+		}
+		if repo != "" && !strings.EqualFold(s.Repo, repo) {
+			continue
+		}
+		stats = append(stats, s)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Score() != stats[j].Score() {
+			return stats[i].Score() > stats[j].Score()
+		}
+		if stats[i].FlakyCommits() != stats[j].FlakyCommits() {
+			return stats[i].FlakyCommits() > stats[j].FlakyCommits()
+		}
+		return stats[i].LastFlaky.After(stats[j].LastFlaky)
+	})
+	return stats
+}
+
+type flakyCheckOutput struct {
+	Host         string    `json:"host,omitempty"`
+	Owner        string    `json:"owner"`
+	Repo         string    `json:"repo"`
+	Check        string    `json:"check"`
+	Score        float64   `json:"score"`
+	FlakyCommits int       `json:"flaky_commits"`
+	Commits      int       `json:"commits"`
+	LikelyFlaky  bool      `json:"likely_flaky"`
+	LastFlaky    time.Time `json:"last_flaky"`
+}
+
+// isLikelyFlaky reports whether failures of a check are tagged as likely flaky.
+func isLikelyFlaky(cfg *config.Config, s history.CheckStats) bool {
+	return cfg.IsFlakyCheck(s.Check) || s.LikelyFlaky()
+}
+
+func outputJSONFlaky(cfg *config.Config, stats []history.CheckStats) error {
+	output := make([]flakyCheckOutput, 0, len(stats))
+	for _, s := range stats {
+		host := s.HostName()
+		if host == github.DefaultHost {
+			host = ""
+		}
+		output = append(output, flakyCheckOutput{
+			Host:         host,
+			Owner:        s.Owner,
+			Repo:         s.Repo,
+			Check:        s.Check,
+			Score:        s.Score(),
+			FlakyCommits: s.FlakyCommits(),
+			Commits:      len(s.Recent),
+			LikelyFlaky:  isLikelyFlaky(cfg, s),
+			LastFlaky:    s.LastFlaky,
+		})
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
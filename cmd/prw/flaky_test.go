@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/history"
+)
+
+func TestFlakyCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+
+	stats, err := history.OpenCheckStats()
+	if err != nil {
+		t.Fatalf("failed to open check stats: %v", err)
+	}
+	record := func(owner, repo, check string, outcomes ...string) {
+		for i, outcome := range outcomes {
+			for _, state := range strings.Split(outcome, "+") {
+				stats.Record("", owner, repo, check, fmt.Sprintf("sha%d", i), state, time.Now())
+			}
+		}
+	}
+	record("owner", "repo", "unit", "failure+success", "success", "success", "success")
+	record("owner", "repo", "e2e", "failure+success", "failure+success", "success")
+	record("owner", "other", "lint", "success")
+	record("someone", "else", "build", "failure+success")
+	if err := stats.Save(); err != nil {
+		t.Fatalf("failed to save check stats: %v", err)
+	}
+	defer func() { flakyJSON = false }()
+
+	output, err := captureStdout(func() error {
+		return flakyCmd.RunE(flakyCmd, []string{"owner/repo"})
+	})
+	if err != nil {
+		t.Fatalf("flakyCmd.RunE() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and two checks, got:\n%s", output)
+	}
+	if !strings.Contains(lines[2], "e2e") || !strings.Contains(lines[2], "67%") || !strings.Contains(lines[2], "2/3") || !strings.Contains(lines[2], "yes") {
+		t.Errorf("expected e2e first as likely flaky, got %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "unit") || !strings.Contains(lines[3], "25%") || !strings.Contains(lines[3], "no") {
+		t.Errorf("expected unit second, got %q", lines[3])
+	}
+
+	flakyJSON = true
+	output, err = captureStdout(func() error {
+		return flakyCmd.RunE(flakyCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("flakyCmd.RunE() error = %v", err)
+	}
+	var checks []flakyCheckOutput
+	if err := json.Unmarshal([]byte(output), &checks); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if len(checks) != 3 || checks[0].Check != "build" || checks[0].Score != 1 || checks[0].LikelyFlaky {
+		t.Errorf("unexpected JSON output: %+v", checks)
+	}
+
+	flakyJSON = false
+	output, err = captureStdout(func() error {
+		return flakyCmd.RunE(flakyCmd, []string{"nobody"})
+	})
+	if err != nil || !strings.Contains(output, "No flaky checks detected.") {
+		t.Errorf("expected no flaky checks for an unknown owner, got %q (%v)", output, err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+)
+
+var (
+	historySince string
+	historyUntil string
+	historyState string
+	historyRepo  string
+	historyPolls bool
+	historyLimit int
+	historyJSON  bool
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historySince, "since", "", "only show entries after this time (duration like 12h or 7d, or a date/RFC3339 time)")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "only show entries before this time (same formats as --since)")
+	historyCmd.Flags().StringVar(&historyState, "state", "", "comma-separated states to include: pending, success, failure, error, merged, closed")
+	historyCmd.Flags().StringVar(&historyRepo, "repo", "", "only show entries for owner/repo (or every repo of owner)")
+	historyCmd.Flags().BoolVar(&historyPolls, "polls", false, "include recorded poll results, not just state changes")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "show at most this many of the most recent entries")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "output history as JSON")
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [PR_URL]",
+	Short: "Show recorded status changes",
+	Long: `Show the status changes, merges, and closes recorded by 'prw run'.
+The history is kept in history.jsonl next to the config file. Poll results are
+only recorded when history_poll_results is enabled.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := historyFilter(args, time.Now())
+		if err != nil {
+			return err
+		}
+
+		store, err := history.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+
+		records, err := store.Query(filter)
+		if err != nil {
+			return err
+		}
+
+		if historyJSON {
+			if records == nil {
+				records = []history.Record{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(records)
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No history recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tREPO\tPR\tEVENT\tSTATE\tTITLE")
+		fmt.Fprintln(w, "----\t----\t--\t-----\t-----\t-----")
+		for _, r := range records {
+			repo := fmt.Sprintf("%s/%s", r.Owner, r.Repo)
+			if host := github.NormalizeHost(r.Host); host != github.DefaultHost {
+				repo = host + "/" + repo
+			}
+			event := r.Type
+			if r.Kind == history.KindPoll {
+				event = history.KindPoll
+			}
+			state := r.CurrentState
+			if r.PreviousState != "" {
+				state = fmt.Sprintf("%s → %s", r.PreviousState, r.CurrentState)
+			}
+			title := r.Title
+			if len(title) > 50 {
+				title = title[:47] + "..."
+			}
+			fmt.Fprintf(w, "%s\t%s\t#%d\t%s\t%s\t%s\n",
+				r.Timestamp.Local().Format("2006-01-02 15:04"), repo, r.Number, event, state, title)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// historyFilter builds the history filter from the command arguments and flags.
+func historyFilter(args []string, now time.Time) (history.Filter, error) {
+	filter := history.Filter{
+		IncludePolls: historyPolls,
+		Limit:        historyLimit,
+	}
+
+	if len(args) == 1 && historyRepo != "" {
+		return filter, fmt.Errorf("--repo can't be combined with a PR URL; the URL already selects the repo")
+	}
+	if len(args) == 1 {
+		host, owner, repo, number, err := github.ParseHostPRURL(args[0])
+		if err != nil {
+			return filter, fmt.Errorf("invalid PR URL: %w", err)
+		}
+		filter.Host, filter.Owner, filter.Repo, filter.Number = host, owner, repo, number
+	}
+
+	if historyRepo != "" {
+		owner, repo, _ := strings.Cut(strings.Trim(historyRepo, "/"), "/")
+		if owner == "" || strings.Contains(repo, "/") {
+			return filter, fmt.Errorf("invalid --repo value %q (expected owner/repo or owner)", historyRepo)
+		}
+		filter.Owner, filter.Repo = owner, repo
+	}
+
+	if historyState != "" {
+		for _, s := range strings.Split(historyState, ",") {
+			if s = github.NormalizeState(s); s != "" {
+				filter.States = append(filter.States, s)
+			}
+		}
+	}
+
+	var err error
+	if filter.Since, err = parseHistoryTime(historySince, now); err != nil {
+		return filter, fmt.Errorf("invalid --since value: %w", err)
+	}
+	if filter.Until, err = parseHistoryTime(historyUntil, now); err != nil {
+		return filter, fmt.Errorf("invalid --until value: %w", err)
+	}
+	if historyLimit < 0 {
+		return filter, fmt.Errorf("invalid --limit value %d (expected a non-negative integer)", historyLimit)
+	}
+	return filter, nil
+}
+
+// parseHistoryTime parses a point in time given as a duration before now (12h, 30m, 7d),
+// an RFC3339 timestamp, or a local date with optional time.
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration (12h, 7d) or a time (2006-01-02, RFC3339)", value)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+)
+
+func resetHistoryFlags() {
+	historySince, historyUntil, historyState, historyRepo = "", "", "", ""
+	historyPolls, historyJSON = false, false
+	historyLimit = 0
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "", want: time.Time{}},
+		{value: "12h", want: now.Add(-12 * time.Hour)},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2025-03-09T22:00:00Z", want: time.Date(2025, 3, 9, 22, 0, 0, 0, time.UTC)},
+		{value: "2025-03-09", want: time.Date(2025, 3, 9, 0, 0, 0, 0, time.Local)},
+		{value: "yesterday", wantErr: true},
+		{value: "-5h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseHistoryTime(tt.value, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+	defer resetHistoryFlags()
+
+	store, err := history.Open()
+	if err != nil {
+		t.Fatalf("failed to open history: %v", err)
+	}
+	now := time.Now()
+	events := []*notify.StatusChangeEvent{
+		{Owner: "owner", Repo: "repo", Number: 1, Title: "Old", PreviousState: "pending", CurrentState: "failure", Timestamp: now.Add(-48 * time.Hour)},
+		{Owner: "owner", Repo: "repo", Number: 1, Title: "Fix", PreviousState: "failure", CurrentState: "success", Timestamp: now.Add(-2 * time.Hour)},
+		{Owner: "owner", Repo: "other", Number: 2, Title: "Other", PreviousState: "pending", CurrentState: "failure", Timestamp: now.Add(-time.Hour)},
+	}
+	for _, e := range events {
+		if err := store.RecordEvent(e); err != nil {
+			t.Fatalf("RecordEvent failed: %v", err)
+		}
+	}
+
+	output, err := captureStdout(func() error {
+		return historyCmd.RunE(historyCmd, []string{})
+	})
+	if err != nil {
+		t.Fatalf("historyCmd.RunE() error = %v", err)
+	}
+	if !strings.Contains(output, "pending → failure") || !strings.Contains(output, "owner/other") {
+		t.Errorf("expected table with all events, got: %s", output)
+	}
+
+	historySince = "24h"
+	historyState = "failure"
+	historyJSON = true
+	output, err = captureStdout(func() error {
+		return historyCmd.RunE(historyCmd, []string{})
+	})
+	if err != nil {
+		t.Fatalf("historyCmd.RunE() error = %v", err)
+	}
+	var records []history.Record
+	if err := json.Unmarshal([]byte(output), &records); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	if len(records) != 1 || records[0].Repo != "other" {
+		t.Errorf("expected only the recent failure, got %+v", records)
+	}
+
+	resetHistoryFlags()
+	historyJSON = true
+	output, err = captureStdout(func() error {
+		return historyCmd.RunE(historyCmd, []string{"https://github.com/owner/repo/pull/1"})
+	})
+	if err != nil {
+		t.Fatalf("historyCmd.RunE() error = %v", err)
+	}
+	records = nil
+	if err := json.Unmarshal([]byte(output), &records); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("expected both events of the PR, got %+v", records)
+	}
+}
+
+func TestHistoryCmd_InvalidFlags(t *testing.T) {
+	defer resetHistoryFlags()
+
+	historyRepo = "a/b/c"
+	if err := historyCmd.RunE(historyCmd, []string{}); err == nil {
+		t.Error("expected error for invalid --repo")
+	}
+
+	resetHistoryFlags()
+	historySince = "last week"
+	if err := historyCmd.RunE(historyCmd, []string{}); err == nil {
+		t.Error("expected error for invalid --since")
+	}
+
+	resetHistoryFlags()
+	if err := historyCmd.RunE(historyCmd, []string{"not a url"}); err == nil {
+		t.Error("expected error for invalid PR URL")
+	}
+
+	resetHistoryFlags()
+	historyRepo = "other/repo"
+	if err := historyCmd.RunE(historyCmd, []string{"https://github.com/owner/repo/pull/1"}); err == nil || !strings.Contains(err.Error(), "--repo can't be combined with a PR URL") {
+		t.Errorf("expected error for a PR URL with --repo, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/watcher"
+)
+
+var (
+	logsLines int
+	logsSave  string
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().IntVar(&logsLines, "lines", 30, "number of log lines to show per failed job")
+	logsCmd.Flags().StringVar(&logsSave, "save", "", "directory to save the full job logs to")
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <PR_URL>",
+	Short: "Show the logs of a PR's failing CI jobs",
+	Long: `Find the failed checks of a PR's head commit and print the end of each failed
+GitHub Actions job log, up to the last error. Logs of other CI systems are not
+available through the GitHub API; their links are shown instead.
+
+Use --save to write the full logs to a directory.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if logsLines <= 0 {
+			return fmt.Errorf("--lines must be a positive integer")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		pr, client, err := resolvePR(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		ghPR, err := client.GetPullRequest(pr.Owner, pr.Repo, pr.Number)
+		if err != nil {
+			return fmt.Errorf("failed to fetch PR: %w", err)
+		}
+		sha := ghPR.Head.SHA
+
+		state, checks, err := watcher.CommitState(client, pr.Owner, pr.Repo, sha)
+		if err != nil {
+			return err
+		}
+		label := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+		if state != "failure" && state != "error" {
+			fmt.Printf("No failing checks for %s (state: %s).\n", label, state)
+			return nil
+		}
+		fmt.Printf("Failing checks for %s at %s:\n", label, shortSHA(sha))
+
+		for _, check := range checks {
+			if check.Source == github.CheckSourceStatus && (check.State == "failure" || check.State == "error") {
+				fmt.Printf("\n✗ %s (%s)\n", check.Name, check.State)
+				printLink(check.URL)
+				fmt.Println("  Logs of commit statuses are not available through the GitHub API.")
+			}
+		}
+
+		jobs, err := watcher.FailedJobs(client, pr.Owner, pr.Repo, sha, 0)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			fmt.Printf("\n✗ %s (%s)\n", job.Check.Name, job.Check.State)
+			if job.Job != nil {
+				if step := job.Job.FailedStep(); step != "" {
+					fmt.Printf("  Failed step: %s\n", step)
+				}
+			}
+			printLink(job.Check.URL)
+
+			switch {
+			case job.Err != nil:
+				fmt.Printf("  Could not fetch the log: %v\n", job.Err)
+				continue
+			case job.Log == nil:
+				fmt.Println("  Not a GitHub Actions job; open the link for its logs.")
+				continue
+			}
+
+			for _, line := range github.LogExcerpt(job.Log, logsLines) {
+				fmt.Printf("  | %s\n", line)
+			}
+			if logsSave != "" {
+				path, err := saveJobLog(logsSave, pr, job)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("  Full log saved to %s\n", path)
+			}
+		}
+		return nil
+	},
+}
+
+func printLink(url string) {
+	if url != "" {
+		fmt.Printf("  %s\n", url)
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// saveJobLog writes the full log of a failed job to dir and returns the file path.
+func saveJobLog(dir string, pr config.WatchedPR, job watcher.FailedJob) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(job.Check.Name, "-"), "-")
+	file := fmt.Sprintf("%s-%s-%d-%s-%d.log", pr.Owner, pr.Repo, pr.Number, name, job.Job.ID)
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, job.Log, 0644); err != nil {
+		return "", fmt.Errorf("failed to save log: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+func TestLogsCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			fmt.Fprint(w, `{"number": 1, "title": "Broken", "state": "open", "head": {"sha": "abcdef123456"}}`)
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			fmt.Fprint(w, `{"state": "failure", "statuses": [{"context": "ci/jenkins", "state": "failure", "target_url": "https://ci.example.com/9"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprint(w, `{"check_suites": []}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprint(w, `{"check_runs": [
+				{"id": 11, "name": "unit tests", "status": "completed", "conclusion": "failure", "html_url": "https://github.com/owner/repo/runs/11", "app": {"slug": "github-actions"}},
+				{"id": 12, "name": "build", "status": "completed", "conclusion": "success", "app": {"slug": "github-actions"}},
+				{"id": 13, "name": "codecov", "status": "completed", "conclusion": "failure", "app": {"slug": "codecov"}}
+			]}`)
+		case strings.HasSuffix(r.URL.Path, "/actions/jobs/11"):
+			fmt.Fprint(w, `{"id": 11, "name": "unit tests", "steps": [{"number": 1, "name": "Checkout", "conclusion": "success"}, {"number": 2, "name": "Test", "conclusion": "failure"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/actions/jobs/11/logs"):
+			http.Redirect(w, r, server.URL+"/download/11", http.StatusFound)
+		case r.URL.Path == "/download/11":
+			fmt.Fprint(w, "2025-01-15T10:00:00.0000000Z go test ./...\n2025-01-15T10:00:01.0000000Z --- FAIL: TestParse\n2025-01-15T10:00:02.0000000Z ##[error]Process completed with exit code 1.\n2025-01-15T10:00:03.0000000Z Cleaning up\n")
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	oldNewGitHubClient := newGitHubClient
+	defer func() { newGitHubClient = oldNewGitHubClient }()
+	newGitHubClient = func(host, token string) *github.Client {
+		client := github.NewClient(token)
+		client.BaseURL = server.URL
+		client.HTTPClient = server.Client()
+		return client
+	}
+
+	saveDir := filepath.Join(tmpDir, "logs")
+	logsLines, logsSave = 2, saveDir
+	defer func() { logsLines, logsSave = 30, "" }()
+
+	output, err := captureStdout(func() error {
+		return logsCmd.RunE(logsCmd, []string{"https://github.com/owner/repo/pull/1"})
+	})
+	if err != nil {
+		t.Fatalf("logsCmd.RunE() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"Failing checks for owner/repo#1 at abcdef1",
+		"✗ ci/jenkins (failure)",
+		"✗ unit tests (failure)",
+		"Failed step: Test",
+		"| --- FAIL: TestParse",
+		"| ##[error]Process completed with exit code 1.",
+		"✗ codecov (failure)",
+		"Not a GitHub Actions job",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "go test ./...") || strings.Contains(output, "build") {
+		t.Errorf("expected only the last 2 lines of failing jobs, got:\n%s", output)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(saveDir, "owner-repo-1-unit-tests-11.log"))
+	if err != nil {
+		t.Fatalf("expected the full log to be saved: %v", err)
+	}
+	if !strings.Contains(string(saved), "Cleaning up") {
+		t.Errorf("expected the full log, got %q", saved)
+	}
+}
@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/mail"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 	"text/tabwriter"
 	"time"
@@ -15,6 +19,7 @@ import (
 
 	"github.com/devblac/prw/internal/config"
 	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
 	"github.com/devblac/prw/internal/notify"
 	"github.com/devblac/prw/internal/version"
 	"github.com/devblac/prw/internal/watcher"
@@ -22,10 +27,29 @@ import (
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError is returned by commands whose exit code carries meaning, such as
+// 'prw wait'.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "prw",
 	Short: "Pull Request Watcher - monitor GitHub PR status changes",
@@ -46,9 +70,16 @@ func init() {
 	rootCmd.AddCommand(completionCmd)
 
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "output watched PRs as JSON")
-	runCmd.Flags().StringVar(&notifyFilter, "on", "", "notify on: change, fail, or success")
+	runCmd.Flags().StringVar(&notifyFilter, "on", "", "notify on: change, fail, success, or review")
 	runCmd.Flags().BoolVar(&runOnce, "once", false, "check watched PRs once and exit")
 	runCmd.Flags().BoolVar(&notifyNative, "notify-native", false, "enable native OS notifications (macOS/Linux/Windows)")
+	runCmd.Flags().IntVar(&runConcurrency, "concurrency", 0, "maximum number of PRs to check in parallel")
+	runCmd.Flags().StringVar(&runListen, "listen", "", "receive GitHub webhooks on this address (e.g. :8787) instead of polling")
+	runCmd.Flags().DurationVar(&runFallback, "fallback-interval", 10*time.Minute, "with --listen, poll all PRs this often to catch missed deliveries (0 disables)")
+	configSetCmd.Flags().StringVar(&configHost, "host", "", "GitHub Enterprise Server host the github_token applies to")
+	configUnsetCmd.Flags().StringVar(&configHost, "host", "", "GitHub Enterprise Server host the github_token applies to")
+	configSetCmd.Flags().StringVar(&configRepo, "repo", "", "owner/repo or owner/* the merge_rule or stuck_after_minutes applies to (default: all repos)")
+	configUnsetCmd.Flags().StringVar(&configRepo, "repo", "", "owner/repo or owner/* the merge_rule or stuck_after_minutes applies to (default: all repos)")
 }
 
 var (
@@ -56,10 +87,333 @@ var (
 	notifyFilter string
 	runOnce      bool
 	notifyNative bool
+
+	runConcurrency int
+	runListen      string
+	runFallback    time.Duration
+	configHost     string
+	configRepo     string
 )
 
 // newGitHubClient allows tests to inject a custom GitHub client.
-var newGitHubClient = github.NewClient
+var newGitHubClient = github.NewHostClient
+
+// newConfiguredClient creates a GitHub client for host with the retry settings from cfg.
+func newConfiguredClient(cfg *config.Config, host, token string) *github.Client {
+	client := newGitHubClient(host, token)
+	client.Retry = cfg.RetryPolicy()
+	return client
+}
+
+// hostToken returns the token for host or an error explaining how to configure it.
+func hostToken(cfg *config.Config, host string) (string, error) {
+	host = github.NormalizeHost(host)
+	if token := cfg.TokenForHost(host); token != "" {
+		return token, nil
+	}
+	if host == github.DefaultHost {
+		return "", fmt.Errorf("missing GITHUB_TOKEN; set it as an environment variable or configure it with 'prw config set github_token <token>'")
+	}
+	return "", fmt.Errorf("missing token for %s; configure it with 'prw config set github_token <token> --host %s'", host, host)
+}
+
+// newHostClients creates a GitHub client for every host with watched PRs.
+func newHostClients(cfg *config.Config) (map[string]*github.Client, error) {
+	clients := make(map[string]*github.Client)
+	for _, host := range cfg.WatchedHosts() {
+		token, err := hostToken(cfg, host)
+		if err != nil {
+			return nil, err
+		}
+		clients[host] = newConfiguredClient(cfg, host, token)
+	}
+	return clients, nil
+}
+
+// newWebhookNotifier creates a notifier for a webhook URL in the given format,
+// detected from the URL when empty or auto, with the retry settings from cfg.
+func newWebhookNotifier(cfg *config.Config, url, format string) notify.Notifier {
+	switch notify.ResolveWebhookFormat(url, format) {
+	case notify.WebhookFormatSlack:
+		notifier := notify.NewSlackWebhookNotifier(url)
+		notifier.Retry = cfg.RetryPolicy()
+		notifier.Template = messageTemplate(cfg, notify.TemplateSlack)
+		return notifier
+	case notify.WebhookFormatDiscord:
+		notifier := notify.NewDiscordNotifier(url)
+		notifier.Retry = cfg.RetryPolicy()
+		notifier.Template = messageTemplate(cfg, notify.TemplateWebhook)
+		return notifier
+	case notify.WebhookFormatTeams:
+		notifier := notify.NewTeamsNotifier(url)
+		notifier.Retry = cfg.RetryPolicy()
+		notifier.Template = messageTemplate(cfg, notify.TemplateWebhook)
+		return notifier
+	default:
+		notifier := notify.NewWebhookNotifier(url)
+		notifier.Retry = cfg.RetryPolicy()
+		notifier.Template = messageTemplate(cfg, notify.TemplateWebhook)
+		return notifier
+	}
+}
+
+// newSlackNotifier returns the configured Slack notifier, preferring the bot
+// token over the incoming webhook, or nil if Slack is not configured.
+func newSlackNotifier(cfg *config.Config) *notify.SlackNotifier {
+	var notifier *notify.SlackNotifier
+	switch {
+	case cfg.SlackBotToken != "" && cfg.SlackChannel != "":
+		notifier = notify.NewSlackBotNotifier(cfg.SlackBotToken, cfg.SlackChannel)
+		notifier.Threads = slackThreads()
+	case cfg.SlackWebhookURL != "":
+		notifier = notify.NewSlackWebhookNotifier(cfg.SlackWebhookURL)
+	default:
+		return nil
+	}
+	notifier.Retry = cfg.RetryPolicy()
+	notifier.Template = messageTemplate(cfg, notify.TemplateSlack)
+	return notifier
+}
+
+// slackThreads returns the store that keeps Slack threads across restarts, or
+// nil, keeping them in memory only, if its path can't be determined.
+func slackThreads() notify.SlackThreadStore {
+	store, err := history.OpenSlackThreads()
+	if err != nil {
+		fmt.Printf("Warning: Slack threads won't survive a restart: %v\n", err)
+		return nil
+	}
+	return store
+}
+
+// heldEventsOutbound is the key under which the events held by the quiet hours
+// of the outbound notifiers are kept. Routed notifiers use their names.
+const heldEventsOutbound = "outbound"
+
+// newQuietNotifier holds the events of next during quiet hours. Held events are
+// kept under key next to the history file, so that those left when prw exits
+// are delivered after the next start.
+func newQuietNotifier(next notify.Notifier, quiet *config.QuietHours, key string) *notify.QuietNotifier {
+	notifier := notify.NewQuietNotifier(next, quiet, quiet.AllowFailures)
+	store, err := history.OpenHeldEvents(key)
+	if err != nil {
+		fmt.Printf("Warning: notifications held for quiet hours won't survive a restart: %v\n", err)
+		return notifier
+	}
+	notifier.Store = store
+	if err := notifier.Restore(); err != nil {
+		fmt.Printf("Warning: failed to restore notifications held for quiet hours: %v\n", err)
+	}
+	return notifier
+}
+
+// messageTemplate parses the configured templates of a notifier. Invalid
+// templates are reported and ignored so that notifications still go out.
+func messageTemplate(cfg *config.Config, notifier string) *notify.MessageTemplate {
+	tmpl, err := notify.NewMessageTemplate(notifier, cfg.Templates[notifier])
+	if err != nil {
+		fmt.Printf("Warning: %v; using the built-in %s messages\n", err, notifier)
+		return nil
+	}
+	return tmpl
+}
+
+// resolvePR parses a PR URL, checks that its host is configured, and fetches the
+// PR to validate that it exists. It returns the PR ready to be watched and the
+// client for its host.
+func resolvePR(cfg *config.Config, prURL string) (config.WatchedPR, *github.Client, error) {
+	host, owner, repo, number, err := github.ParseHostPRURL(prURL)
+	if err != nil {
+		return config.WatchedPR{}, nil, fmt.Errorf("invalid PR URL: %w", err)
+	}
+
+	if !cfg.IsConfiguredHost(host) {
+		return config.WatchedPR{}, nil, fmt.Errorf("unknown GitHub host %s; add it with 'prw config set github_token <token> --host %s'", host, host)
+	}
+	token, err := hostToken(cfg, host)
+	if err != nil {
+		return config.WatchedPR{}, nil, err
+	}
+
+	// Try to fetch the PR to validate it exists and get title
+	client := newConfiguredClient(cfg, host, token)
+	pr, err := client.GetPullRequest(owner, repo, number)
+	if err != nil {
+		return config.WatchedPR{}, nil, fmt.Errorf("failed to fetch PR: %w", err)
+	}
+
+	return config.WatchedPR{
+		Host:   host,
+		Owner:  owner,
+		Repo:   repo,
+		Number: number,
+		Title:  pr.Title,
+	}, client, nil
+}
+
+// newNotifier builds the notifier chain for long-running modes: console, then
+// the webhook and native notifications when configured. With routes, a router
+// picks the notifiers of each event instead; extra notifiers get every event.
+func newNotifier(cfg *config.Config, extra ...notify.Notifier) notify.Notifier {
+	console := notify.NewConsoleNotifier()
+	console.Template = messageTemplate(cfg, notify.TemplateConsole)
+	return notifierChain(cfg, console, extra...)
+}
+
+// notifierChain is newNotifier with the console notifier given; it is nil when
+// a dashboard replaces console output, and routes to the console then deliver
+// nothing.
+func notifierChain(cfg *config.Config, console notify.Notifier, extra ...notify.Notifier) notify.Notifier {
+	var notifiers []notify.Notifier
+	if len(cfg.Routes) > 0 {
+		notifiers = append(notifiers, newRouter(cfg, console))
+		return notify.NewMultiNotifier(append(notifiers, extra...)...)
+	}
+	if console != nil {
+		notifiers = append(notifiers, console)
+	}
+	notifiers = append(notifiers, extra...)
+	notifiers = append(notifiers, outboundNotifiers(cfg)...)
+	return notify.NewMultiNotifier(notifiers...)
+}
+
+// outboundNotifiers returns the configured notifiers that deliver outside the terminal.
+func outboundNotifiers(cfg *config.Config) []notify.Notifier {
+	var notifiers []notify.Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, newWebhookNotifier(cfg, cfg.WebhookURL, cfg.WebhookFormat))
+	}
+	if slack := newSlackNotifier(cfg); slack != nil {
+		notifiers = append(notifiers, slack)
+	}
+	if cfg.Email.Enabled() {
+		notifiers = append(notifiers, newEmailNotifier(cfg, cfg.Email))
+	}
+	// Add native notifications if enabled via flag or config
+	if notifyNative || cfg.NotificationNative {
+		notifiers = append(notifiers, newNativeNotifier(cfg))
+	}
+	// Hold outbound notifications during quiet hours; console output is unaffected
+	if quiet := cfg.QuietHours; quiet != nil && len(quiet.Windows) > 0 && len(notifiers) > 0 {
+		next := notify.NewMultiNotifier(notifiers...)
+		return []notify.Notifier{newQuietNotifier(next, quiet, heldEventsOutbound)}
+	}
+	return notifiers
+}
+
+// newEmailNotifier creates an email notifier from the email settings.
+func newEmailNotifier(cfg *config.Config, email *config.EmailConfig) *notify.EmailNotifier {
+	notifier := notify.NewEmailNotifier(email.SMTPHost, email.SMTPPort, email.From, email.To)
+	if email.SMTPSecurity != "" {
+		notifier.Security = email.SMTPSecurity
+	}
+	notifier.Username = email.SMTPUsername
+	notifier.Password = email.SMTPPasswordOrEnv()
+	notifier.Digest = time.Duration(email.DigestMinutes) * time.Minute
+	notifier.Template = messageTemplate(cfg, notify.TemplateEmail)
+	return notifier
+}
+
+// newNativeNotifier creates a native notifier with the native templates.
+func newNativeNotifier(cfg *config.Config) *notify.NativeNotifier {
+	notifier := notify.NewNativeNotifier()
+	notifier.Template = messageTemplate(cfg, notify.TemplateNative)
+	return notifier
+}
+
+// emailSettings returns the email settings of cfg, creating them if needed.
+func emailSettings(cfg *config.Config) *config.EmailConfig {
+	if cfg.Email == nil {
+		cfg.Email = &config.EmailConfig{}
+	}
+	return cfg.Email
+}
+
+// parseEmailAddresses parses a comma-separated list of email addresses.
+func parseEmailAddresses(value string) ([]string, error) {
+	addrs, err := mail.ParseAddressList(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email address list %q: %w", value, err)
+	}
+	list := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if addr.Name == "" {
+			list = append(list, addr.Address)
+		} else {
+			list = append(list, addr.String())
+		}
+	}
+	return list, nil
+}
+
+// quietHoursSettings returns the quiet hours settings of cfg, creating them if needed.
+func quietHoursSettings(cfg *config.Config) *config.QuietHours {
+	if cfg.QuietHours == nil {
+		cfg.QuietHours = &config.QuietHours{}
+	}
+	return cfg.QuietHours
+}
+
+// pruneQuietHours drops quiet hours settings that no longer hold anything.
+func pruneQuietHours(cfg *config.Config) {
+	if q := cfg.QuietHours; q != nil && len(q.Windows) == 0 && q.Timezone == "" && !q.AllowFailures {
+		cfg.QuietHours = nil
+	}
+}
+
+// pruneEmail removes the email settings once nothing is left in them.
+func pruneEmail(cfg *config.Config) {
+	if e := cfg.Email; e != nil && e.SMTPHost == "" && e.SMTPPort == 0 && e.SMTPSecurity == "" &&
+		e.SMTPUsername == "" && e.SMTPPassword == "" && e.From == "" && len(e.To) == 0 && e.DigestMinutes == 0 {
+		cfg.Email = nil
+	}
+}
+
+// newWatcher creates a watcher with a client for every watched host, the event
+// history, and the check stats.
+func newWatcher(cfg *config.Config, notifier notify.Notifier) (*watcher.Watcher, error) {
+	clients, err := newHostClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	w := watcher.New(nil, cfg, notifier)
+	for host, client := range clients {
+		w.SetClient(host, client)
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	w.SetHistory(store, cfg.HistoryPollResults)
+
+	stats, err := history.OpenCheckStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open check stats: %w", err)
+	}
+	w.SetCheckStats(stats)
+	return w, nil
+}
+
+// signalContext returns a context that is cancelled on Ctrl+C or SIGTERM.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigCh)
+	}()
+
+	return ctx, cancel
+}
 
 var watchCmd = &cobra.Command{
 	Use:   "watch <PR_URL>",
@@ -68,36 +422,21 @@ var watchCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		prURL := args[0]
 
-		owner, repo, number, err := github.ParsePRURL(prURL)
-		if err != nil {
-			return fmt.Errorf("invalid PR URL: %w", err)
-		}
-
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		token := cfg.GetToken()
-		if token == "" {
-			return fmt.Errorf("missing GITHUB_TOKEN; set it as an environment variable or configure it with 'prw config set github_token <token>'")
-		}
-
-		// Try to fetch the PR to validate it exists and get title
-		client := newGitHubClient(token)
-		pr, err := client.GetPullRequest(owner, repo, number)
+		watchedPR, _, err := resolvePR(cfg, prURL)
 		if err != nil {
-			return fmt.Errorf("failed to fetch PR: %w", err)
-		}
-
-		watchedPR := config.WatchedPR{
-			Owner:  owner,
-			Repo:   repo,
-			Number: number,
-			Title:  pr.Title,
+			return err
 		}
+		owner, repo, number := watchedPR.Owner, watchedPR.Repo, watchedPR.Number
 
-		if !cfg.AddPR(watchedPR) {
+		if existing := cfg.FindPR(watchedPR.Host, owner, repo, number); existing != nil && existing.Subscription != "" {
+			// Keep watching the PR after it stops matching the subscription
+			existing.Subscription = ""
+		} else if !cfg.AddPR(watchedPR) {
 			fmt.Printf("PR %s/%s#%d is already being watched.\n", owner, repo, number)
 			return nil
 		}
@@ -106,7 +445,7 @@ var watchCmd = &cobra.Command{
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
-		fmt.Printf("Now watching: %s/%s#%d - %s\n", owner, repo, number, pr.Title)
+		fmt.Printf("Now watching: %s/%s#%d - %s\n", owner, repo, number, watchedPR.Title)
 		return nil
 	},
 }
@@ -130,16 +469,30 @@ var listCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "REPO\tPR\tSTATUS\tLAST CHECKED\tTITLE")
-		fmt.Fprintln(w, "----\t--\t------\t------------\t-----")
+		fmt.Fprintln(w, "REPO\tPR\tSTATUS\tREVIEW\tLAST CHECKED\tTITLE")
+		fmt.Fprintln(w, "----\t--\t------\t------\t------------\t-----")
 
 		for _, pr := range cfg.WatchedPRs {
 			repo := fmt.Sprintf("%s/%s", pr.Owner, pr.Repo)
+			if host := pr.HostName(); host != github.DefaultHost {
+				repo = host + "/" + repo
+			}
 			prNum := fmt.Sprintf("#%d", pr.Number)
 			status := pr.LastKnownState
 			if status == "" {
 				status = "unknown"
 			}
+			if pr.IsClosed() {
+				status = pr.PRState
+			} else if n := len(pr.Reruns); n == 1 {
+				status += " (1 rerun)"
+			} else if n > 1 {
+				status += fmt.Sprintf(" (%d reruns)", n)
+			}
+			review := pr.ReviewDecision
+			if review == "" {
+				review = "-"
+			}
 			lastChecked := "never"
 			if !pr.LastChecked.IsZero() {
 				lastChecked = pr.LastChecked.Format("2006-01-02 15:04")
@@ -148,7 +501,7 @@ var listCmd = &cobra.Command{
 			if len(title) > 50 {
 				title = title[:47] + "..."
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", repo, prNum, status, lastChecked, title)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", repo, prNum, status, review, lastChecked, title)
 		}
 
 		w.Flush()
@@ -163,7 +516,7 @@ var unwatchCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		prURL := args[0]
 
-		owner, repo, number, err := github.ParsePRURL(prURL)
+		host, owner, repo, number, err := github.ParseHostPRURL(prURL)
 		if err != nil {
 			return fmt.Errorf("invalid PR URL: %w", err)
 		}
@@ -173,16 +526,22 @@ var unwatchCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		if !cfg.RemovePR(owner, repo, number) {
+		pr := cfg.FindPR(host, owner, repo, number)
+		if pr == nil {
 			fmt.Printf("PR %s/%s#%d is not being watched.\n", owner, repo, number)
 			return nil
 		}
+		subscription := pr.Subscription
+		cfg.RemovePR(host, owner, repo, number)
 
 		if err := cfg.Save(); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
 		fmt.Printf("Stopped watching: %s/%s#%d\n", owner, repo, number)
+		if subscription != "" {
+			fmt.Printf("Note: it still matches subscription %s and will be watched again; use 'prw unsubscribe' to stop.\n", subscription)
+		}
 		return nil
 	},
 }
@@ -192,6 +551,11 @@ var runCmd = &cobra.Command{
 	Short: "Start the watcher loop",
 	Long: `Start monitoring all watched PRs for status changes.
 Polls GitHub API on the configured interval and notifies on changes.
+
+With --listen, prw receives GitHub webhook deliveries (status, check_run,
+check_suite, pull_request, pull_request_review) at POST /webhook and only
+checks the PRs they concern; polling every --fallback-interval reconciles
+missed deliveries.
 Press Ctrl+C to stop.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load()
@@ -199,50 +563,35 @@ Press Ctrl+C to stop.`,
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		token := cfg.GetToken()
-		if token == "" {
-			return fmt.Errorf("missing GITHUB_TOKEN; set it as an environment variable or configure it with 'prw config set github_token <token>'")
-		}
-
-		client := newGitHubClient(token)
-
-		// Build notifier chain
-		notifiers := []notify.Notifier{notify.NewConsoleNotifier()}
-		if cfg.WebhookURL != "" {
-			notifiers = append(notifiers, notify.NewWebhookNotifier(cfg.WebhookURL))
-		}
-		// Add native notifications if enabled via flag or config
-		if notifyNative || cfg.NotificationNative {
-			notifiers = append(notifiers, notify.NewNativeNotifier())
+		w, err := newWatcher(cfg, newNotifier(cfg))
+		if err != nil {
+			return err
 		}
-		notifier := notify.NewMultiNotifier(notifiers...)
 
 		filter := cfg.NotificationFilter
 		if notifyFilter != "" {
 			filter = config.NormalizeNotificationFilter(notifyFilter)
 			if !config.IsValidNotificationFilter(filter) {
-				return fmt.Errorf("invalid --on value %q (expected change, fail, or success)", notifyFilter)
+				return fmt.Errorf("invalid --on value %q (expected change, fail, success, or review)", notifyFilter)
 			}
 		}
 		cfg.NotificationFilter = filter
 
-		w := watcher.New(client, cfg, notifier)
+		if runConcurrency < 0 {
+			return fmt.Errorf("invalid --concurrency value %d (expected a positive integer)", runConcurrency)
+		}
+		// The flag only applies to this run; checkPRs saves the config every cycle
+		w.SetConcurrency(runConcurrency)
 
-		// Setup signal handling
-		ctx, cancel := context.WithCancel(context.Background())
+		ctx, cancel := signalContext()
 		defer cancel()
 
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-		go func() {
-			<-sigCh
-			cancel()
-		}()
-
 		if runOnce {
 			return w.RunOnce(ctx)
 		}
+		if runListen != "" {
+			return runWebhookReceiver(ctx, cfg, w)
+		}
 
 		return w.Run(ctx)
 	},
@@ -269,8 +618,99 @@ var configShowCmd = &cobra.Command{
 		fmt.Printf("Config file: %s\n\n", path)
 		fmt.Printf("poll_interval_seconds: %d\n", cfg.PollIntervalSeconds)
 		fmt.Printf("webhook_url: %s\n", cfg.WebhookURL)
+		webhookFormat := cfg.WebhookFormat
+		if webhookFormat == "" {
+			webhookFormat = notify.WebhookFormatAuto
+		}
+		if cfg.WebhookURL != "" && webhookFormat == notify.WebhookFormatAuto {
+			webhookFormat += " (" + notify.ResolveWebhookFormat(cfg.WebhookURL, "") + ")"
+		}
+		fmt.Printf("webhook_format: %s\n", webhookFormat)
+		fmt.Printf("slack_webhook_url: %s\n", cfg.SlackWebhookURL)
+		slackToken := "not set"
+		if cfg.SlackBotToken != "" {
+			slackToken = "config file"
+		}
+		fmt.Printf("slack_bot_token: %s\n", slackToken)
+		fmt.Printf("slack_channel: %s\n", cfg.SlackChannel)
+		email := cfg.Email
+		if email == nil {
+			email = &config.EmailConfig{}
+		}
+		fmt.Printf("email_smtp_host: %s\n", email.SMTPHost)
+		security := email.SMTPSecurity
+		if security == "" {
+			security = notify.EmailSecurityStartTLS
+		}
+		port := email.SMTPPort
+		if port == 0 {
+			port = notify.DefaultSMTPPort(security)
+		}
+		fmt.Printf("email_smtp_port: %d\n", port)
+		fmt.Printf("email_smtp_security: %s\n", security)
+		fmt.Printf("email_smtp_username: %s\n", email.SMTPUsername)
+		smtpPassword := "not set"
+		if email.SMTPPassword != "" {
+			smtpPassword = "config file"
+		} else if os.Getenv("PRW_SMTP_PASSWORD") != "" {
+			smtpPassword = "environment variable"
+		}
+		fmt.Printf("email_smtp_password: %s\n", smtpPassword)
+		fmt.Printf("email_from: %s\n", email.From)
+		fmt.Printf("email_to: %s\n", strings.Join(email.To, ", "))
+		fmt.Printf("email_digest_minutes: %d\n", email.DigestMinutes)
 		fmt.Printf("notification_filter: %s\n", cfg.NotificationFilter)
 		fmt.Printf("notification_native: %v\n", cfg.NotificationNative)
+		fmt.Printf("max_concurrency: %d\n", cfg.MaxConcurrency)
+		quiet := cfg.QuietHours
+		if quiet == nil {
+			quiet = &config.QuietHours{}
+		}
+		fmt.Printf("quiet_hours: %s\n", config.FormatQuietWindows(quiet.Windows))
+		quietTimezone := quiet.Timezone
+		if quietTimezone == "" {
+			quietTimezone = "local"
+		}
+		fmt.Printf("quiet_hours_timezone: %s\n", quietTimezone)
+		fmt.Printf("quiet_hours_allow_failures: %v\n", quiet.AllowFailures)
+		retryCfg := cfg.Retry
+		if retryCfg == nil {
+			retryCfg = config.DefaultRetryConfig()
+		}
+		fmt.Printf("retry_max_attempts: %d\n", retryCfg.MaxAttempts)
+		fmt.Printf("retry_base_delay_ms: %d\n", retryCfg.BaseDelayMS)
+		fmt.Printf("retry_max_delay_ms: %d\n", retryCfg.MaxDelayMS)
+		fmt.Printf("retry_jitter: %g\n", retryCfg.Jitter)
+		fmt.Printf("history_poll_results: %v\n", cfg.HistoryPollResults)
+		fmt.Printf("log_excerpt_lines: %d\n", cfg.LogExcerptLines)
+		flakyChecks := "none"
+		if len(cfg.FlakyChecks) > 0 {
+			flakyChecks = strings.Join(cfg.FlakyChecks, ", ")
+		}
+		fmt.Printf("flaky_checks: %s\n", flakyChecks)
+		fmt.Printf("flaky_max_reruns: %d\n", cfg.FlakyRerunLimit())
+		fmt.Printf("subscription_interval_seconds: %d\n", int(cfg.SubscriptionInterval().Seconds()))
+		webhookSecret := "not set"
+		if cfg.GitHubWebhookSecret != "" {
+			webhookSecret = "config file"
+		}
+		fmt.Printf("github_webhook_secret: %s\n", webhookSecret)
+		fmt.Printf("closed_pr_policy: %s\n", cfg.ClosedPRPolicy)
+		if cfg.ClosedPRPolicy == config.ClosedPRPolicyUnwatchDays {
+			fmt.Printf("closed_pr_unwatch_days: %d\n", cfg.ClosedPRUnwatchDays)
+		}
+		if _, ok := cfg.MergeRules[config.DefaultMergeRuleKey]; !ok {
+			fmt.Printf("merge_rule: %s (default)\n", formatMergeRule(config.DefaultMergeRule()))
+		}
+		for _, key := range cfg.MergeRuleKeys() {
+			fmt.Printf("merge_rule (%s): %s\n", key, formatMergeRule(cfg.MergeRules[key]))
+		}
+		if _, ok := cfg.StuckAfterMinutes[config.DefaultMergeRuleKey]; !ok {
+			fmt.Printf("stuck_after_minutes: 0 (disabled)\n")
+		}
+		for _, key := range cfg.StuckThresholdKeys() {
+			fmt.Printf("stuck_after_minutes (%s): %d\n", key, cfg.StuckAfterMinutes[key])
+		}
 
 		tokenSource := "not set"
 		if cfg.GitHubToken != "" {
@@ -280,6 +720,19 @@ var configShowCmd = &cobra.Command{
 		}
 		fmt.Printf("github_token: %s\n", tokenSource)
 
+		hosts := make([]string, 0, len(cfg.Hosts))
+		for host := range cfg.Hosts {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			source := "not set"
+			if cfg.Hosts[host].Token != "" {
+				source = "config file"
+			}
+			fmt.Printf("github_token (%s): %s\n", host, source)
+		}
+
 		fmt.Printf("\nWatched PRs: %d\n", len(cfg.WatchedPRs))
 
 		return nil
@@ -293,9 +746,47 @@ var configSetCmd = &cobra.Command{
 Supported keys:
   - poll_interval_seconds: polling interval in seconds (default: 20)
   - webhook_url: URL to POST notifications to
-  - github_token: GitHub personal access token
-  - notification_filter: change, fail, or success
-  - notification_native: enable native OS notifications (true/false)`,
+  - webhook_format: payload posted to webhook_url: json, slack, discord, teams, or auto
+    to detect it from the URL (default: auto)
+  - slack_webhook_url: Slack incoming webhook URL for Block Kit notifications
+  - slack_bot_token: Slack bot token (chat:write) to post to slack_channel and thread
+    later events of a PR under its first message
+  - slack_channel: Slack channel name or ID used with slack_bot_token
+  - github_token: GitHub personal access token (use --host for GitHub Enterprise Server)
+  - github_webhook_secret: secret GitHub signs webhook deliveries with (prw run --listen)
+  - email_smtp_host, email_smtp_port: SMTP server for email notifications
+    (port default: 587, or 465 with tls and 25 with none)
+  - email_smtp_security: starttls (default), tls for implicit TLS, or none
+  - email_smtp_username, email_smtp_password: SMTP credentials (password may also
+    come from PRW_SMTP_PASSWORD)
+  - email_from: sender address, e.g. "prw <prw@example.com>"
+  - email_to: comma-separated recipient addresses
+  - email_digest_minutes: batch events into one email per window (0 sends one per event)
+  - notification_filter: change, fail, success, or review
+  - notification_native: enable native OS notifications (true/false)
+  - max_concurrency: maximum number of PRs checked in parallel (default: 4)
+  - quiet_hours: comma-separated windows during which webhook and native notifications
+    are held and then sent as one summary, e.g. "mon-fri 22:00-07:00, weekends 00:00-24:00"
+    (none disables)
+  - quiet_hours_timezone: IANA time zone of quiet_hours, e.g. Europe/Berlin (default: local)
+  - quiet_hours_allow_failures: deliver CI failures during quiet hours (true/false)
+  - retry_max_attempts: attempts for transient GitHub/webhook failures (default: 3)
+  - retry_base_delay_ms: first retry delay in milliseconds, doubled per retry (default: 500)
+  - retry_max_delay_ms: longest single wait in milliseconds, including Retry-After (default: 30000)
+  - retry_jitter: fraction of each delay that is randomized, 0-1 (default: 0.2)
+  - history_poll_results: record every poll result in the history, not just changes (true/false)
+  - log_excerpt_lines: lines of failed GitHub Actions job logs attached to failure notifications (0 disables)
+  - flaky_checks: comma-separated names or glob patterns of known flaky checks,
+    re-run automatically before a failure is notified (none disables)
+  - flaky_max_reruns: automatic reruns per flaky check and commit (default: 2)
+  - subscription_interval_seconds: how often each subscription's search runs (default: 120)
+  - closed_pr_policy: keep, unwatch, or unwatch_after_days for merged/closed PRs
+  - closed_pr_unwatch_days: days to keep merged/closed PRs with unwatch_after_days
+  - merge_rule: comma-separated requirements for ready-to-merge notifications
+    (checks, approval, mergeable, not_draft, up_to_date, protection, or none);
+    use --repo owner/repo or --repo owner/* to set a rule for specific repos
+  - stuck_after_minutes: minutes CI may stay pending before a stuck alert (0 disables);
+    use --repo owner/repo or --repo owner/* to set a threshold for specific repos`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -306,6 +797,13 @@ Supported keys:
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if configHost != "" && key != "github_token" {
+			return fmt.Errorf("--host only applies to github_token")
+		}
+		if configRepo != "" && key != "merge_rule" && key != "stuck_after_minutes" {
+			return fmt.Errorf("--repo only applies to merge_rule and stuck_after_minutes")
+		}
+
 		switch key {
 		case "poll_interval_seconds":
 			interval, err := strconv.Atoi(value)
@@ -315,12 +813,69 @@ Supported keys:
 			cfg.PollIntervalSeconds = interval
 		case "webhook_url":
 			cfg.WebhookURL = value
+		case "email_smtp_host":
+			emailSettings(cfg).SMTPHost = value
+		case "email_smtp_port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port <= 0 || port > 65535 {
+				return fmt.Errorf("email_smtp_port must be a port number")
+			}
+			emailSettings(cfg).SMTPPort = port
+		case "email_smtp_security":
+			security := strings.ToLower(value)
+			if !notify.IsValidEmailSecurity(security) {
+				return fmt.Errorf("email_smtp_security must be one of: starttls, tls, none")
+			}
+			emailSettings(cfg).SMTPSecurity = security
+		case "email_smtp_username":
+			emailSettings(cfg).SMTPUsername = value
+		case "email_smtp_password":
+			emailSettings(cfg).SMTPPassword = value
+		case "email_from":
+			addrs, err := parseEmailAddresses(value)
+			if err != nil {
+				return err
+			}
+			if len(addrs) != 1 {
+				return fmt.Errorf("email_from must be a single address")
+			}
+			emailSettings(cfg).From = addrs[0]
+		case "email_to":
+			addrs, err := parseEmailAddresses(value)
+			if err != nil {
+				return err
+			}
+			emailSettings(cfg).To = addrs
+		case "email_digest_minutes":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 {
+				return fmt.Errorf("email_digest_minutes must be a non-negative integer")
+			}
+			emailSettings(cfg).DigestMinutes = minutes
+		case "webhook_format":
+			format := strings.ToLower(strings.TrimSpace(value))
+			if !notify.IsValidWebhookFormat(format) {
+				return fmt.Errorf("webhook_format must be one of: auto, json, slack, discord, teams")
+			}
+			cfg.WebhookFormat = format
+		case "slack_webhook_url":
+			cfg.SlackWebhookURL = value
+		case "slack_bot_token":
+			cfg.SlackBotToken = value
+		case "slack_channel":
+			cfg.SlackChannel = value
 		case "github_token":
+			if configHost != "" {
+				cfg.SetHostToken(configHost, value)
+				break
+			}
 			cfg.GitHubToken = value
+		case "github_webhook_secret":
+			cfg.GitHubWebhookSecret = value
 		case "notification_filter":
 			filter := config.NormalizeNotificationFilter(value)
 			if !config.IsValidNotificationFilter(filter) {
-				return fmt.Errorf("notification_filter must be one of: change, fail, success")
+				return fmt.Errorf("notification_filter must be one of: change, fail, success, review")
 			}
 			cfg.NotificationFilter = filter
 		case "notification_native":
@@ -329,6 +884,118 @@ Supported keys:
 				return fmt.Errorf("notification_native must be true or false")
 			}
 			cfg.NotificationNative = enabled
+		case "max_concurrency":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("max_concurrency must be a positive integer")
+			}
+			cfg.MaxConcurrency = n
+		case "quiet_hours":
+			windows, err := config.ParseQuietWindows(value)
+			if err != nil {
+				return err
+			}
+			quietHoursSettings(cfg).Windows = windows
+			pruneQuietHours(cfg)
+		case "quiet_hours_timezone":
+			if err := config.ValidateTimezone(value); err != nil {
+				return err
+			}
+			quietHoursSettings(cfg).Timezone = value
+			pruneQuietHours(cfg)
+		case "quiet_hours_allow_failures":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("quiet_hours_allow_failures must be true or false")
+			}
+			quietHoursSettings(cfg).AllowFailures = enabled
+			pruneQuietHours(cfg)
+		case "retry_max_attempts", "retry_base_delay_ms", "retry_max_delay_ms":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || (key == "retry_max_attempts" && n == 0) {
+				return fmt.Errorf("%s must be a positive integer", key)
+			}
+			if cfg.Retry == nil {
+				cfg.Retry = config.DefaultRetryConfig()
+			}
+			switch key {
+			case "retry_max_attempts":
+				cfg.Retry.MaxAttempts = n
+			case "retry_base_delay_ms":
+				cfg.Retry.BaseDelayMS = n
+			case "retry_max_delay_ms":
+				cfg.Retry.MaxDelayMS = n
+			}
+		case "retry_jitter":
+			jitter, err := strconv.ParseFloat(value, 64)
+			if err != nil || jitter < 0 || jitter > 1 {
+				return fmt.Errorf("retry_jitter must be a number between 0 and 1")
+			}
+			if cfg.Retry == nil {
+				cfg.Retry = config.DefaultRetryConfig()
+			}
+			cfg.Retry.Jitter = jitter
+		case "history_poll_results":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("history_poll_results must be true or false")
+			}
+			cfg.HistoryPollResults = enabled
+		case "log_excerpt_lines":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("log_excerpt_lines must be a non-negative integer")
+			}
+			cfg.LogExcerptLines = n
+		case "flaky_checks":
+			checks, err := config.ParseFlakyChecks(value)
+			if err != nil {
+				return err
+			}
+			cfg.FlakyChecks = checks
+		case "flaky_max_reruns":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("flaky_max_reruns must be a positive integer")
+			}
+			cfg.FlakyMaxReruns = n
+		case "subscription_interval_seconds":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("subscription_interval_seconds must be a positive integer")
+			}
+			cfg.SubscriptionIntervalSeconds = n
+		case "closed_pr_policy":
+			if !config.IsValidClosedPRPolicy(value) {
+				return fmt.Errorf("closed_pr_policy must be one of: keep, unwatch, unwatch_after_days")
+			}
+			cfg.ClosedPRPolicy = config.NormalizeClosedPRPolicy(value)
+		case "closed_pr_unwatch_days":
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return fmt.Errorf("closed_pr_unwatch_days must be a non-negative integer")
+			}
+			cfg.ClosedPRUnwatchDays = days
+		case "merge_rule":
+			ruleKey, err := repoRuleKey()
+			if err != nil {
+				return err
+			}
+			requirements, err := config.ParseMergeRequirements(value)
+			if err != nil {
+				return err
+			}
+			cfg.SetMergeRule(ruleKey, requirements)
+		case "stuck_after_minutes":
+			ruleKey, err := repoRuleKey()
+			if err != nil {
+				return err
+			}
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 {
+				return fmt.Errorf("stuck_after_minutes must be a non-negative integer")
+			}
+			cfg.SetStuckThreshold(ruleKey, minutes)
 		default:
 			return fmt.Errorf("unknown config key: %s", key)
 		}
@@ -354,17 +1021,118 @@ var configUnsetCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if configHost != "" && key != "github_token" {
+			return fmt.Errorf("--host only applies to github_token")
+		}
+		if configRepo != "" && key != "merge_rule" && key != "stuck_after_minutes" {
+			return fmt.Errorf("--repo only applies to merge_rule and stuck_after_minutes")
+		}
+
 		switch key {
 		case "poll_interval_seconds":
 			cfg.PollIntervalSeconds = 20 // reset to default
 		case "webhook_url":
 			cfg.WebhookURL = ""
+		case "webhook_format":
+			cfg.WebhookFormat = ""
+		case "email_smtp_host", "email_smtp_port", "email_smtp_security", "email_smtp_username",
+			"email_smtp_password", "email_from", "email_to", "email_digest_minutes":
+			if cfg.Email != nil {
+				switch key {
+				case "email_smtp_host":
+					cfg.Email.SMTPHost = ""
+				case "email_smtp_port":
+					cfg.Email.SMTPPort = 0
+				case "email_smtp_security":
+					cfg.Email.SMTPSecurity = ""
+				case "email_smtp_username":
+					cfg.Email.SMTPUsername = ""
+				case "email_smtp_password":
+					cfg.Email.SMTPPassword = ""
+				case "email_from":
+					cfg.Email.From = ""
+				case "email_to":
+					cfg.Email.To = nil
+				case "email_digest_minutes":
+					cfg.Email.DigestMinutes = 0
+				}
+				pruneEmail(cfg)
+			}
+		case "slack_webhook_url":
+			cfg.SlackWebhookURL = ""
+		case "slack_bot_token":
+			cfg.SlackBotToken = ""
+		case "slack_channel":
+			cfg.SlackChannel = ""
 		case "github_token":
+			if configHost != "" {
+				cfg.RemoveHost(configHost)
+				break
+			}
 			cfg.GitHubToken = ""
+		case "github_webhook_secret":
+			cfg.GitHubWebhookSecret = ""
 		case "notification_filter":
 			cfg.NotificationFilter = config.NotificationFilterChange
 		case "notification_native":
 			cfg.NotificationNative = false
+		case "max_concurrency":
+			cfg.MaxConcurrency = config.DefaultMaxConcurrency
+		case "quiet_hours", "quiet_hours_timezone", "quiet_hours_allow_failures":
+			if cfg.QuietHours != nil {
+				switch key {
+				case "quiet_hours":
+					cfg.QuietHours.Windows = nil
+				case "quiet_hours_timezone":
+					cfg.QuietHours.Timezone = ""
+				case "quiet_hours_allow_failures":
+					cfg.QuietHours.AllowFailures = false
+				}
+				pruneQuietHours(cfg)
+			}
+		case "retry_max_attempts", "retry_base_delay_ms", "retry_max_delay_ms", "retry_jitter":
+			if cfg.Retry != nil {
+				defaults := config.DefaultRetryConfig()
+				switch key {
+				case "retry_max_attempts":
+					cfg.Retry.MaxAttempts = defaults.MaxAttempts
+				case "retry_base_delay_ms":
+					cfg.Retry.BaseDelayMS = defaults.BaseDelayMS
+				case "retry_max_delay_ms":
+					cfg.Retry.MaxDelayMS = defaults.MaxDelayMS
+				case "retry_jitter":
+					cfg.Retry.Jitter = defaults.Jitter
+				}
+				if *cfg.Retry == *defaults {
+					cfg.Retry = nil
+				}
+			}
+		case "history_poll_results":
+			cfg.HistoryPollResults = false
+		case "log_excerpt_lines":
+			cfg.LogExcerptLines = 0
+		case "flaky_checks":
+			cfg.FlakyChecks = nil
+		case "flaky_max_reruns":
+			cfg.FlakyMaxReruns = 0
+		case "subscription_interval_seconds":
+			cfg.SubscriptionIntervalSeconds = 0
+		case "closed_pr_policy":
+			cfg.ClosedPRPolicy = config.ClosedPRPolicyKeep
+		case "closed_pr_unwatch_days":
+			cfg.ClosedPRUnwatchDays = 0
+		case "merge_rule":
+			ruleKey, err := repoRuleKey()
+			if err != nil {
+				return err
+			}
+			cfg.RemoveMergeRule(ruleKey)
+		case "stuck_after_minutes":
+			ruleKey, err := repoRuleKey()
+			if err != nil {
+				return err
+			}
+			cfg.RemoveStuckThreshold(ruleKey)
 		default:
 			return fmt.Errorf("unknown config key: %s", key)
 		}
@@ -378,6 +1146,25 @@ var configUnsetCmd = &cobra.Command{
 	},
 }
 
+// repoRuleKey returns the key of the per-repo setting selected with --repo.
+func repoRuleKey() (string, error) {
+	if configRepo == "" {
+		return config.DefaultMergeRuleKey, nil
+	}
+	if !config.IsValidMergeRuleKey(configRepo) {
+		return "", fmt.Errorf("invalid --repo %q (expected owner/repo or owner/*)", configRepo)
+	}
+	return configRepo, nil
+}
+
+// formatMergeRule renders merge rule requirements for display.
+func formatMergeRule(requirements []string) string {
+	if len(requirements) == 0 {
+		return "none"
+	}
+	return strings.Join(requirements, ",")
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
@@ -393,15 +1180,38 @@ func init() {
 }
 
 type listPROutput struct {
-	Owner       string     `json:"owner"`
-	Repo        string     `json:"repo"`
-	Number      int        `json:"number"`
-	Status      string     `json:"status"`
-	LastChecked *time.Time `json:"last_checked,omitempty"`
-	Title       string     `json:"title,omitempty"`
+	Host        string            `json:"host,omitempty"`
+	Owner       string            `json:"owner"`
+	Repo        string            `json:"repo"`
+	Number      int               `json:"number"`
+	Status      string            `json:"status"`
+	Review      string            `json:"review,omitempty"`
+	Reviewers   []string          `json:"requested_reviewers,omitempty"`
+	PRState     string            `json:"pr_state,omitempty"`
+	Draft       bool              `json:"draft,omitempty"`
+	Mergeable   string            `json:"mergeable_state,omitempty"`
+	Ready       bool              `json:"ready_to_merge,omitempty"`
+	Subscribed  string            `json:"subscription,omitempty"`
+	Reruns      []listRerunOutput `json:"reruns,omitempty"`
+	LastChecked *time.Time        `json:"last_checked,omitempty"`
+	Title       string            `json:"title,omitempty"`
+}
+
+// listRerunOutput is an automatic rerun of a flaky check in 'prw list --json'.
+type listRerunOutput struct {
+	Check string    `json:"check"`
+	SHA   string    `json:"sha"`
+	At    time.Time `json:"at"`
 }
 
 func outputJSONList(prs []config.WatchedPR) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(listOutput(prs))
+}
+
+// listOutput converts watched PRs into the JSON shape of 'prw list --json'.
+func listOutput(prs []config.WatchedPR) []listPROutput {
 	output := make([]listPROutput, 0, len(prs))
 	for _, pr := range prs {
 		status := pr.LastKnownState
@@ -415,17 +1225,31 @@ func outputJSONList(prs []config.WatchedPR) error {
 			t := pr.LastChecked
 			lastChecked = &t
 		}
+		host := pr.HostName()
+		if host == github.DefaultHost {
+			host = ""
+		}
+		var reruns []listRerunOutput
+		for _, rerun := range pr.Reruns {
+			reruns = append(reruns, listRerunOutput{Check: rerun.Check, SHA: rerun.SHA, At: rerun.At})
+		}
 		output = append(output, listPROutput{
+			Host:        host,
 			Owner:       pr.Owner,
 			Repo:        pr.Repo,
 			Number:      pr.Number,
 			Status:      status,
+			Review:      pr.ReviewDecision,
+			Reviewers:   pr.RequestedReviewers,
+			PRState:     pr.PRState,
+			Draft:       pr.Draft,
+			Mergeable:   pr.MergeableState,
+			Ready:       pr.ReadyToMerge,
+			Subscribed:  pr.Subscription,
+			Reruns:      reruns,
 			LastChecked: lastChecked,
 			Title:       pr.Title,
 		})
 	}
-
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(output)
+	return output
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/devblac/prw/internal/config"
 	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
 )
 
 // captureStdout captures stdout output from a function
@@ -189,6 +190,58 @@ func TestListCmd_JSONOutput(t *testing.T) {
 	}
 }
 
+func TestListCmd_ReviewColumn(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "success", ReviewDecision: "changes_requested", RequestedReviewers: []string{"bob"}},
+			{Owner: "owner", Repo: "repo", Number: 2, LastKnownState: "pending"},
+		},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	listJSON = false
+	output, err := captureStdout(func() error {
+		return listCmd.RunE(listCmd, []string{})
+	})
+	if err != nil {
+		t.Fatalf("listCmd.RunE() error = %v", err)
+	}
+	if !strings.Contains(output, "REVIEW") || !strings.Contains(output, "changes_requested") {
+		t.Errorf("expected REVIEW column in table output, got: %s", output)
+	}
+
+	listJSON = true
+	defer func() { listJSON = false }()
+	output, err = captureStdout(func() error {
+		return listCmd.RunE(listCmd, []string{})
+	})
+	if err != nil {
+		t.Fatalf("listCmd.RunE() error = %v", err)
+	}
+
+	var decoded []listPROutput
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded[0].Review != "changes_requested" || len(decoded[0].Reviewers) != 1 {
+		t.Errorf("expected review fields for PR 1, got %+v", decoded[0])
+	}
+	if strings.Count(output, `"review"`) != 1 {
+		t.Errorf("expected review to be omitted for unchecked PRs: %s", output)
+	}
+}
+
 func TestListCmd_EmptyList(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, ".prw", "config.json")
@@ -356,6 +409,184 @@ func TestConfigSetCmd(t *testing.T) {
 			value:   "value",
 			wantErr: true,
 		},
+		{
+			name:  "set max_concurrency",
+			key:   "max_concurrency",
+			value: "8",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.MaxConcurrency != 8 {
+					return fmt.Errorf("expected 8, got %d", cfg.MaxConcurrency)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid max_concurrency",
+			key:     "max_concurrency",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:  "set retry_max_attempts",
+			key:   "retry_max_attempts",
+			value: "5",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.Retry == nil || cfg.Retry.MaxAttempts != 5 {
+					return fmt.Errorf("expected 5 retry attempts, got %+v", cfg.Retry)
+				}
+				if cfg.Retry.BaseDelayMS != config.DefaultRetryConfig().BaseDelayMS {
+					return fmt.Errorf("expected other retry settings to keep defaults, got %+v", cfg.Retry)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid retry_max_attempts",
+			key:     "retry_max_attempts",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:  "set retry_jitter",
+			key:   "retry_jitter",
+			value: "0.5",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.Retry == nil || cfg.Retry.Jitter != 0.5 {
+					return fmt.Errorf("expected jitter 0.5, got %+v", cfg.Retry)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid retry_jitter",
+			key:     "retry_jitter",
+			value:   "2",
+			wantErr: true,
+		},
+		{
+			name:  "set history_poll_results",
+			key:   "history_poll_results",
+			value: "true",
+			checkFunc: func(cfg *config.Config) error {
+				if !cfg.HistoryPollResults {
+					return fmt.Errorf("expected history_poll_results to be enabled")
+				}
+				return nil
+			},
+		},
+		{
+			name:  "set github_webhook_secret",
+			key:   "github_webhook_secret",
+			value: "s3cret",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.GitHubWebhookSecret != "s3cret" {
+					return fmt.Errorf("expected github_webhook_secret s3cret, got %q", cfg.GitHubWebhookSecret)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid history_poll_results",
+			key:     "history_poll_results",
+			value:   "sometimes",
+			wantErr: true,
+		},
+		{
+			name:  "set closed_pr_policy",
+			key:   "closed_pr_policy",
+			value: "unwatch_after_days",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.ClosedPRPolicy != config.ClosedPRPolicyUnwatchDays {
+					return fmt.Errorf("expected unwatch_after_days, got %s", cfg.ClosedPRPolicy)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid closed_pr_policy",
+			key:     "closed_pr_policy",
+			value:   "forever",
+			wantErr: true,
+		},
+		{
+			name:  "set closed_pr_unwatch_days",
+			key:   "closed_pr_unwatch_days",
+			value: "7",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.ClosedPRUnwatchDays != 7 {
+					return fmt.Errorf("expected 7, got %d", cfg.ClosedPRUnwatchDays)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid closed_pr_unwatch_days",
+			key:     "closed_pr_unwatch_days",
+			value:   "-3",
+			wantErr: true,
+		},
+		{
+			name:  "set log_excerpt_lines",
+			key:   "log_excerpt_lines",
+			value: "20",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.LogExcerptLines != 20 {
+					return fmt.Errorf("expected 20, got %d", cfg.LogExcerptLines)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid log_excerpt_lines",
+			key:     "log_excerpt_lines",
+			value:   "many",
+			wantErr: true,
+		},
+		{
+			name:  "set flaky_checks",
+			key:   "flaky_checks",
+			value: "e2e-*, integration ,e2e-*",
+			checkFunc: func(cfg *config.Config) error {
+				if strings.Join(cfg.FlakyChecks, ",") != "e2e-*,integration" {
+					return fmt.Errorf("expected [e2e-* integration], got %v", cfg.FlakyChecks)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "set flaky_max_reruns",
+			key:   "flaky_max_reruns",
+			value: "3",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.FlakyMaxReruns != 3 {
+					return fmt.Errorf("expected 3, got %d", cfg.FlakyMaxReruns)
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid flaky_max_reruns",
+			key:     "flaky_max_reruns",
+			value:   "0",
+			wantErr: true,
+		},
+		{
+			name:  "set subscription_interval_seconds",
+			key:   "subscription_interval_seconds",
+			value: "300",
+			checkFunc: func(cfg *config.Config) error {
+				if cfg.SubscriptionInterval() != 5*time.Minute {
+					return fmt.Errorf("expected 5m, got %s", cfg.SubscriptionInterval())
+				}
+				return nil
+			},
+		},
+		{
+			name:    "invalid subscription_interval_seconds",
+			key:     "subscription_interval_seconds",
+			value:   "-1",
+			wantErr: true,
+		},
 		{
 			name:    "invalid notification_filter gets normalized",
 			key:     "notification_filter",
@@ -1178,10 +1409,16 @@ func TestRunCmd_Once(t *testing.T) {
 
 	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			fmt.Fprint(w, `[]`)
 		case strings.Contains(r.URL.Path, "/pulls/123"):
 			fmt.Fprintf(w, `{"number":123,"title":"RunOnce PR","head":{"sha":"abc123"}}`)
 		case strings.Contains(r.URL.Path, "/commits/abc123/status"):
 			fmt.Fprintf(w, `{"state":"success","sha":"abc123"}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprintf(w, `{"total_count":0,"check_suites":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprintf(w, `{"total_count":0,"check_runs":[]}`)
 		default:
 			t.Fatalf("unexpected path: %s", r.URL.Path)
 		}
@@ -1189,7 +1426,7 @@ func TestRunCmd_Once(t *testing.T) {
 	defer ghServer.Close()
 
 	oldNewGitHubClient := newGitHubClient
-	newGitHubClient = func(token string) *github.Client {
+	newGitHubClient = func(host, token string) *github.Client {
 		c := github.NewClient(token)
 		c.BaseURL = ghServer.URL
 		c.HTTPClient = ghServer.Client()
@@ -1253,7 +1490,7 @@ func TestWatchCmd_Success(t *testing.T) {
 
 	// Inject custom client that points to the mock server
 	oldNewGitHubClient := newGitHubClient
-	newGitHubClient = func(token string) *github.Client {
+	newGitHubClient = func(host, token string) *github.Client {
 		client := github.NewClient(token)
 		client.BaseURL = server.URL
 		client.HTTPClient = server.Client()
@@ -1392,10 +1629,16 @@ func TestBroadcastCmd_SuccessWebhook(t *testing.T) {
 
 	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			fmt.Fprint(w, `[]`)
 		case strings.Contains(r.URL.Path, "/pulls/123"):
 			fmt.Fprintf(w, `{"number":123,"title":"Test PR","head":{"sha":"abc123"}}`)
 		case strings.Contains(r.URL.Path, "/commits/abc123/status"):
 			fmt.Fprintf(w, `{"state":"success","sha":"abc123"}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprintf(w, `{"total_count":0,"check_suites":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprintf(w, `{"total_count":0,"check_runs":[]}`)
 		default:
 			t.Fatalf("unexpected path: %s", r.URL.Path)
 		}
@@ -1403,7 +1646,7 @@ func TestBroadcastCmd_SuccessWebhook(t *testing.T) {
 	defer ghServer.Close()
 
 	oldNewGitHubClient := newGitHubClient
-	newGitHubClient = func(token string) *github.Client {
+	newGitHubClient = func(host, token string) *github.Client {
 		c := github.NewClient(token)
 		c.BaseURL = ghServer.URL
 		c.HTTPClient = ghServer.Client()
@@ -1470,6 +1713,8 @@ func TestBroadcastCmd_FilterFailing(t *testing.T) {
 
 	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			fmt.Fprint(w, `[]`)
 		case strings.Contains(r.URL.Path, "/pulls/1"):
 			fmt.Fprintf(w, `{"number":1,"title":"PR1","head":{"sha":"sha1"}}`)
 		case strings.Contains(r.URL.Path, "/pulls/2"):
@@ -1478,6 +1723,10 @@ func TestBroadcastCmd_FilterFailing(t *testing.T) {
 			fmt.Fprintf(w, `{"state":"success","sha":"sha1"}`)
 		case strings.Contains(r.URL.Path, "/commits/sha2/status"):
 			fmt.Fprintf(w, `{"state":"failure","sha":"sha2"}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprintf(w, `{"total_count":0,"check_suites":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprintf(w, `{"total_count":0,"check_runs":[]}`)
 		default:
 			t.Fatalf("unexpected path: %s", r.URL.Path)
 		}
@@ -1485,7 +1734,7 @@ func TestBroadcastCmd_FilterFailing(t *testing.T) {
 	defer ghServer.Close()
 
 	oldNewGitHubClient := newGitHubClient
-	newGitHubClient = func(token string) *github.Client {
+	newGitHubClient = func(host, token string) *github.Client {
 		c := github.NewClient(token)
 		c.BaseURL = ghServer.URL
 		c.HTTPClient = ghServer.Client()
@@ -1536,10 +1785,16 @@ func TestBroadcastCmd_DryRun(t *testing.T) {
 
 	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			fmt.Fprint(w, `[]`)
 		case strings.Contains(r.URL.Path, "/pulls/1"):
 			fmt.Fprintf(w, `{"number":1,"title":"PR1","head":{"sha":"sha1"}}`)
 		case strings.Contains(r.URL.Path, "/commits/sha1/status"):
 			fmt.Fprintf(w, `{"state":"success","sha":"sha1"}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprintf(w, `{"total_count":0,"check_suites":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprintf(w, `{"total_count":0,"check_runs":[]}`)
 		default:
 			t.Fatalf("unexpected path: %s", r.URL.Path)
 		}
@@ -1547,7 +1802,7 @@ func TestBroadcastCmd_DryRun(t *testing.T) {
 	defer ghServer.Close()
 
 	oldNewGitHubClient := newGitHubClient
-	newGitHubClient = func(token string) *github.Client {
+	newGitHubClient = func(host, token string) *github.Client {
 		c := github.NewClient(token)
 		c.BaseURL = ghServer.URL
 		c.HTTPClient = ghServer.Client()
@@ -1612,3 +1867,540 @@ func TestBroadcastCmd_EmptyList(t *testing.T) {
 		t.Errorf("expected empty message, got: %s", output)
 	}
 }
+
+func TestDoctorCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"resources":{"core":{"limit":5000,"remaining":4321,"used":679,"reset":1700000000}}}`)
+	}))
+	defer ghServer.Close()
+
+	oldNewGitHubClient := newGitHubClient
+	newGitHubClient = func(host, token string) *github.Client {
+		c := github.NewClient(token)
+		c.BaseURL = ghServer.URL
+		c.HTTPClient = ghServer.Client()
+		return c
+	}
+	defer func() { newGitHubClient = oldNewGitHubClient }()
+
+	output, err := captureStdout(func() error {
+		return doctorCmd.RunE(doctorCmd, []string{})
+	})
+	if err != nil {
+		t.Fatalf("doctorCmd.RunE() error = %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "4321/5000 remaining") {
+		t.Errorf("expected rate limit budget in output, got: %s", output)
+	}
+	if !strings.Contains(output, "All checks passed") {
+		t.Errorf("expected all checks to pass, got: %s", output)
+	}
+}
+
+func TestDoctorCmd_MissingToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	oldToken := os.Getenv("GITHUB_TOKEN")
+	os.Unsetenv("GITHUB_TOKEN")
+	defer func() {
+		if oldToken != "" {
+			os.Setenv("GITHUB_TOKEN", oldToken)
+		}
+	}()
+
+	output, err := captureStdout(func() error {
+		return doctorCmd.RunE(doctorCmd, []string{})
+	})
+	if err == nil {
+		t.Fatal("expected doctor to report a problem when the token is missing")
+	}
+	if !strings.Contains(output, "GitHub token: not set") {
+		t.Errorf("expected missing token report, got: %s", output)
+	}
+}
+
+func TestWatchCmd_EnterpriseHost(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	t.Setenv("GITHUB_TOKEN", "github-com-token")
+
+	cfg := config.DefaultConfig()
+	cfg.SetHostToken("github.example.com", "ghe-token")
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghe-token" {
+			t.Errorf("expected enterprise token, got %q", got)
+		}
+		fmt.Fprintf(w, `{"number":42,"title":"GHES PR","head":{"sha":"abc123"}}`)
+	}))
+	defer server.Close()
+
+	var gotHost string
+	oldNewGitHubClient := newGitHubClient
+	newGitHubClient = func(host, token string) *github.Client {
+		gotHost = host
+		client := github.NewClient(token)
+		client.BaseURL = server.URL
+		client.HTTPClient = server.Client()
+		return client
+	}
+	defer func() { newGitHubClient = oldNewGitHubClient }()
+
+	_, err := captureStdout(func() error {
+		return watchCmd.RunE(watchCmd, []string{"https://github.example.com/team/service/pull/42"})
+	})
+	if err != nil {
+		t.Fatalf("watchCmd.RunE() error = %v", err)
+	}
+	if gotHost != "github.example.com" {
+		t.Errorf("expected client for github.example.com, got %q", gotHost)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.WatchedPRs) != 1 || loaded.WatchedPRs[0].Host != "github.example.com" {
+		t.Fatalf("expected enterprise PR to be stored with its host, got %+v", loaded.WatchedPRs)
+	}
+
+	// Unwatching by URL must match the host too
+	if _, err := captureStdout(func() error {
+		return unwatchCmd.RunE(unwatchCmd, []string{"https://github.com/team/service/pull/42"})
+	}); err != nil {
+		t.Fatalf("unwatchCmd.RunE() error = %v", err)
+	}
+	loaded, _ = config.Load()
+	if len(loaded.WatchedPRs) != 1 {
+		t.Fatalf("github.com URL should not unwatch the enterprise PR")
+	}
+	if _, err := captureStdout(func() error {
+		return unwatchCmd.RunE(unwatchCmd, []string{"https://github.example.com/team/service/pull/42"})
+	}); err != nil {
+		t.Fatalf("unwatchCmd.RunE() error = %v", err)
+	}
+	loaded, _ = config.Load()
+	if len(loaded.WatchedPRs) != 0 {
+		t.Errorf("expected enterprise PR to be unwatched, got %+v", loaded.WatchedPRs)
+	}
+}
+
+func TestWatchCmd_UnknownHost(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	t.Setenv("GITHUB_TOKEN", "github-com-token")
+
+	err := watchCmd.RunE(watchCmd, []string{"https://github.example.com/team/service/pull/42"})
+	if err == nil || !strings.Contains(err.Error(), "unknown GitHub host github.example.com") {
+		t.Errorf("expected unknown host error, got %v", err)
+	}
+}
+
+func TestRunCmd_MissingEnterpriseToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	// GITHUB_TOKEN must never be sent to an enterprise host
+	t.Setenv("GITHUB_TOKEN", "github-com-token")
+
+	cfg := &config.Config{
+		Hosts: map[string]config.HostConfig{"github.example.com": {}},
+		WatchedPRs: []config.WatchedPR{
+			{Host: "github.example.com", Owner: "team", Repo: "service", Number: 42},
+		},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	err := runCmd.RunE(runCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "missing token for github.example.com") {
+		t.Errorf("expected missing enterprise token error, got %v", err)
+	}
+}
+
+func TestConfigSetCmd_HostToken(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	configHost = "github.example.com"
+	defer func() { configHost = "" }()
+
+	if _, err := captureStdout(func() error {
+		return configSetCmd.RunE(configSetCmd, []string{"github_token", "ghe-token"})
+	}); err != nil {
+		t.Fatalf("configSetCmd.RunE() error = %v", err)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.GitHubToken != "" || loaded.TokenForHost("github.example.com") != "ghe-token" {
+		t.Errorf("expected token stored for enterprise host only, got %+v", loaded)
+	}
+
+	if err := configSetCmd.RunE(configSetCmd, []string{"webhook_url", "https://example.com"}); err == nil {
+		t.Error("expected --host to be rejected for keys other than github_token")
+	}
+
+	if _, err := captureStdout(func() error {
+		return configUnsetCmd.RunE(configUnsetCmd, []string{"github_token"})
+	}); err != nil {
+		t.Fatalf("configUnsetCmd.RunE() error = %v", err)
+	}
+	loaded, _ = config.Load()
+	if loaded.IsConfiguredHost("github.example.com") {
+		t.Error("expected enterprise host to be removed")
+	}
+}
+
+func TestConfigSetCmd_MergeRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+	defer func() { configRepo = "" }()
+
+	set := func(repo, value string) error {
+		configRepo = repo
+		_, err := captureStdout(func() error {
+			return configSetCmd.RunE(configSetCmd, []string{"merge_rule", value})
+		})
+		return err
+	}
+
+	if err := set("", "checks,approval"); err != nil {
+		t.Fatalf("set default merge_rule: %v", err)
+	}
+	if err := set("Owner/Repo", "checks, not_draft"); err != nil {
+		t.Fatalf("set repo merge_rule: %v", err)
+	}
+	if err := set("owner/repo", "checks,bogus"); err == nil {
+		t.Error("expected unknown requirement to be rejected")
+	}
+	if err := set("owner", "checks"); err == nil {
+		t.Error("expected invalid --repo to be rejected")
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if got := loaded.MergeRuleFor("owner", "repo"); strings.Join(got, ",") != "checks,not_draft" {
+		t.Errorf("repo rule = %v", got)
+	}
+	if got := loaded.MergeRuleFor("other", "repo"); strings.Join(got, ",") != "checks,approval" {
+		t.Errorf("default rule = %v", got)
+	}
+
+	configRepo = "owner/repo"
+	if err := configSetCmd.RunE(configSetCmd, []string{"webhook_url", "https://example.com"}); err == nil {
+		t.Error("expected --repo to be rejected for keys other than merge_rule")
+	}
+	if _, err := captureStdout(func() error {
+		return configUnsetCmd.RunE(configUnsetCmd, []string{"merge_rule"})
+	}); err != nil {
+		t.Fatalf("configUnsetCmd.RunE() error = %v", err)
+	}
+	loaded, _ = config.Load()
+	if got := loaded.MergeRuleFor("owner", "repo"); strings.Join(got, ",") != "checks,approval" {
+		t.Errorf("expected repo rule to be removed, got %v", got)
+	}
+}
+
+func TestConfigSetCmd_StuckAfterMinutes(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+	defer func() { configRepo = "" }()
+
+	set := func(repo, value string) error {
+		configRepo = repo
+		_, err := captureStdout(func() error {
+			return configSetCmd.RunE(configSetCmd, []string{"stuck_after_minutes", value})
+		})
+		return err
+	}
+
+	if err := set("", "60"); err != nil {
+		t.Fatalf("set default stuck_after_minutes: %v", err)
+	}
+	if err := set("owner/*", "120"); err != nil {
+		t.Fatalf("set owner stuck_after_minutes: %v", err)
+	}
+	if err := set("", "-5"); err == nil {
+		t.Error("expected a negative threshold to be rejected")
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if got := loaded.StuckThresholdFor("owner", "repo"); got != 2*time.Hour {
+		t.Errorf("owner threshold = %v", got)
+	}
+	if got := loaded.StuckThresholdFor("other", "repo"); got != time.Hour {
+		t.Errorf("default threshold = %v", got)
+	}
+
+	configRepo = "owner/*"
+	if _, err := captureStdout(func() error {
+		return configUnsetCmd.RunE(configUnsetCmd, []string{"stuck_after_minutes"})
+	}); err != nil {
+		t.Fatalf("configUnsetCmd.RunE() error = %v", err)
+	}
+	loaded, _ = config.Load()
+	if got := loaded.StuckThresholdFor("owner", "repo"); got != time.Hour {
+		t.Errorf("expected the owner threshold to be removed, got %v", got)
+	}
+}
+
+func TestConfigSetCmd_QuietHours(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	set := func(key, value string) error {
+		_, err := captureStdout(func() error {
+			return configSetCmd.RunE(configSetCmd, []string{key, value})
+		})
+		return err
+	}
+	unset := func(key string) {
+		if _, err := captureStdout(func() error {
+			return configUnsetCmd.RunE(configUnsetCmd, []string{key})
+		}); err != nil {
+			t.Fatalf("unset %s: %v", key, err)
+		}
+	}
+
+	if err := set("quiet_hours", "weekdays 22:00-07:00, weekends 00:00-24:00"); err != nil {
+		t.Fatalf("set quiet_hours: %v", err)
+	}
+	if err := set("quiet_hours_timezone", "UTC"); err != nil {
+		t.Fatalf("set quiet_hours_timezone: %v", err)
+	}
+	if err := set("quiet_hours_allow_failures", "true"); err != nil {
+		t.Fatalf("set quiet_hours_allow_failures: %v", err)
+	}
+	if err := set("quiet_hours", "22:00-7"); err == nil {
+		t.Error("expected an invalid window to be rejected")
+	}
+	if err := set("quiet_hours_timezone", "Mars/Olympus"); err == nil {
+		t.Error("expected an unknown time zone to be rejected")
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	quiet := loaded.QuietHours
+	if quiet == nil || len(quiet.Windows) != 2 || quiet.Timezone != "UTC" || !quiet.AllowFailures {
+		t.Fatalf("unexpected quiet hours: %+v", quiet)
+	}
+	if !quiet.Active(time.Date(2024, 6, 4, 23, 0, 0, 0, time.UTC)) {
+		t.Error("expected Tuesday 23:00 UTC to be quiet")
+	}
+
+	output, err := captureStdout(func() error {
+		return configShowCmd.RunE(configShowCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("config show: %v", err)
+	}
+	if !strings.Contains(output, "quiet_hours: mon+tue+wed+thu+fri 22:00-07:00, sat+sun 00:00-24:00\n") ||
+		!strings.Contains(output, "quiet_hours_timezone: UTC\n") {
+		t.Errorf("config show output missing quiet hours:\n%s", output)
+	}
+
+	unset("quiet_hours")
+	unset("quiet_hours_timezone")
+	unset("quiet_hours_allow_failures")
+	loaded, _ = config.Load()
+	if loaded.QuietHours != nil {
+		t.Errorf("expected quiet hours to be removed, got %+v", loaded.QuietHours)
+	}
+}
+
+func TestNewSlackNotifier(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if newSlackNotifier(cfg) != nil {
+		t.Error("expected no Slack notifier without Slack settings")
+	}
+
+	cfg.SlackWebhookURL = "https://hooks.slack.com/services/T/B/X"
+	if n := newSlackNotifier(cfg); n == nil || n.WebhookURL != cfg.SlackWebhookURL || n.Token != "" {
+		t.Errorf("expected an incoming webhook notifier, got %+v", n)
+	}
+
+	cfg.SlackBotToken = "xoxb-token"
+	cfg.SlackChannel = "#ci"
+	if n := newSlackNotifier(cfg); n == nil || n.Token != "xoxb-token" || n.Channel != "#ci" || n.Threads == nil {
+		t.Errorf("expected the bot token to take precedence with stored threads, got %+v", n)
+	}
+}
+
+func TestNewWebhookNotifierFormats(t *testing.T) {
+	cfg := config.DefaultConfig()
+	tests := []struct {
+		url, format string
+		want        notify.Notifier
+	}{
+		{"https://example.com/hook", "", &notify.WebhookNotifier{}},
+		{"https://hooks.slack.com/services/T/B/X", "", &notify.SlackNotifier{}},
+		{"https://discord.com/api/webhooks/1/abc", "auto", &notify.DiscordNotifier{}},
+		{"https://contoso.webhook.office.com/webhookb2/abc", "", &notify.TeamsNotifier{}},
+		{"https://discord.com/api/webhooks/1/abc", "json", &notify.WebhookNotifier{}},
+		{"https://example.com/hook", "teams", &notify.TeamsNotifier{}},
+	}
+	for _, tt := range tests {
+		got := newWebhookNotifier(cfg, tt.url, tt.format)
+		if fmt.Sprintf("%T", got) != fmt.Sprintf("%T", tt.want) {
+			t.Errorf("newWebhookNotifier(%q, %q) = %T, want %T", tt.url, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestConfigSetCmd_Email(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	set := func(key, value string) error {
+		_, err := captureStdout(func() error {
+			return configSetCmd.RunE(configSetCmd, []string{key, value})
+		})
+		return err
+	}
+
+	for _, kv := range [][2]string{
+		{"email_smtp_host", "smtp.example.com"},
+		{"email_smtp_security", "TLS"},
+		{"email_from", "prw <prw@example.com>"},
+		{"email_to", "dev@example.com, Lead <lead@example.com>"},
+		{"email_digest_minutes", "15"},
+	} {
+		if err := set(kv[0], kv[1]); err != nil {
+			t.Fatalf("set %s: %v", kv[0], err)
+		}
+	}
+	if err := set("email_smtp_security", "ssl"); err == nil {
+		t.Error("expected an unknown security mode to be rejected")
+	}
+	if err := set("email_to", "not an address"); err == nil {
+		t.Error("expected an invalid recipient to be rejected")
+	}
+	if err := set("email_from", "a@example.com, b@example.com"); err == nil {
+		t.Error("expected several senders to be rejected")
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if !loaded.Email.Enabled() || len(loaded.Email.To) != 2 || loaded.Email.SMTPSecurity != "tls" {
+		t.Fatalf("unexpected email settings: %+v", loaded.Email)
+	}
+
+	n := newEmailNotifier(loaded, loaded.Email)
+	if n.Port != 0 || n.Security != notify.EmailSecurityTLS || n.Digest != 15*time.Minute {
+		t.Errorf("unexpected email notifier: %+v", n)
+	}
+
+	output, err := captureStdout(func() error {
+		return configShowCmd.RunE(configShowCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("config show: %v", err)
+	}
+	if !strings.Contains(output, "email_smtp_port: 465\n") ||
+		!strings.Contains(output, `email_to: dev@example.com, "Lead" <lead@example.com>`) {
+		t.Errorf("config show output missing email settings:\n%s", output)
+	}
+
+	for _, key := range []string{"email_smtp_host", "email_smtp_security", "email_from", "email_to", "email_digest_minutes"} {
+		if _, err := captureStdout(func() error {
+			return configUnsetCmd.RunE(configUnsetCmd, []string{key})
+		}); err != nil {
+			t.Fatalf("unset %s: %v", key, err)
+		}
+	}
+	loaded, _ = config.Load()
+	if loaded.Email != nil {
+		t.Errorf("expected email settings to be removed, got %+v", loaded.Email)
+	}
+}
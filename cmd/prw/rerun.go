@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+)
+
+var rerunFailedOnly bool
+
+func init() {
+	rootCmd.AddCommand(rerunCmd)
+	rerunCmd.Flags().BoolVar(&rerunFailedOnly, "failed-only", false, "re-run only the failed jobs of each workflow run")
+}
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun <PR_URL>",
+	Short: "Re-run the failed GitHub Actions workflows of a PR",
+	Long: `Re-run every failed GitHub Actions workflow run of a PR's head commit.
+Use --failed-only to re-run only the failed jobs (and the jobs depending on them)
+instead of whole workflows.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		pr, client, err := resolvePR(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		ghPR, err := client.GetPullRequest(pr.Owner, pr.Repo, pr.Number)
+		if err != nil {
+			return fmt.Errorf("failed to fetch PR: %w", err)
+		}
+		sha := ghPR.Head.SHA
+
+		runs, err := client.GetWorkflowRuns(pr.Owner, pr.Repo, sha)
+		if err != nil {
+			return fmt.Errorf("failed to fetch workflow runs: %w", err)
+		}
+
+		label := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+		rerun := 0
+		for _, run := range runs {
+			if !run.Failed() {
+				continue
+			}
+			if err := client.RerunWorkflow(pr.Owner, pr.Repo, run.ID, rerunFailedOnly); err != nil {
+				return fmt.Errorf("failed to re-run %s: %w", run.Name, err)
+			}
+			rerun++
+			if rerunFailedOnly {
+				fmt.Printf("Re-running failed jobs of %s (%s)\n", run.Name, run.Conclusion)
+			} else {
+				fmt.Printf("Re-running %s (%s)\n", run.Name, run.Conclusion)
+			}
+			printLink(run.HTMLURL)
+		}
+
+		if rerun == 0 {
+			fmt.Printf("No failed workflow runs for %s at %s.\n", label, shortSHA(sha))
+			return nil
+		}
+		fmt.Printf("Re-ran %d workflow run(s) for %s at %s.\n", rerun, label, shortSHA(sha))
+		return nil
+	},
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+func TestRerunCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	var posts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			posts = append(posts, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			fmt.Fprint(w, `{"number": 1, "title": "Flaky", "state": "open", "head": {"sha": "abcdef123456"}}`)
+		case strings.HasSuffix(r.URL.Path, "/pulls/2"):
+			fmt.Fprint(w, `{"number": 2, "title": "Green", "state": "open", "head": {"sha": "fedcba654321"}}`)
+		case strings.HasSuffix(r.URL.Path, "/actions/runs") && r.URL.Query().Get("head_sha") == "abcdef123456":
+			fmt.Fprint(w, `{"workflow_runs": [
+				{"id": 21, "name": "CI", "status": "completed", "conclusion": "failure", "html_url": "https://github.com/owner/repo/actions/runs/21"},
+				{"id": 22, "name": "Lint", "status": "completed", "conclusion": "success"},
+				{"id": 23, "name": "E2E", "status": "completed", "conclusion": "cancelled"}
+			]}`)
+		case strings.HasSuffix(r.URL.Path, "/actions/runs"):
+			fmt.Fprint(w, `{"workflow_runs": [{"id": 31, "name": "CI", "status": "completed", "conclusion": "success"}]}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	oldNewGitHubClient := newGitHubClient
+	defer func() { newGitHubClient = oldNewGitHubClient }()
+	newGitHubClient = func(host, token string) *github.Client {
+		client := github.NewClient(token)
+		client.BaseURL = server.URL
+		client.HTTPClient = server.Client()
+		return client
+	}
+	defer func() { rerunFailedOnly = false }()
+
+	tests := []struct {
+		name       string
+		url        string
+		failedOnly bool
+		wantPosts  []string
+		wantOutput string
+	}{
+		{
+			name:       "whole workflows",
+			url:        "https://github.com/owner/repo/pull/1",
+			wantPosts:  []string{"/repos/owner/repo/actions/runs/21/rerun", "/repos/owner/repo/actions/runs/23/rerun"},
+			wantOutput: "Re-ran 2 workflow run(s) for owner/repo#1 at abcdef1.",
+		},
+		{
+			name:       "failed jobs only",
+			url:        "https://github.com/owner/repo/pull/1",
+			failedOnly: true,
+			wantPosts:  []string{"/repos/owner/repo/actions/runs/21/rerun-failed-jobs", "/repos/owner/repo/actions/runs/23/rerun-failed-jobs"},
+			wantOutput: "Re-running failed jobs of CI (failure)",
+		},
+		{
+			name:       "nothing failed",
+			url:        "https://github.com/owner/repo/pull/2",
+			wantOutput: "No failed workflow runs for owner/repo#2 at fedcba6.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts = nil
+			rerunFailedOnly = tt.failedOnly
+
+			output, err := captureStdout(func() error {
+				return rerunCmd.RunE(rerunCmd, []string{tt.url})
+			})
+			if err != nil {
+				t.Fatalf("rerunCmd.RunE() error = %v", err)
+			}
+			if strings.Join(posts, " ") != strings.Join(tt.wantPosts, " ") {
+				t.Errorf("rerun requests = %v, want %v", posts, tt.wantPosts)
+			}
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.wantOutput, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,492 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+var (
+	routeRepos   []string
+	routeAuthors []string
+	routeLabels  []string
+	routeEvents  []string
+	routeFrom    []string
+	routeTo      []string
+	routeNotify  []string
+	routeStop    bool
+
+	routeTestEvent  string
+	routeTestFrom   string
+	routeTestTo     string
+	routeTestAuthor string
+	routeTestLabels []string
+
+	notifierURL     string
+	notifierFormat  string
+	notifierToken   string
+	notifierChannel string
+	notifierTo      string
+)
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+	routeCmd.AddCommand(routeAddCmd)
+	routeCmd.AddCommand(routeRemoveCmd)
+	routeCmd.AddCommand(routeTestCmd)
+	routeAddCmd.Flags().StringSliceVar(&routeRepos, "repo", nil, "owner/repo glob patterns to match, e.g. org/* (default: every repo)")
+	routeAddCmd.Flags().StringSliceVar(&routeAuthors, "author", nil, "PR authors to match (default: every author)")
+	routeAddCmd.Flags().StringSliceVar(&routeLabels, "label", nil, "match PRs with any of these labels (default: every PR)")
+	routeAddCmd.Flags().StringSliceVar(&routeEvents, "event", nil, "event types to match, e.g. status_change,merged (default: every event)")
+	routeAddCmd.Flags().StringSliceVar(&routeFrom, "from", nil, "previous states to match, e.g. pending (default: every state)")
+	routeAddCmd.Flags().StringSliceVar(&routeTo, "to", nil, "current states to match, e.g. failure,error (default: every state)")
+	routeAddCmd.Flags().StringSliceVar(&routeNotify, "notify", nil, "notifiers to send matching events to (required)")
+	routeAddCmd.Flags().BoolVar(&routeStop, "stop", false, "skip the routes after this one when it matches")
+	routeTestCmd.Flags().StringVar(&routeTestEvent, "event", notify.EventStatusChange, "event type")
+	routeTestCmd.Flags().StringVar(&routeTestFrom, "from", "pending", "previous state")
+	routeTestCmd.Flags().StringVar(&routeTestTo, "to", "failure", "current state")
+	routeTestCmd.Flags().StringVar(&routeTestAuthor, "author", "", "PR author")
+	routeTestCmd.Flags().StringSliceVar(&routeTestLabels, "label", nil, "PR labels")
+
+	rootCmd.AddCommand(notifierCmd)
+	notifierCmd.AddCommand(notifierAddCmd)
+	notifierCmd.AddCommand(notifierRemoveCmd)
+	notifierAddCmd.Flags().StringVar(&notifierURL, "url", "", "webhook URL, or Slack incoming webhook URL")
+	notifierAddCmd.Flags().StringVar(&notifierFormat, "format", "", "webhook payload format: json, slack, discord, teams, or auto (default: auto)")
+	notifierAddCmd.Flags().StringVar(&notifierToken, "token", "", "Slack bot token (default: slack_bot_token)")
+	notifierAddCmd.Flags().StringVar(&notifierChannel, "channel", "", "Slack channel to post to with the bot token")
+	notifierAddCmd.Flags().StringVar(&notifierTo, "to", "", "comma-separated email recipients, sent through the email_smtp_* server")
+}
+
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Show the notification routes",
+	Long: `Routes pick the notifiers of each event. Without routes, every event goes to
+every configured notifier. With routes, an event goes to the notifiers of every
+route it matches, in order, until a route added with --stop matches; events
+matching no route are only shown in the dashboard. Routes apply to the events
+that pass notification_filter.
+
+Notifiers are named: console, native, webhook, slack, and email are the built-in
+ones, configured with 'prw config set'; more can be added with 'prw notifier add'.
+
+  prw notifier add api-ci webhook --url https://hooks.example.com/api-ci
+  prw route add --repo org/api --to failure,error --notify api-ci
+  prw route add --author octocat --to success --notify native
+  prw route add --notify console`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if len(cfg.Routes) == 0 {
+			fmt.Println("No routes; every event goes to every configured notifier.")
+			return nil
+		}
+		for i, route := range cfg.Routes {
+			fmt.Printf("%d. %s\n", i+1, describeRoute(route))
+		}
+		return nil
+	},
+}
+
+var routeAddCmd = &cobra.Command{
+	Use:   "add --notify <notifier,...> [criteria]",
+	Short: "Add a route after the existing ones",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, event := range routeEvents {
+			if !isNotifiedEventType(event) {
+				return fmt.Errorf("unknown event type %q (expected one of: %s)", event, strings.Join(notify.NotifiedEventTypes, ", "))
+			}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		route := config.Route{
+			Repos:   routeRepos,
+			Authors: routeAuthors,
+			Labels:  routeLabels,
+			Events:  routeEvents,
+			From:    routeFrom,
+			To:      routeTo,
+			Notify:  routeNotify,
+			Stop:    routeStop,
+		}
+		if err := cfg.AddRoute(route); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Added route %d: %s\n", len(cfg.Routes), describeRoute(route))
+		return nil
+	},
+}
+
+var routeRemoveCmd = &cobra.Command{
+	Use:   "remove <number>",
+	Short: "Remove a route by its number in 'prw route'",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid route number %q", args[0])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if !cfg.RemoveRoute(n) {
+			return fmt.Errorf("no route %d (see 'prw route')", n)
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Removed route %d\n", n)
+		return nil
+	},
+}
+
+var routeTestCmd = &cobra.Command{
+	Use:   "test <owner/repo>",
+	Short: "Show which notifiers an event would be routed to",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, ok := strings.Cut(strings.Trim(args[0], "/"), "/")
+		if !ok || owner == "" || repo == "" {
+			return fmt.Errorf("invalid repository %q (expected owner/repo)", args[0])
+		}
+		if !isNotifiedEventType(routeTestEvent) {
+			return fmt.Errorf("unknown event type %q (expected one of: %s)", routeTestEvent, strings.Join(notify.NotifiedEventTypes, ", "))
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if len(cfg.Routes) == 0 {
+			fmt.Println("No routes; every event goes to every configured notifier.")
+			return nil
+		}
+
+		event := &notify.StatusChangeEvent{
+			Type:          routeTestEvent,
+			Owner:         owner,
+			Repo:          repo,
+			Author:        routeTestAuthor,
+			Labels:        routeTestLabels,
+			PreviousState: routeTestFrom,
+			CurrentState:  routeTestTo,
+		}
+		targets := notify.NewRouter(notifyRoutes(cfg.Routes), nil).Targets(event)
+		if len(targets) == 0 {
+			fmt.Println("No route matches; the event is not sent to any notifier.")
+			return nil
+		}
+		for _, name := range targets {
+			if reason := inactiveNotifier(cfg, name); reason != "" {
+				fmt.Printf("%s (inactive: %s)\n", name, reason)
+			} else {
+				fmt.Println(name)
+			}
+		}
+		return nil
+	},
+}
+
+var notifierCmd = &cobra.Command{
+	Use:   "notifier",
+	Short: "Show the named notifiers routes can send to",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tTYPE\tDESTINATION")
+		for _, name := range config.BuiltinNotifiers {
+			destination := "built-in"
+			if reason := inactiveNotifier(cfg, name); reason != "" {
+				destination = "built-in, inactive: " + reason
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, name, destination)
+		}
+		for _, name := range cfg.NotifierNames() {
+			n := cfg.Notifiers[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, n.Type, notifierDestination(n))
+		}
+		return tw.Flush()
+	},
+}
+
+var notifierAddCmd = &cobra.Command{
+	Use:   "add <name> <webhook|slack|email>",
+	Short: "Add or replace a named notifier",
+	Long: `Add a named webhook, Slack, or email notifier for routes to send to. Named
+notifiers reuse the global settings of their type, such as the retry policy,
+slack_bot_token, or the email_smtp_* server, and change where they deliver.
+
+  prw notifier add api-ci webhook --url https://hooks.example.com/api-ci
+  prw notifier add api-team slack --channel "#api"
+  prw notifier add oncall email --to oncall@example.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, notifierType := args[0], strings.ToLower(args[1])
+		notifier := config.NotifierConfig{
+			Type:    notifierType,
+			URL:     notifierURL,
+			Token:   notifierToken,
+			Channel: notifierChannel,
+		}
+		if notifierFormat != "" {
+			format := strings.ToLower(notifierFormat)
+			if !notify.IsValidWebhookFormat(format) {
+				return fmt.Errorf("--format must be one of: auto, json, slack, discord, teams")
+			}
+			if format != notify.WebhookFormatAuto {
+				notifier.Format = format
+			}
+		}
+		if notifierTo != "" {
+			to, err := parseEmailAddresses(notifierTo)
+			if err != nil {
+				return err
+			}
+			notifier.To = to
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := cfg.SetNotifier(name, notifier); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Saved %s notifier %s\n", notifierType, name)
+		return nil
+	},
+}
+
+var notifierRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a named notifier",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		removed, err := cfg.RemoveNotifier(args[0])
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Printf("No notifier named %s.\n", args[0])
+			return nil
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Removed notifier %s\n", args[0])
+		return nil
+	},
+}
+
+// newRouter builds the routes of cfg over the built-in notifiers and the named
+// ones; console may be nil. During quiet hours each outbound notifier holds its own events, so
+// every notifier gets a summary of the events routed to it.
+func newRouter(cfg *config.Config, console notify.Notifier) *notify.Router {
+	outbound := map[string]notify.Notifier{
+		config.NotifierNative: newNativeNotifier(cfg),
+	}
+	if cfg.WebhookURL != "" {
+		outbound[config.NotifierWebhook] = newWebhookNotifier(cfg, cfg.WebhookURL, cfg.WebhookFormat)
+	}
+	if slack := newSlackNotifier(cfg); slack != nil {
+		outbound[config.NotifierSlack] = slack
+	}
+	if cfg.Email.Enabled() {
+		outbound[config.NotifierEmail] = newEmailNotifier(cfg, cfg.Email)
+	}
+	for _, name := range cfg.NotifierNames() {
+		n, err := newNamedNotifier(cfg, cfg.Notifiers[name])
+		if err != nil {
+			fmt.Printf("Warning: notifier %s: %v\n", name, err)
+			continue
+		}
+		outbound[name] = n
+	}
+
+	notifiers := make(map[string]notify.Notifier)
+	if console != nil {
+		notifiers[config.NotifierConsole] = console
+	}
+	for name, n := range outbound {
+		if quiet := cfg.QuietHours; quiet != nil && len(quiet.Windows) > 0 {
+			n = newQuietNotifier(n, quiet, name)
+		}
+		notifiers[name] = n
+	}
+	return notify.NewRouter(notifyRoutes(cfg.Routes), notifiers)
+}
+
+// newNamedNotifier creates a named notifier on top of the global settings of its type.
+func newNamedNotifier(cfg *config.Config, n config.NotifierConfig) (notify.Notifier, error) {
+	switch n.Type {
+	case config.NotifierWebhook:
+		return newWebhookNotifier(cfg, n.URL, n.Format), nil
+	case config.NotifierSlack:
+		var notifier *notify.SlackNotifier
+		if n.Channel != "" {
+			token := n.Token
+			if token == "" {
+				token = cfg.SlackBotToken
+			}
+			if token == "" {
+				return nil, fmt.Errorf("posting to %s needs a bot token (set slack_bot_token)", n.Channel)
+			}
+			notifier = notify.NewSlackBotNotifier(token, n.Channel)
+			notifier.Threads = slackThreads()
+		} else {
+			notifier = notify.NewSlackWebhookNotifier(n.URL)
+		}
+		notifier.Retry = cfg.RetryPolicy()
+		notifier.Template = messageTemplate(cfg, notify.TemplateSlack)
+		return notifier, nil
+	case config.NotifierEmail:
+		if cfg.Email == nil || cfg.Email.SMTPHost == "" || cfg.Email.From == "" {
+			return nil, fmt.Errorf("sending email needs email_smtp_host and email_from")
+		}
+		email := *cfg.Email
+		email.To = n.To
+		return newEmailNotifier(cfg, &email), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", n.Type)
+	}
+}
+
+// notifyRoutes converts configured routes for the router.
+func notifyRoutes(routes []config.Route) []notify.Route {
+	converted := make([]notify.Route, 0, len(routes))
+	for _, r := range routes {
+		converted = append(converted, notify.Route{
+			Repos:     r.Repos,
+			Authors:   r.Authors,
+			Labels:    r.Labels,
+			Events:    r.Events,
+			From:      r.From,
+			To:        r.To,
+			Notifiers: r.Notify,
+			Stop:      r.Stop,
+		})
+	}
+	return converted
+}
+
+// inactiveNotifier explains why a notifier routes can name would not deliver,
+// or returns "" if it would.
+func inactiveNotifier(cfg *config.Config, name string) string {
+	switch name {
+	case config.NotifierConsole, config.NotifierNative:
+		return ""
+	case config.NotifierWebhook:
+		if cfg.WebhookURL == "" {
+			return "webhook_url is not set"
+		}
+	case config.NotifierSlack:
+		if newSlackNotifier(cfg) == nil {
+			return "Slack is not configured"
+		}
+	case config.NotifierEmail:
+		if !cfg.Email.Enabled() {
+			return "email is not configured"
+		}
+	default:
+		n, ok := cfg.Notifiers[name]
+		if !ok {
+			return "not defined"
+		}
+		if _, err := newNamedNotifier(cfg, n); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// notifierDestination describes where a named notifier delivers.
+func notifierDestination(n config.NotifierConfig) string {
+	switch n.Type {
+	case config.NotifierWebhook:
+		format := n.Format
+		if format == "" {
+			format = notify.WebhookFormatAuto
+		}
+		return fmt.Sprintf("%s (%s)", n.URL, format)
+	case config.NotifierSlack:
+		if n.Channel != "" {
+			return n.Channel
+		}
+		return n.URL
+	case config.NotifierEmail:
+		return strings.Join(n.To, ", ")
+	default:
+		return ""
+	}
+}
+
+// describeRoute renders a route on one line, e.g. "repo org/api, to failure → api-ci".
+func describeRoute(route config.Route) string {
+	var criteria []string
+	for _, c := range []struct {
+		name   string
+		values []string
+	}{
+		{"repo", route.Repos},
+		{"author", route.Authors},
+		{"label", route.Labels},
+		{"event", route.Events},
+		{"from", route.From},
+		{"to", route.To},
+	} {
+		if len(c.values) > 0 {
+			criteria = append(criteria, c.name+" "+strings.Join(c.values, ","))
+		}
+	}
+	description := "every event"
+	if len(criteria) > 0 {
+		description = strings.Join(criteria, ", ")
+	}
+	description += " → " + strings.Join(route.Notify, ", ")
+	if route.Stop {
+		description += " (stop)"
+	}
+	return description
+}
+
+// isNotifiedEventType reports whether eventType is delivered to notifiers.
+func isNotifiedEventType(eventType string) bool {
+	for _, t := range notify.NotifiedEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+func TestRouteAndNotifierCmds(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	addRoute := func(repos, to, notifiers []string, stop bool) error {
+		routeRepos, routeTo, routeNotify, routeStop = repos, to, notifiers, stop
+		defer func() { routeRepos, routeTo, routeNotify, routeStop = nil, nil, nil, false }()
+		_, err := captureStdout(func() error { return routeAddCmd.RunE(routeAddCmd, nil) })
+		return err
+	}
+
+	if err := addRoute(nil, nil, []string{"api-ci"}, false); err == nil {
+		t.Error("expected a route to an undefined notifier to be rejected")
+	}
+
+	notifierURL = "https://hooks.example.com/api-ci"
+	_, err := captureStdout(func() error { return notifierAddCmd.RunE(notifierAddCmd, []string{"api-ci", "webhook"}) })
+	notifierURL = ""
+	if err != nil {
+		t.Fatalf("notifier add: %v", err)
+	}
+	if _, err := captureStdout(func() error { return notifierAddCmd.RunE(notifierAddCmd, []string{"console", "webhook"}) }); err == nil {
+		t.Error("expected a built-in notifier name to be rejected")
+	}
+
+	if err := addRoute([]string{"org/api"}, []string{"failure", "error"}, []string{"api-ci"}, false); err != nil {
+		t.Fatalf("route add: %v", err)
+	}
+	if err := addRoute(nil, []string{"success"}, []string{"native"}, true); err != nil {
+		t.Fatalf("route add: %v", err)
+	}
+	if err := addRoute(nil, nil, []string{"console"}, false); err != nil {
+		t.Fatalf("route add: %v", err)
+	}
+
+	output, _ := captureStdout(func() error { return routeCmd.RunE(routeCmd, nil) })
+	want := "1. repo org/api, to failure,error → api-ci\n2. to success → native (stop)\n3. every event → console\n"
+	if output != want {
+		t.Errorf("unexpected route list:\n%s", output)
+	}
+
+	output, err = captureStdout(func() error { return routeTestCmd.RunE(routeTestCmd, []string{"org/api"}) })
+	if err != nil {
+		t.Fatalf("route test: %v", err)
+	}
+	if output != "api-ci\nconsole\n" {
+		t.Errorf("unexpected route test output %q", output)
+	}
+
+	output, _ = captureStdout(func() error { return notifierCmd.RunE(notifierCmd, nil) })
+	if !strings.Contains(output, "webhook_url is not set") || !strings.Contains(output, "https://hooks.example.com/api-ci (auto)") {
+		t.Errorf("unexpected notifier list:\n%s", output)
+	}
+
+	if _, err := captureStdout(func() error { return notifierRemoveCmd.RunE(notifierRemoveCmd, []string{"api-ci"}) }); err == nil {
+		t.Error("expected removing a notifier used by a route to fail")
+	}
+	if _, err := captureStdout(func() error { return routeRemoveCmd.RunE(routeRemoveCmd, []string{"1"}) }); err != nil {
+		t.Fatalf("route remove: %v", err)
+	}
+	if _, err := captureStdout(func() error { return notifierRemoveCmd.RunE(notifierRemoveCmd, []string{"api-ci"}) }); err != nil {
+		t.Fatalf("notifier remove: %v", err)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.Routes) != 2 || loaded.Notifiers != nil {
+		t.Errorf("unexpected config after removal: routes %v, notifiers %v", loaded.Routes, loaded.Notifiers)
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if err := cfg.SetNotifier("api-ci", config.NotifierConfig{Type: config.NotifierWebhook, URL: "https://hooks.example.com/api-ci"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetNotifier("team", config.NotifierConfig{Type: config.NotifierSlack, Channel: "#api"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Routes = []config.Route{{Repos: []string{"org/*"}, Notify: []string{"api-ci", "team", "webhook"}}}
+
+	var router *notify.Router
+	output, _ := captureStdout(func() error {
+		router = newRouter(cfg, notify.NewConsoleNotifier())
+		return nil
+	})
+	if !strings.Contains(output, "Warning: notifier team: posting to #api needs a bot token") {
+		t.Errorf("expected the incomplete Slack notifier to be reported, got %q", output)
+	}
+
+	event := &notify.StatusChangeEvent{Owner: "org", Repo: "api", CurrentState: "failure"}
+	if got := router.Targets(event); !reflect.DeepEqual(got, []string{"api-ci", "team", "webhook"}) {
+		t.Errorf("Targets() = %v", got)
+	}
+	if got := router.Targets(&notify.StatusChangeEvent{Owner: "other", Repo: "api"}); len(got) != 0 {
+		t.Errorf("expected no targets outside org, got %v", got)
+	}
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+	"github.com/devblac/prw/internal/watcher"
+	"github.com/devblac/prw/internal/webhook"
+)
+
+// defaultEventLimit is the number of events returned by /api/events without ?limit.
+const defaultEventLimit = 50
+
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+var serveAddr string
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().BoolVar(&notifyNative, "notify-native", false, "enable native OS notifications (macOS/Linux/Windows)")
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the watcher with a local HTTP API and dashboard",
+	Long: `Run the watcher loop in the background and serve a JSON API and a live
+dashboard on a local address.
+
+API:
+  GET    /api/prs                          watched PRs (same shape as 'prw list --json')
+  POST   /api/prs                          watch a PR: {"url": "<PR_URL>"}
+  DELETE /api/prs/{owner}/{repo}/{number}  unwatch a PR (?host= for Enterprise hosts)
+  POST   /api/check                        check all PRs now
+  GET    /api/events?limit=N               recent status changes
+  GET    /api/stream                       Server-Sent Events on changes
+  POST   /webhook                          GitHub webhook deliveries (when github_webhook_secret is set)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		hub := newEventHub()
+		w, err := newWatcher(cfg, newNotifier(cfg, hub))
+		if err != nil {
+			return err
+		}
+		w.OnCycle(func() { hub.publish("cycle", struct{}{}) })
+
+		httpServer, listener, err := startHTTPServer(serveAddr, newServer(cfg, w, hub, serveAddr).routes())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dashboard: http://%s/\n", listener.Addr())
+		if cfg.GitHubWebhookSecret != "" {
+			fmt.Printf("Receiving GitHub webhooks at http://%s%s\n", listener.Addr(), webhookPath)
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+
+		err = w.RunBackground(ctx)
+
+		// Stream handlers only return once their clients go away, so close them first.
+		hub.close()
+		stopHTTPServer(httpServer)
+
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	},
+}
+
+// server implements the HTTP API on top of a running watcher.
+type server struct {
+	cfg     *config.Config
+	watcher *watcher.Watcher
+	hub     *eventHub
+	// addr is the --addr the server listens on. Its host name is accepted in
+	// the Host header besides localhost and the IP the request came in on.
+	addr string
+}
+
+func newServer(cfg *config.Config, w *watcher.Watcher, hub *eventHub, addr string) *server {
+	return &server{cfg: cfg, watcher: w, hub: hub, addr: addr}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.localOnly(s.handleDashboard))
+	mux.HandleFunc("GET /api/prs", s.localOnly(s.handleListPRs))
+	mux.HandleFunc("POST /api/prs", s.localOnly(s.handleAddPR))
+	mux.HandleFunc("DELETE /api/prs/{owner}/{repo}/{number}", s.localOnly(s.handleRemovePR))
+	mux.HandleFunc("POST /api/check", s.localOnly(s.handleCheck))
+	mux.HandleFunc("GET /api/events", s.localOnly(s.handleEvents))
+	mux.HandleFunc("GET /api/stream", s.localOnly(s.handleStream))
+	if s.cfg.GitHubWebhookSecret != "" {
+		mux.Handle("POST "+webhookPath, webhook.NewHandler(s.cfg.GitHubWebhookSecret, deliverWebhook(s.watcher)))
+	}
+	return mux
+}
+
+// localOnly rejects requests that a web page on another site could make
+// through the user's browser. The Host header must name the listen address, so
+// a DNS rebinding attack can't reach the API, and requests from another Origin
+// or POSTs without a JSON body, which browsers send cross-site without a
+// preflight, are refused. The webhook route is signed and not wrapped.
+func (s *server) localOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.isListenHost(r, r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not the listen address", r.Host))
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Scheme != "http" || !s.isListenHost(r, u.Host) {
+				writeError(w, http.StatusForbidden, fmt.Errorf("origin %q is not allowed", origin))
+				return
+			}
+		}
+		if r.Method == http.MethodPost {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("Content-Type must be application/json"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// isListenHost reports whether hostport names the address r was received on:
+// the same port, and localhost, the local IP of the connection, or the host
+// given with --addr.
+func (s *server) isListenHost(r *http.Request, hostport string) bool {
+	local, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return false
+	}
+	localHost, localPort, err := net.SplitHostPort(local.String())
+	if err != nil {
+		return false
+	}
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		// No port is the default HTTP port
+		host, port = hostport, "80"
+	}
+	if port != localPort {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.Equal(net.ParseIP(localHost))
+	}
+	addrHost, _, err := net.SplitHostPort(s.addr)
+	return err == nil && addrHost != "" && strings.EqualFold(host, addrHost)
+}
+
+func (s *server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(dashboardHTML)
+}
+
+func (s *server) handleListPRs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, listOutput(s.watcher.WatchedPRs()))
+}
+
+func (s *server) handleAddPR(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	pr, client, err := resolvePR(s.cfg, req.URL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !s.watcher.HasClient(pr.Host) {
+		s.watcher.SetClient(pr.Host, client)
+	}
+
+	added, err := s.watcher.AddPR(pr)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to save config: %w", err))
+		return
+	}
+
+	status := http.StatusOK
+	if added {
+		status = http.StatusCreated
+		s.hub.publish("update", struct{}{})
+		s.watcher.CheckNow()
+	}
+	writeJSON(w, status, listOutput([]config.WatchedPR{pr})[0])
+}
+
+func (s *server) handleRemovePR(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid PR number %q", r.PathValue("number")))
+		return
+	}
+	host := r.URL.Query().Get("host")
+
+	removed, err := s.watcher.RemovePR(host, r.PathValue("owner"), r.PathValue("repo"), number)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to save config: %w", err))
+		return
+	}
+	if !removed {
+		writeError(w, http.StatusNotFound, fmt.Errorf("PR %s/%s#%d is not being watched", r.PathValue("owner"), r.PathValue("repo"), number))
+		return
+	}
+
+	s.hub.publish("update", struct{}{})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	s.watcher.CheckNow()
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "scheduled"})
+}
+
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	limit := defaultEventLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+
+	records := []history.Record{}
+	if store := s.watcher.History(); store != nil {
+		found, err := store.Query(history.Filter{Limit: limit})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		records = append(records, found...)
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (s *server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	messages, unsubscribe := s.hub.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.name, msg.data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// sseMessage is a single Server-Sent Event.
+type sseMessage struct {
+	name string
+	data []byte
+}
+
+// eventHub fans out watcher events to Server-Sent Events subscribers. It is
+// also a notifier so it can sit in the watcher's notifier chain.
+type eventHub struct {
+	mu     sync.Mutex
+	subs   map[chan sseMessage]struct{}
+	closed bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan sseMessage]struct{})}
+}
+
+// Notify publishes a status change to all subscribers.
+func (h *eventHub) Notify(event *notify.StatusChangeEvent) error {
+	h.publish("status", history.FromEvent(event))
+	return nil
+}
+
+// publish sends a message to every subscriber. Slow subscribers miss messages
+// rather than blocking the watcher.
+func (h *eventHub) publish(name string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- sseMessage{name: name, data: data}:
+		default:
+		}
+	}
+}
+
+func (h *eventHub) subscribe() (<-chan sseMessage, func()) {
+	ch := make(chan sseMessage, 16)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subs[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// close disconnects all subscribers.
+func (h *eventHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.subs {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
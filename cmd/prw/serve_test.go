@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// newTestAPI starts the serve API backed by a fake GitHub server.
+func newTestAPI(t *testing.T) (*httptest.Server, *config.Config, *eventHub) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+	oldConfigPath := config.ConfigPath
+	t.Cleanup(func() { config.ConfigPath = oldConfigPath })
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pulls/404"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		case strings.HasSuffix(r.URL.Path, "/reviews"):
+			fmt.Fprint(w, `[]`)
+		case strings.Contains(r.URL.Path, "/pulls/"):
+			fmt.Fprint(w, `{"number":7,"title":"Served PR","state":"open","head":{"sha":"abc"}}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ghServer.Close)
+
+	oldNewGitHubClient := newGitHubClient
+	t.Cleanup(func() { newGitHubClient = oldNewGitHubClient })
+	newGitHubClient = func(host, token string) *github.Client {
+		c := github.NewClient(token)
+		c.BaseURL = ghServer.URL
+		c.HTTPClient = ghServer.Client()
+		return c
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	cfg.WatchedPRs = []config.WatchedPR{
+		{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "success", Title: "Existing"},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	hub := newEventHub()
+	w, err := newWatcher(cfg, hub)
+	if err != nil {
+		t.Fatalf("newWatcher failed: %v", err)
+	}
+
+	api := httptest.NewServer(newServer(cfg, w, hub, "").routes())
+	t.Cleanup(api.Close)
+	t.Cleanup(hub.close)
+	return api, cfg, hub
+}
+
+func doRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServeListPRs(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	resp := doRequest(t, "GET", api.URL+"/api/prs", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var prs []listPROutput
+	if err := json.NewDecoder(resp.Body).Decode(&prs); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Owner != "owner" || prs[0].Status != "success" || prs[0].Title != "Existing" {
+		t.Errorf("unexpected PRs: %+v", prs)
+	}
+}
+
+func TestServeAddAndRemovePR(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	resp := doRequest(t, "POST", api.URL+"/api/prs", `{"url":"https://github.com/owner/repo/pull/7"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	var added listPROutput
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if added.Number != 7 || added.Title != "Served PR" || added.Status != "unknown" {
+		t.Errorf("unexpected added PR: %+v", added)
+	}
+
+	if resp := doRequest(t, "POST", api.URL+"/api/prs", `{"url":"https://github.com/owner/repo/pull/7"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for already watched PR, got %d", resp.StatusCode)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.WatchedPRs) != 2 {
+		t.Fatalf("expected added PR to be saved, got %+v", loaded.WatchedPRs)
+	}
+
+	if resp := doRequest(t, "DELETE", api.URL+"/api/prs/owner/repo/7", ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, "DELETE", api.URL+"/api/prs/owner/repo/7", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unwatched PR, got %d", resp.StatusCode)
+	}
+
+	loaded, _ = config.Load()
+	if len(loaded.WatchedPRs) != 1 {
+		t.Errorf("expected removal to be saved, got %+v", loaded.WatchedPRs)
+	}
+}
+
+func TestServeAddPRErrors(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid JSON", body: `{`},
+		{name: "invalid URL", body: `{"url":"not a url"}`},
+		{name: "unknown host", body: `{"url":"https://ghe.example.com/owner/repo/pull/1"}`},
+		{name: "missing PR", body: `{"url":"https://github.com/owner/repo/pull/404"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := doRequest(t, "POST", api.URL+"/api/prs", tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", resp.StatusCode)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body["error"] == "" {
+				t.Errorf("expected JSON error body, got %v (%v)", body, err)
+			}
+		})
+	}
+}
+
+func TestServeRejectsCrossSiteRequests(t *testing.T) {
+	api, cfg, _ := newTestAPI(t)
+	_, port, _ := strings.Cut(strings.TrimPrefix(api.URL, "http://"), ":")
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		host        string
+		origin      string
+		contentType string
+		want        int
+	}{
+		{"form post", "POST", "/api/prs", "", "", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"text post", "POST", "/api/check", "", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"no content type", "POST", "/api/check", "", "", "", http.StatusUnsupportedMediaType},
+		{"other origin", "POST", "/api/prs", "", "http://evil.example", "application/json", http.StatusForbidden},
+		{"other origin delete", "DELETE", "/api/prs/owner/repo/1", "", "https://evil.example", "", http.StatusForbidden},
+		{"rebound host", "GET", "/api/prs", "evil.example:" + port, "", "", http.StatusForbidden},
+		{"rebound dashboard", "GET", "/", "evil.example:" + port, "", "", http.StatusForbidden},
+		{"other port", "GET", "/api/prs", "127.0.0.1:1", "", "", http.StatusForbidden},
+		{"localhost", "GET", "/api/prs", "localhost:" + port, "", "", http.StatusOK},
+		{"own origin", "POST", "/api/check", "", api.URL, "application/json; charset=utf-8", http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, api.URL+tt.path, strings.NewReader(`{"url":"https://github.com/owner/repo/pull/7"}`))
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+
+	if len(cfg.WatchedPRs) != 1 {
+		t.Errorf("expected rejected requests not to change the watched PRs, got %+v", cfg.WatchedPRs)
+	}
+}
+
+func TestServeCheckAndEvents(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	if resp := doRequest(t, "POST", api.URL+"/api/check", ""); resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", resp.StatusCode)
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		t.Fatalf("failed to open history: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		store.RecordEvent(&notify.StatusChangeEvent{Owner: "owner", Repo: "repo", Number: i, CurrentState: "failure", Timestamp: time.Now()})
+	}
+
+	resp := doRequest(t, "GET", api.URL+"/api/events?limit=2", "")
+	var records []history.Record
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(records) != 2 || records[0].Number != 2 || records[1].Number != 3 {
+		t.Errorf("expected the two most recent events, got %+v", records)
+	}
+
+	if resp := doRequest(t, "GET", api.URL+"/api/events?limit=zero", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid limit, got %d", resp.StatusCode)
+	}
+}
+
+func TestServeDashboard(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	resp := doRequest(t, "GET", api.URL+"/", "")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Fatalf("expected HTML dashboard, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if resp := doRequest(t, "GET", api.URL+"/nope", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown paths, got %d", resp.StatusCode)
+	}
+}
+
+func TestServeStream(t *testing.T) {
+	api, _, hub := newTestAPI(t)
+
+	resp := doRequest(t, "GET", api.URL+"/api/stream", "")
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	// Wait for the connection comment so the subscription is registered
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, ": connected") {
+		t.Fatalf("expected connected comment, got %q (%v)", line, err)
+	}
+
+	hub.Notify(&notify.StatusChangeEvent{Owner: "owner", Repo: "repo", Number: 1, PreviousState: "pending", CurrentState: "success"})
+
+	var lines []string
+	for len(lines) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if lines[0] != "event: status" || !strings.Contains(lines[1], `"current_state":"success"`) {
+		t.Errorf("unexpected stream message: %v", lines)
+	}
+}
+
+func TestServeStreamSurvivesFailingNotifier(t *testing.T) {
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer webhookServer.Close()
+
+	for _, routes := range [][]config.Route{nil, {{Notify: []string{"webhook"}}}} {
+		cfg := config.DefaultConfig()
+		cfg.WebhookURL = webhookServer.URL
+		cfg.WebhookFormat = notify.WebhookFormatJSON
+		cfg.Routes = routes
+
+		hub := newEventHub()
+		messages, unsubscribe := hub.subscribe()
+		_, err := captureStdout(func() error {
+			return newNotifier(cfg, hub).Notify(&notify.StatusChangeEvent{Owner: "owner", Repo: "repo", Number: 1, CurrentState: "failure"})
+		})
+		if err == nil {
+			t.Error("expected the webhook failure to be reported")
+		}
+		select {
+		case msg := <-messages:
+			if msg.name != "status" {
+				t.Errorf("unexpected stream message %+v", msg)
+			}
+		default:
+			t.Errorf("expected the event to reach the stream despite the failing webhook (routes: %v)", routes)
+		}
+		unsubscribe()
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+)
+
+var (
+	statsSince string
+	statsJSON  bool
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVar(&statsSince, "since", "", "only count CI runs after this time (duration like 12h or 7d, or a date/RFC3339 time)")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "output stats as JSON")
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [owner/repo]",
+	Short: "Show CI duration statistics per repo",
+	Long: `Show how long CI took on watched PRs, from entering pending to a result,
+per repo: the number of runs, the average and 90th percentile duration, and how
+often CI was reported as stuck. Durations come from the history recorded by
+'prw run'.
+
+Pass owner/repo, or just owner, to limit the report.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filter history.Filter
+		if len(args) == 1 {
+			owner, repo, _ := strings.Cut(strings.Trim(args[0], "/"), "/")
+			if owner == "" || strings.Contains(repo, "/") {
+				return fmt.Errorf("invalid repo %q (expected owner/repo or owner)", args[0])
+			}
+			filter.Owner, filter.Repo = owner, repo
+		}
+		since, err := parseHistoryTime(statsSince, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		filter.Since = since
+
+		store, err := history.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open history: %w", err)
+		}
+		records, err := store.Query(filter)
+		if err != nil {
+			return err
+		}
+
+		stats := ciStats(records)
+
+		if statsJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(stats)
+		}
+
+		if len(stats) == 0 {
+			fmt.Println("No CI durations recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "REPO\tRUNS\tAVG\tP90\tSTUCK")
+		fmt.Fprintln(w, "----\t----\t---\t---\t-----")
+		for _, s := range stats {
+			repo := fmt.Sprintf("%s/%s", s.Owner, s.Repo)
+			if s.Host != "" {
+				repo = s.Host + "/" + repo
+			}
+			avg, p90 := "-", "-"
+			if s.Runs > 0 {
+				avg = notify.FormatDuration(time.Duration(s.AvgSeconds * float64(time.Second)))
+				p90 = notify.FormatDuration(time.Duration(s.P90Seconds) * time.Second)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\n", repo, s.Runs, avg, p90, s.Stuck)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+// repoCIStats summarizes the CI durations of one repo.
+type repoCIStats struct {
+	Host       string  `json:"host,omitempty"`
+	Owner      string  `json:"owner"`
+	Repo       string  `json:"repo"`
+	Runs       int     `json:"runs"`
+	AvgSeconds float64 `json:"avg_seconds"`
+	P90Seconds int64   `json:"p90_seconds"`
+	Stuck      int     `json:"stuck"`
+
+	durations []int64
+}
+
+// ciStats computes per-repo CI duration statistics from history records, sorted by repo.
+// CI results with a recorded duration count as runs; stuck events are counted separately.
+func ciStats(records []history.Record) []repoCIStats {
+	byRepo := make(map[string]*repoCIStats)
+	var keys []string
+	for _, r := range records {
+		isRun := r.Type == notify.EventStatusChange && r.DurationSeconds > 0
+		if r.Kind != history.KindEvent || (!isRun && r.Type != notify.EventStuck) {
+			continue
+		}
+
+		host := github.NormalizeHost(r.Host)
+		key := strings.ToLower(host + "/" + r.Owner + "/" + r.Repo)
+		s, ok := byRepo[key]
+		if !ok {
+			s = &repoCIStats{Owner: r.Owner, Repo: r.Repo}
+			if host != github.DefaultHost {
+				s.Host = host
+			}
+			byRepo[key] = s
+			keys = append(keys, key)
+		}
+		if isRun {
+			s.durations = append(s.durations, r.DurationSeconds)
+		} else {
+			s.Stuck++
+		}
+	}
+
+	sort.Strings(keys)
+	stats := make([]repoCIStats, 0, len(keys))
+	for _, key := range keys {
+		s := byRepo[key]
+		s.Runs = len(s.durations)
+		if s.Runs > 0 {
+			var total int64
+			for _, d := range s.durations {
+				total += d
+			}
+			s.AvgSeconds = float64(total) / float64(s.Runs)
+			s.P90Seconds = percentile(s.durations, 90)
+		}
+		stats = append(stats, *s)
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of values using the nearest-rank method.
+func percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
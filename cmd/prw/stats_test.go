@@ -0,0 +1,92 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+)
+
+func TestStatsCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+
+	store, err := history.Open()
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	now := time.Now()
+	run := func(owner, repo string, minutes int) history.Record {
+		return history.Record{Kind: history.KindEvent, Type: notify.EventStatusChange, Owner: owner, Repo: repo, Number: 1,
+			PreviousState: "pending", CurrentState: "success", DurationSeconds: int64(minutes * 60), Timestamp: now}
+	}
+	records := []history.Record{
+		run("owner", "repo", 10),
+		run("owner", "repo", 20),
+		run("owner", "repo", 30),
+		run("owner", "other", 5),
+		{Kind: history.KindEvent, Type: notify.EventStuck, Owner: "owner", Repo: "repo", Number: 1, CurrentState: "pending", DurationSeconds: 7200, Timestamp: now},
+		{Kind: history.KindEvent, Type: notify.EventStatusChange, Owner: "owner", Repo: "repo", Number: 1, CurrentState: "pending", Timestamp: now},
+		{Kind: history.KindEvent, Type: notify.EventMerged, Owner: "someone", Repo: "else", Number: 2, CurrentState: "merged", Timestamp: now},
+	}
+	for _, r := range records {
+		if err := store.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	defer func() { statsSince = "" }()
+
+	output, err := captureStdout(func() error {
+		return statsCmd.RunE(statsCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("statsCmd.RunE() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and two repos, got:\n%s", output)
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "owner/other 1 5m0s 5m0s 0" {
+		t.Errorf("unexpected owner/other row: %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); strings.Join(fields, " ") != "owner/repo 3 20m0s 30m0s 1" {
+		t.Errorf("unexpected owner/repo row: %q", lines[3])
+	}
+
+	statsSince = "1h"
+	output, err = captureStdout(func() error {
+		return statsCmd.RunE(statsCmd, []string{"someone/else"})
+	})
+	if err != nil || !strings.Contains(output, "No CI durations recorded.") {
+		t.Errorf("expected no durations for someone/else, got %q (%v)", output, err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{50, 10, 40, 20, 30, 60, 70, 80, 90, 100}
+	tests := []struct {
+		p    float64
+		want int64
+	}{
+		{90, 90},
+		{50, 50},
+		{100, 100},
+		{1, 10},
+	}
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 90); got != 0 {
+		t.Errorf("percentile of no values = %d", got)
+	}
+}
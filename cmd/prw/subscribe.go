@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+var subscribeHost string
+
+func init() {
+	rootCmd.AddCommand(subscribeCmd)
+	rootCmd.AddCommand(unsubscribeCmd)
+	subscribeCmd.Flags().StringVar(&subscribeHost, "host", "", "GitHub Enterprise Server host to search (default: github.com)")
+	unsubscribeCmd.Flags().StringVar(&subscribeHost, "host", "", "GitHub Enterprise Server host to search (default: github.com)")
+}
+
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe [repo|author|review-requested|search] [value]",
+	Short: "Watch every open PR in a repo, by an author, or matching a search",
+	Long: `Subscribe to a set of PRs. Every watcher cycle runs the subscription's search,
+watches new matching PRs, and drops the PRs it added once they no longer match,
+e.g. after they are merged or closed. PRs added with 'prw watch' are never dropped.
+
+  prw subscribe repo owner/repo             every open PR in owner/repo
+  prw subscribe author octocat              every open PR opened by octocat
+  prw subscribe review-requested [user]     every open PR requesting your (or user's) review
+  prw subscribe search "org:acme label:ci"  every open PR matching a GitHub search query
+
+Without arguments, lists the subscriptions.`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if len(args) == 0 {
+			listSubscriptions(cfg)
+			return nil
+		}
+
+		sub, err := subscriptionFromArgs(args)
+		if err != nil {
+			return err
+		}
+		host := sub.HostName()
+		if !cfg.IsConfiguredHost(host) {
+			return fmt.Errorf("unknown GitHub host %s; add it with 'prw config set github_token <token> --host %s'", host, host)
+		}
+		if !cfg.AddSubscription(sub) {
+			fmt.Printf("Already subscribed to %s.\n", sub.Key())
+			return nil
+		}
+
+		// Run the search once to validate the query and watch the current matches
+		token, err := hostToken(cfg, host)
+		if err != nil {
+			return err
+		}
+		refs, err := newConfiguredClient(cfg, host, token).SearchPullRequests(sub.Query())
+		if err != nil {
+			return fmt.Errorf("failed to search PRs: %w", err)
+		}
+		added, _ := cfg.ReconcileSubscriptions(map[string][]github.PullRequestRef{sub.Key(): refs})
+
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("Subscribed to %s (%d open PRs, %d newly watched).\n", sub.Key(), len(refs), len(added))
+		for _, pr := range added {
+			fmt.Printf("Now watching: %s/%s#%d - %s\n", pr.Owner, pr.Repo, pr.Number, pr.Title)
+		}
+		return nil
+	},
+}
+
+var unsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <repo|author|review-requested|search> [value]",
+	Short: "Remove a subscription and the PRs it added",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		sub, err := subscriptionFromArgs(args)
+		if err != nil {
+			return err
+		}
+		ok, removed := cfg.RemoveSubscription(sub)
+		if !ok {
+			fmt.Printf("Not subscribed to %s.\n", sub.Key())
+			return nil
+		}
+
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("Unsubscribed from %s (stopped watching %d PRs).\n", sub.Key(), len(removed))
+		return nil
+	},
+}
+
+// subscriptionFromArgs builds a subscription from the kind and value arguments and --host.
+func subscriptionFromArgs(args []string) (config.Subscription, error) {
+	value := ""
+	if len(args) > 1 {
+		value = args[1]
+	}
+	return config.NewSubscription(subscribeHost, args[0], value)
+}
+
+// listSubscriptions prints the subscriptions and how many watched PRs each added.
+func listSubscriptions(cfg *config.Config) {
+	if len(cfg.Subscriptions) == 0 {
+		fmt.Println("No subscriptions. Add one with 'prw subscribe repo owner/repo'.")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, pr := range cfg.WatchedPRs {
+		if pr.Subscription != "" {
+			counts[pr.Subscription]++
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBSCRIPTION\tWATCHED PRS\tQUERY")
+	fmt.Fprintln(w, "------------\t-----------\t-----")
+	for _, sub := range cfg.Subscriptions {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", sub.Key(), counts[sub.Key()], sub.Query())
+	}
+	w.Flush()
+}
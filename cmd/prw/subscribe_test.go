@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+func TestSubscribeCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	cfg.AddPR(config.WatchedPR{Owner: "owner", Repo: "repo", Number: 1})
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/search/issues" && r.URL.Query().Get("q") == "is:pr is:open repo:owner/repo":
+			fmt.Fprint(w, `{"total_count": 2, "items": [
+				{"number": 1, "title": "Explicit", "repository_url": "https://api.github.com/repos/owner/repo", "pull_request": {}},
+				{"number": 2, "title": "Found", "repository_url": "https://api.github.com/repos/owner/repo", "pull_request": {}}
+			]}`)
+		case r.URL.Path == "/repos/owner/repo/pulls/2":
+			fmt.Fprint(w, `{"number": 2, "title": "Found", "state": "open", "head": {"sha": "abc"}}`)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprintf(w, `{"message": "unexpected request %s"}`, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	oldNewGitHubClient := newGitHubClient
+	newGitHubClient = func(host, token string) *github.Client {
+		client := github.NewClient(token)
+		client.BaseURL = server.URL
+		client.HTTPClient = server.Client()
+		return client
+	}
+	defer func() { newGitHubClient = oldNewGitHubClient }()
+
+	output, err := captureStdout(func() error {
+		return subscribeCmd.RunE(subscribeCmd, []string{"repo", "owner/repo"})
+	})
+	if err != nil {
+		t.Fatalf("subscribeCmd.RunE() error = %v", err)
+	}
+	if !strings.Contains(output, "Subscribed to repo:owner/repo (2 open PRs, 1 newly watched)") {
+		t.Errorf("unexpected output: %s", output)
+	}
+
+	loaded, _ := config.Load()
+	if len(loaded.Subscriptions) != 1 || len(loaded.WatchedPRs) != 2 {
+		t.Fatalf("expected one subscription and two watched PRs, got %+v", loaded)
+	}
+	if loaded.WatchedPRs[0].Subscription != "" || loaded.WatchedPRs[1].Subscription != "repo:owner/repo" {
+		t.Errorf("expected only the new PR to belong to the subscription, got %+v", loaded.WatchedPRs)
+	}
+
+	output, _ = captureStdout(func() error {
+		return subscribeCmd.RunE(subscribeCmd, []string{})
+	})
+	if !strings.Contains(output, "repo:owner/repo") || !strings.Contains(output, "is:pr is:open repo:owner/repo") {
+		t.Errorf("expected the subscription to be listed, got: %s", output)
+	}
+
+	// Watching a subscribed PR explicitly keeps it after unsubscribing.
+	if _, err := captureStdout(func() error {
+		return watchCmd.RunE(watchCmd, []string{"https://github.com/owner/repo/pull/2"})
+	}); err != nil {
+		t.Fatalf("watchCmd.RunE() error = %v", err)
+	}
+	output, err = captureStdout(func() error {
+		return unsubscribeCmd.RunE(unsubscribeCmd, []string{"repo", "owner/repo"})
+	})
+	if err != nil {
+		t.Fatalf("unsubscribeCmd.RunE() error = %v", err)
+	}
+	if !strings.Contains(output, "stopped watching 0 PRs") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	loaded, _ = config.Load()
+	if len(loaded.Subscriptions) != 0 || len(loaded.WatchedPRs) != 2 {
+		t.Errorf("expected both explicit watches to remain, got %+v", loaded)
+	}
+}
+
+func TestSubscribeCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	if err := subscribeCmd.RunE(subscribeCmd, []string{"team", "platform"}); err == nil {
+		t.Error("expected unknown subscription kind to be rejected")
+	}
+
+	subscribeHost = "github.example.com"
+	defer func() { subscribeHost = "" }()
+	err := subscribeCmd.RunE(subscribeCmd, []string{"repo", "owner/repo"})
+	if err == nil || !strings.Contains(err.Error(), "unknown GitHub host github.example.com") {
+		t.Errorf("expected unknown host error, got %v", err)
+	}
+}
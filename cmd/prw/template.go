@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+var (
+	templateFile string
+	templateText string
+)
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateSetCmd)
+	templateCmd.AddCommand(templateUnsetCmd)
+	templateCmd.AddCommand(templateTestCmd)
+	templateSetCmd.Flags().StringVar(&templateFile, "file", "", "read the template from a file instead of the argument")
+	templateTestCmd.Flags().StringVar(&templateText, "template", "", "render this template instead of the configured one")
+	templateTestCmd.Flags().StringVar(&templateFile, "file", "", "render the template in this file instead of the configured one")
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Customize notification messages",
+	Long: `Replace the built-in messages of a notifier with Go text/template templates.
+Templates are set per notifier and per event type; a "default" template covers
+the event types without a template of their own.
+
+Notifiers: console, native, webhook, slack, email
+  console  the text printed by 'prw run'
+  native   the notification; the first line is the title, the rest the message
+  webhook  the JSON body posted to webhook_url (json, discord, and teams formats)
+  slack    the mrkdwn message text, for slack_webhook_url, the bot, and webhook_url
+           in slack format
+  email    the email of one event; the first line is the subject, the rest the body
+
+Event types: default, status_change, merged, closed, approved, changes_requested,
+review_requested, ready_to_merge, pushed, force_pushed, stuck
+
+Templates see every event field, e.g. {{.Owner}}, {{.Repo}}, {{.Number}},
+{{.Title}}, {{.PreviousState}}, {{.CurrentState}}, {{.SHA}}, {{.Checks}},
+{{.Reviewers}}, {{.Duration}}, and {{.Timestamp}}, plus these functions:
+  prURL .        the PR's web URL           duration .Duration  e.g. 7m42s
+  emoji .State   emoji for a state or type  headline .          e.g. "CI failed (pending → failure)"
+  failing .Checks  names of failing checks  shortSHA .SHA       first 7 characters
+  join, upper, lower                        json                JSON-encode a value
+
+Example:
+  prw template set console status_change '{{emoji .CurrentState}} {{.Repo}}#{{.Number}} {{.CurrentState}} {{prURL .}}'
+  prw template test console status_change`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list [notifier]",
+	Short: "Show the configured templates",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		notifiers := notify.TemplateNotifiers
+		if len(args) == 1 {
+			if !notify.IsValidTemplateNotifier(args[0]) {
+				return invalidTemplateNotifier(args[0])
+			}
+			notifiers = args[:1]
+		}
+
+		found := false
+		for _, notifier := range notifiers {
+			for _, event := range cfg.TemplateKeys(notifier) {
+				fmt.Printf("%s %s:\n", notifier, event)
+				for _, line := range strings.Split(cfg.Templates[notifier][event], "\n") {
+					fmt.Printf("  %s\n", line)
+				}
+				found = true
+			}
+		}
+		if !found {
+			fmt.Println("No templates configured; notifiers use their built-in messages.")
+		}
+		return nil
+	},
+}
+
+var templateSetCmd = &cobra.Command{
+	Use:   "set <notifier> <event> [template]",
+	Short: "Set the template of a notifier for an event type",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifier, event := args[0], args[1]
+		if err := validateTemplateTarget(notifier, event); err != nil {
+			return err
+		}
+		text, err := templateSource(args[2:])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Check the template against a sample event before saving it, so that
+		// mistakes such as unknown fields don't surface during 'prw run'.
+		tmpl, err := notify.NewMessageTemplate(notifier, map[string]string{event: text})
+		if err != nil {
+			return err
+		}
+		if _, _, err := tmpl.Render(notify.SampleEvent(sampleEventType(event))); err != nil {
+			return err
+		}
+
+		cfg.SetTemplate(notifier, event, text)
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Set %s template for %s\n", notifier, event)
+		return nil
+	},
+}
+
+var templateUnsetCmd = &cobra.Command{
+	Use:   "unset <notifier> [event]",
+	Short: "Remove a template, or all templates of a notifier",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifier, event := args[0], ""
+		if len(args) == 2 {
+			event = args[1]
+		}
+		if !notify.IsValidTemplateNotifier(notifier) {
+			return invalidTemplateNotifier(notifier)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if !cfg.RemoveTemplate(notifier, event) {
+			fmt.Println("No such template.")
+			return nil
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		if event == "" {
+			fmt.Printf("✓ Removed %s templates\n", notifier)
+		} else {
+			fmt.Printf("✓ Removed %s template for %s\n", notifier, event)
+		}
+		return nil
+	},
+}
+
+var templateTestCmd = &cobra.Command{
+	Use:   "test <notifier> [event]",
+	Short: "Render a template against a sample event",
+	Long: `Render the configured template of a notifier, or the one given with
+--template or --file, against a sample event of the given type
+(default: status_change, a CI failure).`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notifier, event := args[0], notify.EventStatusChange
+		if len(args) == 2 {
+			event = args[1]
+		}
+		if err := validateTemplateTarget(notifier, event); err != nil {
+			return err
+		}
+		event = sampleEventType(event)
+
+		var sources map[string]string
+		if templateText != "" || templateFile != "" {
+			var given []string
+			if templateText != "" {
+				given = []string{templateText}
+			}
+			text, err := templateSource(given)
+			if err != nil {
+				return err
+			}
+			sources = map[string]string{notify.TemplateDefault: text}
+		} else {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			sources = cfg.Templates[notifier]
+		}
+
+		tmpl, err := notify.NewMessageTemplate(notifier, sources)
+		if err != nil {
+			return err
+		}
+		text, ok, err := tmpl.Render(notify.SampleEvent(event))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("No %s template for %s; the built-in message is used.\n", notifier, event)
+			return nil
+		}
+
+		printRenderedTemplate(notifier, text)
+		return nil
+	},
+}
+
+// printRenderedTemplate shows rendered text the way the notifier would use it.
+func printRenderedTemplate(notifier, text string) {
+	switch notifier {
+	case notify.TemplateNative, notify.TemplateEmail:
+		title, body := notify.SplitTitle(text)
+		label := "Title"
+		if notifier == notify.TemplateEmail {
+			label = "Subject"
+		}
+		fmt.Printf("%s: %s\n\n%s\n", label, title, body)
+	case notify.TemplateWebhook:
+		fmt.Println(strings.TrimRight(text, "\n"))
+		if !json.Valid([]byte(text)) {
+			fmt.Println("\nWarning: the output is not valid JSON; webhooks expect a JSON body.")
+		}
+	default:
+		fmt.Println(strings.TrimRight(text, "\n"))
+	}
+}
+
+// validateTemplateTarget checks a notifier name and event type given on the command line.
+func validateTemplateTarget(notifier, event string) error {
+	if !notify.IsValidTemplateNotifier(notifier) {
+		return invalidTemplateNotifier(notifier)
+	}
+	if !notify.IsValidTemplateEvent(event) {
+		return fmt.Errorf("unknown event type %q (expected default or one of: %s)", event, strings.Join(notify.NotifiedEventTypes, ", "))
+	}
+	return nil
+}
+
+func invalidTemplateNotifier(notifier string) error {
+	return fmt.Errorf("unknown notifier %q (expected one of: %s)", notifier, strings.Join(notify.TemplateNotifiers, ", "))
+}
+
+// templateSource returns the template given as an argument or read from --file.
+func templateSource(args []string) (string, error) {
+	switch {
+	case templateFile != "" && len(args) > 0:
+		return "", fmt.Errorf("pass the template either as an argument or with --file, not both")
+	case templateFile != "":
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template: %w", err)
+		}
+		return string(data), nil
+	case len(args) > 0:
+		return args[0], nil
+	default:
+		return "", fmt.Errorf("missing template (pass it as an argument or with --file)")
+	}
+}
+
+// sampleEventType returns the event type to check a template for event against.
+func sampleEventType(event string) string {
+	if event == notify.TemplateDefault {
+		return notify.EventStatusChange
+	}
+	return event
+}
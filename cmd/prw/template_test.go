@@ -0,0 +1,110 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+func TestTemplateCmds(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".prw", "config.json")
+
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return configPath, nil
+	}
+
+	set := func(args ...string) error {
+		_, err := captureStdout(func() error { return templateSetCmd.RunE(templateSetCmd, args) })
+		return err
+	}
+
+	if err := set("console", "status_change", "{{emoji .CurrentState}} {{.Repo}}#{{.Number}} {{.CurrentState}} {{prURL .}}"); err != nil {
+		t.Fatalf("template set: %v", err)
+	}
+	if err := set("native", "default", "{{.Owner}}/{{.Repo}}#{{.Number}}\n{{headline .}}"); err != nil {
+		t.Fatalf("template set: %v", err)
+	}
+	if err := set("pager", "default", "x"); err == nil {
+		t.Error("expected an unknown notifier to be rejected")
+	}
+	if err := set("console", "deployed", "x"); err == nil {
+		t.Error("expected an unknown event type to be rejected")
+	}
+	if err := set("console", "merged", "{{.Nope}}"); err == nil {
+		t.Error("expected a template failing on the sample event to be rejected")
+	}
+
+	output, err := captureStdout(func() error { return templateTestCmd.RunE(templateTestCmd, []string{"console"}) })
+	if err != nil {
+		t.Fatalf("template test: %v", err)
+	}
+	if output != "❌ api#1347 failure https://github.com/octo-org/api/pull/1347\n" {
+		t.Errorf("unexpected console output %q", output)
+	}
+
+	output, err = captureStdout(func() error { return templateTestCmd.RunE(templateTestCmd, []string{"native", "merged"}) })
+	if err != nil {
+		t.Fatalf("template test: %v", err)
+	}
+	if output != "Title: octo-org/api#1347\n\nMerged\n" {
+		t.Errorf("unexpected native output %q", output)
+	}
+
+	output, _ = captureStdout(func() error { return templateTestCmd.RunE(templateTestCmd, []string{"console", "merged"}) })
+	if !strings.Contains(output, "built-in message is used") {
+		t.Errorf("expected no console template for merges, got %q", output)
+	}
+
+	templateText = `{"text": {{.Title}}}`
+	output, err = captureStdout(func() error { return templateTestCmd.RunE(templateTestCmd, []string{"webhook"}) })
+	templateText = ""
+	if err != nil {
+		t.Fatalf("template test --template: %v", err)
+	}
+	if !strings.Contains(output, "not valid JSON") {
+		t.Errorf("expected a JSON warning, got %q", output)
+	}
+
+	output, _ = captureStdout(func() error { return templateListCmd.RunE(templateListCmd, nil) })
+	if !strings.Contains(output, "console status_change:\n  {{emoji") || !strings.Contains(output, "native default:\n") {
+		t.Errorf("unexpected template list:\n%s", output)
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if tmpl := messageTemplate(loaded, notify.TemplateNative); tmpl == nil {
+		t.Error("expected the native template to be loaded")
+	}
+
+	for _, args := range [][]string{{"console", "status_change"}, {"native"}} {
+		if _, err := captureStdout(func() error { return templateUnsetCmd.RunE(templateUnsetCmd, args) }); err != nil {
+			t.Fatalf("template unset %v: %v", args, err)
+		}
+	}
+	loaded, _ = config.Load()
+	if loaded.Templates != nil {
+		t.Errorf("expected no templates left, got %v", loaded.Templates)
+	}
+}
+
+func TestMessageTemplateIgnoresInvalidConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SetTemplate(notify.TemplateConsole, "default", "{{.Owner")
+
+	var tmpl *notify.MessageTemplate
+	output, _ := captureStdout(func() error {
+		tmpl = messageTemplate(cfg, notify.TemplateConsole)
+		return nil
+	})
+	if tmpl != nil || !strings.Contains(output, "Warning: invalid console template") {
+		t.Errorf("expected the invalid template to be reported and ignored, got %v, %q", tmpl, output)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+	"github.com/devblac/prw/internal/tui"
+	"github.com/devblac/prw/internal/watcher"
+)
+
+// uiEventLimit is the number of past events loaded into the events pane on start.
+const uiEventLimit = 50
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().BoolVar(&notifyNative, "notify-native", false, "enable native OS notifications (macOS/Linux/Windows)")
+}
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Open an interactive terminal dashboard",
+	Long: `Run the watcher loop with a full-screen dashboard of watched PRs and recent events.
+
+Keys:
+  j/k, arrows  move the selection
+  o, enter     open the selected PR in the browser
+  d            unwatch the selected PR (asks for confirmation)
+  r            check all PRs now
+  a            watch a new PR by URL
+  s            cycle the sort order (repo, status, last checked)
+  /            filter by repo, number, title, or status (esc clears)
+  q            quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// The dashboard replaces console output; webhook and native notifications still fire.
+		app := &tui.App{
+			In:    os.Stdin,
+			Out:   os.Stdout,
+			Size:  func() (int, int) { return tui.Size(os.Stdin) },
+			Color: tui.ColorEnabled(),
+		}
+		w, err := newWatcher(cfg, newUINotifier(cfg, app))
+		if err != nil {
+			return err
+		}
+		w.SetOutput(app.LogWriter())
+		w.OnCycle(app.Update)
+		app.PRs = w.WatchedPRs
+		app.Actions = &uiActions{cfg: cfg, watcher: w}
+
+		events, err := w.History().Query(history.Filter{Limit: uiEventLimit})
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		app.SetEvents(events)
+
+		restore, err := tui.EnableCbreak(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("prw ui needs an interactive terminal: %w", err)
+		}
+		defer restore()
+
+		ctx, cancel := signalContext()
+		defer cancel()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			w.RunBackground(ctx)
+		}()
+
+		err = app.Run(ctx)
+		// Let the watcher finish its cycle and flush held notifications, such as email digests
+		cancel()
+		<-done
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+		return nil
+	},
+}
+
+// newUINotifier builds the notifier chain of the dashboard, which replaces
+// console output: app gets every event and the routes, if any, pick the others.
+func newUINotifier(cfg *config.Config, app notify.Notifier) notify.Notifier {
+	return notifierChain(cfg, nil, app)
+}
+
+// uiActions carries out dashboard commands against the running watcher.
+type uiActions struct {
+	cfg     *config.Config
+	watcher *watcher.Watcher
+}
+
+func (a *uiActions) Open(pr config.WatchedPR) error {
+	return openBrowser(github.FormatHostPRURL(pr.HostName(), pr.Owner, pr.Repo, pr.Number))
+}
+
+func (a *uiActions) Unwatch(pr config.WatchedPR) error {
+	removed, err := a.watcher.RemovePR(pr.Host, pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("PR is not being watched")
+	}
+	return nil
+}
+
+func (a *uiActions) Refresh() {
+	a.watcher.CheckNow()
+}
+
+func (a *uiActions) Add(prURL string) (config.WatchedPR, error) {
+	pr, client, err := resolvePR(a.cfg, prURL)
+	if err != nil {
+		return config.WatchedPR{}, err
+	}
+	if !a.watcher.HasClient(pr.Host) {
+		a.watcher.SetClient(pr.Host, client)
+	}
+	if _, err := a.watcher.AddPR(pr); err != nil {
+		return config.WatchedPR{}, fmt.Errorf("failed to save config: %w", err)
+	}
+	a.watcher.CheckNow()
+	return pr, nil
+}
+
+// openBrowser opens url with the platform's default handler. It is a variable so
+// tests can replace it.
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
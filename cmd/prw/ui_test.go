@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// recordingNotifier stands in for the dashboard.
+type recordingNotifier struct {
+	events []*notify.StatusChangeEvent
+}
+
+func (r *recordingNotifier) Notify(event *notify.StatusChangeEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestUINotifierRespectsRoutes(t *testing.T) {
+	var posts atomic.Int32
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+	}))
+	defer webhookServer.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.WebhookURL = webhookServer.URL
+	cfg.WebhookFormat = notify.WebhookFormatJSON
+	cfg.Routes = []config.Route{
+		{Repos: []string{"org/api"}, Notify: []string{"webhook"}},
+		{Notify: []string{"console"}},
+	}
+
+	app := &recordingNotifier{}
+	n := newUINotifier(cfg, app)
+	output, err := captureStdout(func() error {
+		return n.Notify(&notify.StatusChangeEvent{Owner: "org", Repo: "web", Number: 1, CurrentState: "failure"})
+	})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if posts.Load() != 0 {
+		t.Error("expected the unrouted event to skip the webhook")
+	}
+	if output != "" {
+		t.Errorf("expected the dashboard to replace console output, got %q", output)
+	}
+
+	if err := n.Notify(&notify.StatusChangeEvent{Owner: "org", Repo: "api", Number: 2, CurrentState: "failure"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("expected the routed event to reach the webhook once, got %d posts", posts.Load())
+	}
+	if len(app.events) != 2 {
+		t.Errorf("expected the dashboard to get every event, got %d", len(app.events))
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+	"github.com/devblac/prw/internal/watcher"
+)
+
+// Exit codes of 'prw wait'.
+const (
+	waitExitFailure = 1
+	waitExitTimeout = 2
+)
+
+var (
+	waitTimeout       time.Duration
+	waitInterval      time.Duration
+	waitRequireChecks []string
+)
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Minute, "give up after this long (0 waits forever)")
+	waitCmd.Flags().DurationVar(&waitInterval, "interval", 0, "time between checks (default: poll_interval_seconds)")
+	waitCmd.Flags().StringSliceVar(&waitRequireChecks, "require-checks", nil, "comma-separated check names to wait for instead of the aggregate state")
+}
+
+var waitCmd = &cobra.Command{
+	Use:   "wait <PR_URL>",
+	Short: "Wait until a PR's CI finishes and exit with its result",
+	Long: `Poll a PR until its CI state leaves pending, then exit with:
+
+  0  CI succeeded
+  1  CI failed or errored (or the PR was closed, or GitHub rejected the request)
+  2  --timeout was reached first
+
+The PR does not have to be watched. With --require-checks, only the named
+checks are waited for: the PR succeeds once all of them succeed, fails as soon
+as one of them fails, and keeps waiting while any of them has not reported yet.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if waitTimeout < 0 || waitInterval < 0 {
+			return fmt.Errorf("--timeout and --interval must not be negative")
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		pr, client, err := resolvePR(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		// Past this point failures are outcomes, not usage errors
+		cmd.SilenceUsage = true
+
+		interval := waitInterval
+		if interval == 0 {
+			interval = time.Duration(cfg.PollIntervalSeconds) * time.Second
+		}
+
+		ctx, cancel := signalContext()
+		defer cancel()
+		if waitTimeout > 0 {
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithTimeout(ctx, waitTimeout)
+			defer cancelTimeout()
+		}
+
+		return waitForPR(ctx, client, pr, requiredCheckNames(waitRequireChecks), interval)
+	},
+}
+
+// waitForPR polls pr until its CI finishes or ctx is done, printing progress
+// whenever it changes.
+func waitForPR(ctx context.Context, client watcher.GitHubClient, pr config.WatchedPR, required []string, interval time.Duration) error {
+	label := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+	if len(required) > 0 {
+		fmt.Printf("Waiting for checks of %s: %s\n", label, strings.Join(required, ", "))
+	} else {
+		fmt.Printf("Waiting for CI of %s...\n", label)
+	}
+
+	lastProgress := ""
+	for {
+		state, checks, err := pollPRState(client, pr, required)
+		switch {
+		case github.IsPermanent(err):
+			// A bad token or a deleted PR won't recover by polling again
+			return &exitCodeError{code: waitExitFailure, err: fmt.Errorf("%s: %w", label, err)}
+		case err != nil:
+			fmt.Printf("Warning: %v\n", err)
+		case state == github.PRStateClosed:
+			return &exitCodeError{code: waitExitFailure, err: fmt.Errorf("%s was closed before CI finished", label)}
+		case state == "success":
+			fmt.Printf("✓ %s: CI succeeded\n", label)
+			return nil
+		case state == "failure" || state == "error":
+			if failing := notify.FailingChecks(checks); len(failing) > 0 {
+				fmt.Printf("Failing: %s\n", strings.Join(failing, ", "))
+			}
+			return &exitCodeError{code: waitExitFailure, err: fmt.Errorf("%s: CI finished with %s", label, state)}
+		default:
+			if progress := waitProgress(checks); progress != lastProgress {
+				fmt.Printf("%s  %s: %s\n", time.Now().Format("15:04:05"), label, progress)
+				lastProgress = progress
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return &exitCodeError{code: waitExitTimeout, err: fmt.Errorf("timed out waiting for %s", label)}
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollPRState returns the CI state of the PR's current head commit and the
+// checks it was derived from. With required check names, only those checks
+// count. A PR closed without merging reports github.PRStateClosed.
+func pollPRState(client watcher.GitHubClient, pr config.WatchedPR, required []string) (string, []github.Check, error) {
+	ghPR, err := client.GetPullRequest(pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch PR: %w", err)
+	}
+	if ghPR.Lifecycle() == github.PRStateClosed {
+		return github.PRStateClosed, nil, nil
+	}
+
+	state, checks, err := watcher.CommitState(client, pr.Owner, pr.Repo, ghPR.Head.SHA)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(required) == 0 {
+		return state, checks, nil
+	}
+	state, checks = requiredChecksState(checks, required)
+	return state, checks, nil
+}
+
+// requiredChecksState aggregates only the checks named in required. A required
+// check that has not reported yet counts as pending.
+func requiredChecksState(checks []github.Check, required []string) (string, []github.Check) {
+	var selected []github.Check
+	state := "success"
+	for _, name := range required {
+		found := false
+		for _, c := range checks {
+			if c.Name == name {
+				found = true
+				selected = append(selected, c)
+			}
+		}
+		if !found {
+			selected = append(selected, github.Check{Name: name, State: "pending"})
+		}
+	}
+	for _, c := range selected {
+		switch {
+		case c.State == "failure" || c.State == "error":
+			return c.State, selected
+		case c.State != "success":
+			state = "pending"
+		}
+	}
+	return state, selected
+}
+
+// requiredCheckNames trims and de-duplicates the --require-checks values.
+func requiredCheckNames(values []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		name := strings.TrimSpace(v)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// waitProgress summarizes how many checks have finished, e.g. "pending, 3/5 checks done".
+func waitProgress(checks []github.Check) string {
+	if len(checks) == 0 {
+		return "pending, no checks reported yet"
+	}
+	done := 0
+	for _, c := range checks {
+		if c.State != "pending" {
+			done++
+		}
+	}
+	return fmt.Sprintf("pending, %d/%d checks done", done, len(checks))
+}
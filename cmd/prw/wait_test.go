@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+// setupWaitTest points the config and GitHub client at a fake API whose check
+// runs are produced by runs, called with the number of the status poll.
+func setupWaitTest(t *testing.T, runs func(poll int) string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	t.Cleanup(func() { config.ConfigPath = oldConfigPath })
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+			fmt.Fprint(w, `{"number": 1, "title": "Wait for me", "state": "open", "head": {"sha": "abc"}}`)
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			atomic.AddInt32(&polls, 1)
+			fmt.Fprint(w, `{"state": "pending", "statuses": []}`)
+		case strings.HasSuffix(r.URL.Path, "/check-suites"):
+			fmt.Fprint(w, `{"check_suites": []}`)
+		case strings.HasSuffix(r.URL.Path, "/check-runs"):
+			fmt.Fprintf(w, `{"check_runs": [%s]}`, runs(int(atomic.LoadInt32(&polls))))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldNewGitHubClient := newGitHubClient
+	t.Cleanup(func() { newGitHubClient = oldNewGitHubClient })
+	newGitHubClient = func(host, token string) *github.Client {
+		client := github.NewClient(token)
+		client.BaseURL = server.URL
+		client.HTTPClient = server.Client()
+		return client
+	}
+
+	waitInterval = time.Millisecond
+	waitTimeout = 5 * time.Second
+	waitRequireChecks = nil
+	t.Cleanup(func() {
+		waitInterval, waitTimeout, waitRequireChecks = 0, 30*time.Minute, nil
+	})
+}
+
+func checkRun(name, status, conclusion string) string {
+	return fmt.Sprintf(`{"name": %q, "status": %q, "conclusion": %q}`, name, status, conclusion)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return -1
+}
+
+func TestWaitCmd(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []string
+		timeout  time.Duration
+		runs     func(poll int) string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name: "success after pending",
+			runs: func(poll int) string {
+				if poll < 3 {
+					return checkRun("build", "in_progress", "")
+				}
+				return checkRun("build", "completed", "success")
+			},
+			wantCode: 0,
+			wantOut:  "CI succeeded",
+		},
+		{
+			name: "failure",
+			runs: func(poll int) string {
+				return checkRun("build", "completed", "success") + "," + checkRun("lint", "completed", "failure")
+			},
+			wantCode: waitExitFailure,
+			wantOut:  "Failing: lint",
+		},
+		{
+			name:    "timeout",
+			timeout: 20 * time.Millisecond,
+			runs: func(poll int) string {
+				return checkRun("build", "queued", "")
+			},
+			wantCode: waitExitTimeout,
+			wantOut:  "0/1 checks done",
+		},
+		{
+			name:     "required checks ignore other failures",
+			required: []string{"build", " test"},
+			runs: func(poll int) string {
+				runs := checkRun("lint", "completed", "failure") + "," + checkRun("build", "completed", "success")
+				if poll >= 2 {
+					runs += "," + checkRun("test", "completed", "success")
+				}
+				return runs
+			},
+			wantCode: 0,
+			wantOut:  "Waiting for checks of owner/repo#1: build, test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupWaitTest(t, tt.runs)
+			waitRequireChecks = tt.required
+			if tt.timeout > 0 {
+				waitTimeout = tt.timeout
+			}
+
+			output, err := captureStdout(func() error {
+				return waitCmd.RunE(waitCmd, []string{"https://github.com/owner/repo/pull/1"})
+			})
+			if code := exitCode(err); code != tt.wantCode {
+				t.Errorf("exit code = %d (err %v), want %d", code, err, tt.wantCode)
+			}
+			if !strings.Contains(output, tt.wantOut) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.wantOut, output)
+			}
+		})
+	}
+}
+
+func TestRequiredChecksState(t *testing.T) {
+	checks := []github.Check{
+		{Name: "build", State: "success"},
+		{Name: "test", State: "pending"},
+		{Name: "lint", State: "failure"},
+	}
+	tests := []struct {
+		required []string
+		want     string
+	}{
+		{[]string{"build"}, "success"},
+		{[]string{"build", "test"}, "pending"},
+		{[]string{"build", "missing"}, "pending"},
+		{[]string{"test", "lint"}, "failure"},
+	}
+	for _, tt := range tests {
+		if got, _ := requiredChecksState(checks, tt.required); got != tt.want {
+			t.Errorf("requiredChecksState(%v) = %q, want %q", tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForPRStopsOnPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantCode int
+		wantOut  string
+	}{
+		{name: "not found", status: http.StatusNotFound, wantCode: waitExitFailure},
+		{name: "bad credentials", status: http.StatusUnauthorized, wantCode: waitExitFailure},
+		{name: "server error is retried", status: http.StatusBadGateway, wantCode: 0, wantOut: "Warning: failed to fetch PR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/pulls/1"):
+					if atomic.AddInt32(&requests, 1) == 1 {
+						w.WriteHeader(tt.status)
+						fmt.Fprint(w, `{"message": "nope"}`)
+						return
+					}
+					fmt.Fprint(w, `{"number": 1, "state": "open", "head": {"sha": "abc"}}`)
+				case strings.HasSuffix(r.URL.Path, "/status"):
+					fmt.Fprint(w, `{"state": "success", "statuses": [{"context": "ci", "state": "success"}]}`)
+				default:
+					fmt.Fprint(w, `{}`)
+				}
+			}))
+			defer server.Close()
+
+			client := github.NewClient("test-token")
+			client.BaseURL = server.URL
+			client.HTTPClient = server.Client()
+			client.Retry.MaxAttempts = 1
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			output, err := captureStdout(func() error {
+				return waitForPR(ctx, client, config.WatchedPR{Owner: "owner", Repo: "repo", Number: 1}, nil, time.Millisecond)
+			})
+			if code := exitCode(err); code != tt.wantCode {
+				t.Errorf("exit code = %d (err %v), want %d", code, err, tt.wantCode)
+			}
+			if tt.wantCode != 0 && atomic.LoadInt32(&requests) != 1 {
+				t.Errorf("expected no polling after a permanent error, got %d requests", requests)
+			}
+			if !strings.Contains(output, tt.wantOut) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.wantOut, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/watcher"
+	"github.com/devblac/prw/internal/webhook"
+)
+
+// webhookPath is where GitHub webhook deliveries are received.
+const webhookPath = "/webhook"
+
+// errMissingWebhookSecret explains how to configure the secret deliveries are verified with.
+var errMissingWebhookSecret = errors.New("missing github_webhook_secret; configure it with 'prw config set github_webhook_secret <secret>' and use the same secret in the GitHub webhook settings")
+
+// runWebhookReceiver runs the watcher in webhook mode: deliveries received on
+// runListen trigger checks of the PRs they concern, and polling only reconciles
+// every runFallback.
+func runWebhookReceiver(ctx context.Context, cfg *config.Config, w *watcher.Watcher) error {
+	if cfg.GitHubWebhookSecret == "" {
+		return errMissingWebhookSecret
+	}
+	if runFallback < 0 {
+		return fmt.Errorf("invalid --fallback-interval value %s (expected a positive duration or 0)", runFallback)
+	}
+	w.SetFallbackInterval(runFallback)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST "+webhookPath, webhook.NewHandler(cfg.GitHubWebhookSecret, deliverWebhook(w)))
+
+	httpServer, listener, err := startHTTPServer(runListen, mux)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Receiving GitHub webhooks at http://%s%s\n", listener.Addr(), webhookPath)
+
+	err = w.RunBackground(ctx)
+	stopHTTPServer(httpServer)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// deliverWebhook returns a callback that queues a check of the watched PRs a
+// webhook delivery refers to. Deliveries for PRs that aren't watched are dropped.
+func deliverWebhook(w *watcher.Watcher) func(*webhook.Delivery) {
+	return func(d *webhook.Delivery) {
+		w.Refresh(w.MatchPRs(d.Host, d.Owner, d.Repo, d.SHA, d.Numbers)...)
+	}
+}
+
+// startHTTPServer listens on addr and serves handler in the background.
+func startHTTPServer(addr string, handler http.Handler) (*http.Server, net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("Warning: HTTP server stopped: %v\n", err)
+		}
+	}()
+	return httpServer, listener, nil
+}
+
+// stopHTTPServer shuts the server down, giving in-flight requests a few seconds to finish.
+func stopHTTPServer(httpServer *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		fmt.Printf("Warning: failed to stop HTTP server: %v\n", err)
+	}
+}
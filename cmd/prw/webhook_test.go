@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/webhook"
+)
+
+func TestRunCmd_ListenRequiresWebhookSecret(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	runListen = "127.0.0.1:0"
+	defer func() { runListen = "" }()
+
+	err := runCmd.RunE(runCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "github_webhook_secret") {
+		t.Errorf("expected missing webhook secret error, got %v", err)
+	}
+}
+
+func TestRunCmd_InvalidFallbackInterval(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, ".prw", "config.json"), nil
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	cfg.GitHubWebhookSecret = "s3cret"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save test config: %v", err)
+	}
+
+	runListen = "127.0.0.1:0"
+	runFallback = -time.Minute
+	defer func() { runListen, runFallback = "", 10*time.Minute }()
+
+	err := runCmd.RunE(runCmd, []string{})
+	if err == nil || !strings.Contains(err.Error(), "--fallback-interval") {
+		t.Errorf("expected invalid fallback interval error, got %v", err)
+	}
+}
+
+func TestServeWebhookRoute(t *testing.T) {
+	api, _, _ := newTestAPI(t)
+
+	// Without a secret the endpoint is not registered.
+	resp := doRequest(t, http.MethodPost, api.URL+"/webhook", "{}")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 without a webhook secret, got %d", resp.StatusCode)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.GitHubToken = "test-token"
+	cfg.GitHubWebhookSecret = "s3cret"
+	w, err := newWatcher(cfg, newEventHub())
+	if err != nil {
+		t.Fatalf("newWatcher failed: %v", err)
+	}
+	srv := httptest.NewServer(newServer(cfg, w, newEventHub(), "").routes())
+	defer srv.Close()
+
+	body := `{"sha": "abc", "repository": {"name": "repo", "html_url": "https://github.com/owner/repo", "owner": {"login": "owner"}}}`
+	tests := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{"signed", webhook.Sign("s3cret", []byte(body)), http.StatusAccepted},
+		{"wrong secret", webhook.Sign("other", []byte(body)), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+"/webhook", strings.NewReader(body))
+			req.Header.Set("X-GitHub-Event", "status")
+			req.Header.Set("X-Hub-Signature-256", tt.signature)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
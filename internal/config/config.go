@@ -7,12 +7,26 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/retry"
 )
 
 const (
 	NotificationFilterChange  = "change"
 	NotificationFilterFail    = "fail"
 	NotificationFilterSuccess = "success"
+	NotificationFilterReview  = "review"
+)
+
+// DefaultMaxConcurrency is the default number of PRs checked in parallel.
+const DefaultMaxConcurrency = 4
+
+// Policies for PRs that have been merged or closed.
+const (
+	ClosedPRPolicyKeep        = "keep"
+	ClosedPRPolicyUnwatch     = "unwatch"
+	ClosedPRPolicyUnwatchDays = "unwatch_after_days"
 )
 
 // Config represents the application configuration and state.
@@ -20,16 +34,120 @@ type Config struct {
 	// Global settings
 	PollIntervalSeconds int    `json:"poll_interval_seconds"`
 	WebhookURL          string `json:"webhook_url,omitempty"`
+	WebhookFormat       string `json:"webhook_format,omitempty"` // auto, json, slack, discord, or teams; empty means auto
 	GitHubToken         string `json:"github_token,omitempty"`
 	NotificationFilter  string `json:"notification_filter,omitempty"`
 	NotificationNative  bool   `json:"notification_native,omitempty"`
+	MaxConcurrency      int    `json:"max_concurrency,omitempty"`
+
+	// Slack Block Kit notifications, via an incoming webhook or a bot token and channel
+	SlackWebhookURL string `json:"slack_webhook_url,omitempty"`
+	SlackBotToken   string `json:"slack_bot_token,omitempty"`
+	SlackChannel    string `json:"slack_channel,omitempty"`
+
+	// Email notifications over SMTP
+	Email *EmailConfig `json:"email,omitempty"`
+
+	// Named notifier instances, and the routes that pick the notifiers of each
+	// event; without routes every event goes to every configured notifier
+	Notifiers map[string]NotifierConfig `json:"notifiers,omitempty"`
+	Routes    []Route                   `json:"routes,omitempty"`
+
+	// Message templates keyed by notifier, then by event type or "default"
+	Templates map[string]map[string]string `json:"templates,omitempty"`
+
+	// Schedule during which webhook and native notifications are held back
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+
+	// Retries of transient GitHub API and webhook failures
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// Tokens for GitHub Enterprise Server instances, keyed by hostname
+	Hosts map[string]HostConfig `json:"hosts,omitempty"`
+
+	// Secret shared with GitHub for signing webhook deliveries to prw
+	GitHubWebhookSecret string `json:"github_webhook_secret,omitempty"`
+
+	// Record every poll result in the history, not just state changes
+	HistoryPollResults bool `json:"history_poll_results,omitempty"`
+
+	// Lines of failed GitHub Actions job logs attached to failure notifications; 0 disables
+	LogExcerptLines int `json:"log_excerpt_lines,omitempty"`
+
+	// Ready-to-merge requirements keyed by "owner/repo", "owner/*", or "*"
+	MergeRules map[string][]string `json:"merge_rules,omitempty"`
+
+	// Minutes CI may stay pending before it is reported as stuck, keyed like MergeRules
+	StuckAfterMinutes map[string]int `json:"stuck_after_minutes,omitempty"`
+
+	// Checks known to be flaky, re-run automatically before notifying; empty disables
+	FlakyChecks    []string `json:"flaky_checks,omitempty"`
+	FlakyMaxReruns int      `json:"flaky_max_reruns,omitempty"`
+
+	// Merged/closed PR cleanup
+	ClosedPRPolicy      string `json:"closed_pr_policy,omitempty"`
+	ClosedPRUnwatchDays int    `json:"closed_pr_unwatch_days,omitempty"`
+
+	// Searches whose open PRs are watched automatically, and how often they run
+	Subscriptions               []Subscription `json:"subscriptions,omitempty"`
+	SubscriptionIntervalSeconds int            `json:"subscription_interval_seconds,omitempty"`
 
 	// Watched PRs
 	WatchedPRs []WatchedPR `json:"watched_prs"`
 }
 
+// RetryConfig controls how transient GitHub API and webhook failures are retried.
+type RetryConfig struct {
+	MaxAttempts int     `json:"max_attempts"`
+	BaseDelayMS int     `json:"base_delay_ms"`
+	MaxDelayMS  int     `json:"max_delay_ms"`
+	Jitter      float64 `json:"jitter"`
+}
+
+// EmailConfig holds the SMTP server and addresses for email notifications.
+type EmailConfig struct {
+	SMTPHost      string   `json:"smtp_host,omitempty"`
+	SMTPPort      int      `json:"smtp_port,omitempty"`     // 0 means the default port for SMTPSecurity
+	SMTPSecurity  string   `json:"smtp_security,omitempty"` // starttls (default), tls, or none
+	SMTPUsername  string   `json:"smtp_username,omitempty"`
+	SMTPPassword  string   `json:"smtp_password,omitempty"`
+	From          string   `json:"from,omitempty"`
+	To            []string `json:"to,omitempty"`
+	DigestMinutes int      `json:"digest_minutes,omitempty"` // 0 sends one email per event
+}
+
+// Enabled reports whether enough is configured to send email.
+func (e *EmailConfig) Enabled() bool {
+	return e != nil && e.SMTPHost != "" && e.From != "" && len(e.To) > 0
+}
+
+// SMTPPasswordOrEnv returns the configured SMTP password, falling back to PRW_SMTP_PASSWORD.
+func (e *EmailConfig) SMTPPasswordOrEnv() string {
+	if e.SMTPPassword != "" {
+		return e.SMTPPassword
+	}
+	return os.Getenv("PRW_SMTP_PASSWORD")
+}
+
+// HostConfig holds the settings for one GitHub host.
+type HostConfig struct {
+	Token string `json:"token,omitempty"`
+}
+
+// DefaultRetryConfig returns the retry settings used when none are configured.
+func DefaultRetryConfig() *RetryConfig {
+	p := retry.DefaultPolicy()
+	return &RetryConfig{
+		MaxAttempts: p.MaxAttempts,
+		BaseDelayMS: int(p.BaseDelay / time.Millisecond),
+		MaxDelayMS:  int(p.MaxDelay / time.Millisecond),
+		Jitter:      p.Jitter,
+	}
+}
+
 // WatchedPR represents a pull request being watched.
 type WatchedPR struct {
+	Host           string    `json:"host,omitempty"` // empty means github.com
 	Owner          string    `json:"owner"`
 	Repo           string    `json:"repo"`
 	Number         int       `json:"number"`
@@ -37,6 +155,29 @@ type WatchedPR struct {
 	LastKnownState string    `json:"last_known_state,omitempty"`
 	LastChecked    time.Time `json:"last_checked,omitempty"`
 	Title          string    `json:"title,omitempty"`
+	Author         string    `json:"author,omitempty"`
+	Labels         []string  `json:"labels,omitempty"`
+	PRState        string    `json:"pr_state,omitempty"` // open, closed, merged
+	Draft          bool      `json:"draft,omitempty"`
+	ClosedAt       time.Time `json:"closed_at,omitempty"`
+
+	// Review tracking; an empty ReviewDecision means reviews were never checked
+	ReviewDecision     string   `json:"review_decision,omitempty"`
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+
+	// Mergeability; an empty MergeableState means it was never known
+	MergeableState string `json:"mergeable_state,omitempty"`
+	ReadyToMerge   bool   `json:"ready_to_merge,omitempty"`
+
+	// Automatic reruns of flaky checks on the current head commit
+	Reruns []AutoRerun `json:"reruns,omitempty"`
+
+	// When CI on the head commit entered pending; zero when it is not pending
+	PendingSince  time.Time `json:"pending_since,omitempty"`
+	StuckNotified bool      `json:"stuck_notified,omitempty"`
+
+	// Key of the subscription that added the PR; empty for explicit watches
+	Subscription string `json:"subscription,omitempty"`
 }
 
 // DefaultConfig returns a config with sensible defaults.
@@ -44,6 +185,8 @@ func DefaultConfig() *Config {
 	return &Config{
 		PollIntervalSeconds: 20,
 		NotificationFilter:  NotificationFilterChange,
+		MaxConcurrency:      DefaultMaxConcurrency,
+		ClosedPRPolicy:      ClosedPRPolicyKeep,
 		WatchedPRs:          []WatchedPR{},
 	}
 }
@@ -82,10 +225,14 @@ func Load() (*Config, error) {
 	if cfg.PollIntervalSeconds == 0 {
 		cfg.PollIntervalSeconds = 20
 	}
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = DefaultMaxConcurrency
+	}
 	if cfg.WatchedPRs == nil {
 		cfg.WatchedPRs = []WatchedPR{}
 	}
 	cfg.NotificationFilter = normalizeNotificationFilter(cfg.NotificationFilter)
+	cfg.ClosedPRPolicy = NormalizeClosedPRPolicy(cfg.ClosedPRPolicy)
 
 	return &cfg, nil
 }
@@ -117,7 +264,7 @@ func (c *Config) Save() error {
 // AddPR adds a PR to the watched list if not already present.
 func (c *Config) AddPR(pr WatchedPR) bool {
 	for _, existing := range c.WatchedPRs {
-		if existing.Owner == pr.Owner && existing.Repo == pr.Repo && existing.Number == pr.Number {
+		if existing.matches(pr.HostName(), pr.Owner, pr.Repo, pr.Number) {
 			return false
 		}
 	}
@@ -126,9 +273,9 @@ func (c *Config) AddPR(pr WatchedPR) bool {
 }
 
 // RemovePR removes a PR from the watched list.
-func (c *Config) RemovePR(owner, repo string, number int) bool {
+func (c *Config) RemovePR(host, owner, repo string, number int) bool {
 	for i, pr := range c.WatchedPRs {
-		if pr.Owner == owner && pr.Repo == repo && pr.Number == number {
+		if pr.matches(host, owner, repo, number) {
 			c.WatchedPRs = append(c.WatchedPRs[:i], c.WatchedPRs[i+1:]...)
 			return true
 		}
@@ -137,18 +284,107 @@ func (c *Config) RemovePR(owner, repo string, number int) bool {
 }
 
 // UpdatePR updates the state of a watched PR.
-func (c *Config) UpdatePR(owner, repo string, number int, sha, state string) {
+func (c *Config) UpdatePR(host, owner, repo string, number int, sha, state string) {
+	if pr := c.FindPR(host, owner, repo, number); pr != nil {
+		pr.LastKnownSHA = sha
+		pr.LastKnownState = state
+		pr.LastChecked = time.Now()
+	}
+}
+
+// FindPR returns the watched PR, or nil if it is not watched.
+func (c *Config) FindPR(host, owner, repo string, number int) *WatchedPR {
 	for i := range c.WatchedPRs {
-		if c.WatchedPRs[i].Owner == owner && c.WatchedPRs[i].Repo == repo && c.WatchedPRs[i].Number == number {
-			c.WatchedPRs[i].LastKnownSHA = sha
-			c.WatchedPRs[i].LastKnownState = state
-			c.WatchedPRs[i].LastChecked = time.Now()
-			return
+		if c.WatchedPRs[i].matches(host, owner, repo, number) {
+			return &c.WatchedPRs[i]
 		}
 	}
+	return nil
 }
 
-// GetToken returns the GitHub token from config or environment.
+// PruneClosedPRs removes merged or closed PRs according to the closed PR policy
+// and returns the PRs that were removed.
+func (c *Config) PruneClosedPRs(now time.Time) []WatchedPR {
+	policy := NormalizeClosedPRPolicy(c.ClosedPRPolicy)
+	if policy == ClosedPRPolicyKeep {
+		return nil
+	}
+
+	var removed []WatchedPR
+	kept := c.WatchedPRs[:0]
+	for _, pr := range c.WatchedPRs {
+		if pr.IsClosed() && closedPRExpired(policy, c.ClosedPRUnwatchDays, pr.ClosedAt, now) {
+			removed = append(removed, pr)
+			continue
+		}
+		kept = append(kept, pr)
+	}
+	c.WatchedPRs = kept
+	return removed
+}
+
+// WatchedHosts returns the distinct hosts of the watched PRs in watch-list order,
+// followed by the hosts only used by subscriptions. github.com is returned when
+// nothing is watched.
+func (c *Config) WatchedHosts() []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	add := func(host string) {
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	for _, pr := range c.WatchedPRs {
+		add(pr.HostName())
+	}
+	for _, sub := range c.Subscriptions {
+		add(sub.HostName())
+	}
+	if len(hosts) == 0 {
+		hosts = append(hosts, github.DefaultHost)
+	}
+	return hosts
+}
+
+// HostName returns the normalized host of the PR, defaulting to github.com.
+func (pr WatchedPR) HostName() string {
+	return github.NormalizeHost(pr.Host)
+}
+
+func (pr WatchedPR) matches(host, owner, repo string, number int) bool {
+	return pr.HostName() == github.NormalizeHost(host) && pr.Owner == owner && pr.Repo == repo && pr.Number == number
+}
+
+// IsClosed reports whether the PR has been merged or closed.
+func (pr WatchedPR) IsClosed() bool {
+	return pr.PRState == "closed" || pr.PRState == "merged"
+}
+
+func closedPRExpired(policy string, days int, closedAt, now time.Time) bool {
+	if policy == ClosedPRPolicyUnwatch {
+		return true
+	}
+	if closedAt.IsZero() {
+		return false
+	}
+	return now.Sub(closedAt) >= time.Duration(days)*24*time.Hour
+}
+
+// RetryPolicy returns the configured retry policy, falling back to the defaults.
+func (c *Config) RetryPolicy() retry.Policy {
+	if c.Retry == nil {
+		return retry.DefaultPolicy()
+	}
+	return retry.Policy{
+		MaxAttempts: c.Retry.MaxAttempts,
+		BaseDelay:   time.Duration(c.Retry.BaseDelayMS) * time.Millisecond,
+		MaxDelay:    time.Duration(c.Retry.MaxDelayMS) * time.Millisecond,
+		Jitter:      c.Retry.Jitter,
+	}
+}
+
+// GetToken returns the github.com token from config or environment.
 func (c *Config) GetToken() string {
 	if c.GitHubToken != "" {
 		return c.GitHubToken
@@ -156,11 +392,66 @@ func (c *Config) GetToken() string {
 	return os.Getenv("GITHUB_TOKEN")
 }
 
+// TokenForHost returns the token for a GitHub host. Enterprise hosts only use the
+// token configured for them; GITHUB_TOKEN is never sent to a host other than github.com.
+func (c *Config) TokenForHost(host string) string {
+	host = github.NormalizeHost(host)
+	if hc, ok := c.Hosts[host]; ok && hc.Token != "" {
+		return hc.Token
+	}
+	if host == github.DefaultHost {
+		return c.GetToken()
+	}
+	return ""
+}
+
+// IsConfiguredHost reports whether PR URLs on host are accepted: github.com always is,
+// Enterprise hosts once they have an entry in hosts.
+func (c *Config) IsConfiguredHost(host string) bool {
+	host = github.NormalizeHost(host)
+	if host == github.DefaultHost {
+		return true
+	}
+	_, ok := c.Hosts[host]
+	return ok
+}
+
+// SetHostToken stores the token for host. Setting the github.com token updates github_token.
+func (c *Config) SetHostToken(host, token string) {
+	host = github.NormalizeHost(host)
+	if host == github.DefaultHost {
+		c.GitHubToken = token
+		return
+	}
+	if c.Hosts == nil {
+		c.Hosts = make(map[string]HostConfig)
+	}
+	c.Hosts[host] = HostConfig{Token: token}
+}
+
+// RemoveHost forgets an Enterprise host and its token.
+func (c *Config) RemoveHost(host string) bool {
+	host = github.NormalizeHost(host)
+	if host == github.DefaultHost {
+		had := c.GitHubToken != ""
+		c.GitHubToken = ""
+		return had
+	}
+	if _, ok := c.Hosts[host]; !ok {
+		return false
+	}
+	delete(c.Hosts, host)
+	if len(c.Hosts) == 0 {
+		c.Hosts = nil
+	}
+	return true
+}
+
 // normalizeNotificationFilter applies defaults and validation for the notification filter.
 func normalizeNotificationFilter(value string) string {
 	filter := strings.ToLower(strings.TrimSpace(value))
 	switch filter {
-	case NotificationFilterFail, NotificationFilterSuccess, NotificationFilterChange:
+	case NotificationFilterFail, NotificationFilterSuccess, NotificationFilterChange, NotificationFilterReview:
 		return filter
 	default:
 		return NotificationFilterChange
@@ -170,10 +461,26 @@ func normalizeNotificationFilter(value string) string {
 // IsValidNotificationFilter reports whether the provided filter is allowed.
 func IsValidNotificationFilter(value string) bool {
 	filter := strings.ToLower(strings.TrimSpace(value))
-	return filter == NotificationFilterFail || filter == NotificationFilterSuccess || filter == NotificationFilterChange
+	return filter == NotificationFilterFail || filter == NotificationFilterSuccess || filter == NotificationFilterChange ||
+		filter == NotificationFilterReview
 }
 
 // NormalizeNotificationFilter sanitizes user input and applies defaults.
 func NormalizeNotificationFilter(value string) string {
 	return normalizeNotificationFilter(value)
 }
+
+// IsValidClosedPRPolicy reports whether the provided closed PR policy is allowed.
+func IsValidClosedPRPolicy(value string) bool {
+	policy := strings.ToLower(strings.TrimSpace(value))
+	return policy == ClosedPRPolicyKeep || policy == ClosedPRPolicyUnwatch || policy == ClosedPRPolicyUnwatchDays
+}
+
+// NormalizeClosedPRPolicy sanitizes the closed PR policy and defaults to keep.
+func NormalizeClosedPRPolicy(value string) string {
+	policy := strings.ToLower(strings.TrimSpace(value))
+	if IsValidClosedPRPolicy(policy) {
+		return policy
+	}
+	return ClosedPRPolicyKeep
+}
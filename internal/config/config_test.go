@@ -169,7 +169,7 @@ func TestRemovePR(t *testing.T) {
 		},
 	}
 
-	if !cfg.RemovePR("owner", "repo", 1) {
+	if !cfg.RemovePR("github.com", "owner", "repo", 1) {
 		t.Error("expected RemovePR to return true")
 	}
 	if len(cfg.WatchedPRs) != 1 {
@@ -179,7 +179,7 @@ func TestRemovePR(t *testing.T) {
 		t.Errorf("wrong PR remaining: %+v", cfg.WatchedPRs[0])
 	}
 
-	if cfg.RemovePR("owner", "repo", 99) {
+	if cfg.RemovePR("github.com", "owner", "repo", 99) {
 		t.Error("expected RemovePR to return false for non-existent PR")
 	}
 }
@@ -191,7 +191,7 @@ func TestUpdatePR(t *testing.T) {
 		},
 	}
 
-	cfg.UpdatePR("owner", "repo", 1, "newsha", "success")
+	cfg.UpdatePR("", "owner", "repo", 1, "newsha", "success")
 
 	pr := cfg.WatchedPRs[0]
 	if pr.LastKnownSHA != "newsha" {
@@ -376,6 +376,7 @@ func TestNormalizeNotificationFilter(t *testing.T) {
 		{"fail", NotificationFilterFail},
 		{"success", NotificationFilterSuccess},
 		{"change", NotificationFilterChange},
+		{"Review", NotificationFilterReview},
 		{"", NotificationFilterChange},
 		{"  SUCCESS ", NotificationFilterSuccess},
 		{"unknown", NotificationFilterChange},
@@ -443,6 +444,7 @@ func TestIsValidNotificationFilter(t *testing.T) {
 		{"fail", true},
 		{"success", true},
 		{"change", true},
+		{"review", true},
 		{"FAIL", true},   // case insensitive
 		{"SUCCESS", true}, // case insensitive
 		{"CHANGE", true},  // case insensitive
@@ -498,7 +500,7 @@ func TestUpdatePR_NonExistent(t *testing.T) {
 	}
 
 	// Update a PR that doesn't exist - should not panic or error
-	cfg.UpdatePR("owner2", "repo2", 2, "sha123", "success")
+	cfg.UpdatePR("", "owner2", "repo2", 2, "sha123", "success")
 
 	// Original PR should be unchanged
 	if len(cfg.WatchedPRs) != 1 {
@@ -516,7 +518,7 @@ func TestRemovePR_LastItem(t *testing.T) {
 		},
 	}
 
-	if !cfg.RemovePR("owner", "repo", 1) {
+	if !cfg.RemovePR("github.com", "owner", "repo", 1) {
 		t.Error("expected RemovePR to return true")
 	}
 	if len(cfg.WatchedPRs) != 0 {
@@ -537,3 +539,163 @@ func TestAddPR_EmptyList(t *testing.T) {
 		t.Errorf("expected 1 PR, got %d", len(cfg.WatchedPRs))
 	}
 }
+
+func TestNormalizeClosedPRPolicy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"keep", ClosedPRPolicyKeep},
+		{"unwatch", ClosedPRPolicyUnwatch},
+		{" UNWATCH_AFTER_DAYS ", ClosedPRPolicyUnwatchDays},
+		{"", ClosedPRPolicyKeep},
+		{"bogus", ClosedPRPolicyKeep},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeClosedPRPolicy(tt.value); got != tt.expected {
+			t.Errorf("NormalizeClosedPRPolicy(%q) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+	if IsValidClosedPRPolicy("bogus") {
+		t.Error("expected bogus policy to be invalid")
+	}
+}
+
+func TestPruneClosedPRs(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	newPRs := func() []WatchedPR {
+		return []WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, PRState: "open"},
+			{Owner: "owner", Repo: "repo", Number: 2, PRState: "merged", ClosedAt: now.Add(-72 * time.Hour)},
+			{Owner: "owner", Repo: "repo", Number: 3, PRState: "closed", ClosedAt: now.Add(-1 * time.Hour)},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		days        int
+		wantRemoved []int
+	}{
+		{name: "keep", policy: ClosedPRPolicyKeep},
+		{name: "default keeps", policy: ""},
+		{name: "unwatch immediately", policy: ClosedPRPolicyUnwatch, wantRemoved: []int{2, 3}},
+		{name: "unwatch after days", policy: ClosedPRPolicyUnwatchDays, days: 2, wantRemoved: []int{2}},
+		{name: "unwatch after zero days", policy: ClosedPRPolicyUnwatchDays, days: 0, wantRemoved: []int{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{ClosedPRPolicy: tt.policy, ClosedPRUnwatchDays: tt.days, WatchedPRs: newPRs()}
+			removed := cfg.PruneClosedPRs(now)
+
+			if len(removed) != len(tt.wantRemoved) {
+				t.Fatalf("expected %d removed PRs, got %d", len(tt.wantRemoved), len(removed))
+			}
+			for i, number := range tt.wantRemoved {
+				if removed[i].Number != number {
+					t.Errorf("expected PR #%d to be removed, got #%d", number, removed[i].Number)
+				}
+			}
+			if len(cfg.WatchedPRs) != 3-len(tt.wantRemoved) {
+				t.Errorf("expected %d remaining PRs, got %d", 3-len(tt.wantRemoved), len(cfg.WatchedPRs))
+			}
+			if cfg.WatchedPRs[0].Number != 1 {
+				t.Errorf("open PR should never be pruned: %+v", cfg.WatchedPRs)
+			}
+		})
+	}
+}
+
+func TestAddPR_DistinguishesHosts(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if !cfg.AddPR(WatchedPR{Owner: "owner", Repo: "repo", Number: 1}) {
+		t.Fatal("expected github.com PR to be added")
+	}
+	if cfg.AddPR(WatchedPR{Host: "github.com", Owner: "owner", Repo: "repo", Number: 1}) {
+		t.Error("expected explicit github.com host to match a PR without host")
+	}
+	if !cfg.AddPR(WatchedPR{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 1}) {
+		t.Error("expected same PR on an enterprise host to be added")
+	}
+
+	if !cfg.RemovePR("github.example.com", "owner", "repo", 1) {
+		t.Error("expected enterprise PR to be removed")
+	}
+	if len(cfg.WatchedPRs) != 1 || cfg.WatchedPRs[0].HostName() != "github.com" {
+		t.Errorf("expected only the github.com PR to remain, got %+v", cfg.WatchedPRs)
+	}
+}
+
+func TestTokenForHost(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+
+	cfg := &Config{
+		Hosts: map[string]HostConfig{
+			"github.example.com": {Token: "ghe-token"},
+			"ghe.nothing.com":    {},
+		},
+	}
+
+	if got := cfg.TokenForHost("github.com"); got != "env-token" {
+		t.Errorf("expected github.com to fall back to GITHUB_TOKEN, got %q", got)
+	}
+	if got := cfg.TokenForHost("GitHub.Example.com"); got != "ghe-token" {
+		t.Errorf("expected enterprise token, got %q", got)
+	}
+	if got := cfg.TokenForHost("ghe.nothing.com"); got != "" {
+		t.Errorf("expected no GITHUB_TOKEN fallback for enterprise hosts, got %q", got)
+	}
+
+	cfg.GitHubToken = "config-token"
+	if got := cfg.TokenForHost(""); got != "config-token" {
+		t.Errorf("expected github_token for github.com, got %q", got)
+	}
+}
+
+func TestHostConfiguration(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if !cfg.IsConfiguredHost("github.com") {
+		t.Error("github.com should always be configured")
+	}
+	if cfg.IsConfiguredHost("github.example.com") {
+		t.Error("enterprise host should not be configured yet")
+	}
+
+	cfg.SetHostToken("https://github.example.com", "ghe-token")
+	if !cfg.IsConfiguredHost("github.example.com") || cfg.TokenForHost("github.example.com") != "ghe-token" {
+		t.Errorf("expected host to be configured with token, got %+v", cfg.Hosts)
+	}
+
+	cfg.SetHostToken("github.com", "gh-token")
+	if cfg.GitHubToken != "gh-token" || len(cfg.Hosts) != 1 {
+		t.Errorf("expected github.com token to be stored in github_token, got %+v", cfg)
+	}
+
+	if !cfg.RemoveHost("github.example.com") || cfg.IsConfiguredHost("github.example.com") {
+		t.Error("expected enterprise host to be removed")
+	}
+	if cfg.Hosts != nil {
+		t.Errorf("expected empty hosts to be dropped, got %+v", cfg.Hosts)
+	}
+}
+
+func TestWatchedHosts(t *testing.T) {
+	cfg := DefaultConfig()
+	if hosts := cfg.WatchedHosts(); len(hosts) != 1 || hosts[0] != "github.com" {
+		t.Errorf("expected github.com for an empty watch list, got %v", hosts)
+	}
+
+	cfg.WatchedPRs = []WatchedPR{
+		{Host: "github.example.com", Owner: "a", Repo: "b", Number: 1},
+		{Owner: "a", Repo: "b", Number: 2},
+		{Host: "github.example.com", Owner: "a", Repo: "b", Number: 3},
+	}
+	hosts := cfg.WatchedHosts()
+	if len(hosts) != 2 || hosts[0] != "github.example.com" || hosts[1] != "github.com" {
+		t.Errorf("unexpected hosts %v", hosts)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// DefaultFlakyMaxReruns is the number of automatic reruns of a flaky check per
+// commit when flaky_max_reruns is not set.
+const DefaultFlakyMaxReruns = 2
+
+// AutoRerun records a failed check that prw re-ran automatically.
+type AutoRerun struct {
+	Check string    `json:"check"`
+	SHA   string    `json:"sha"`
+	JobID int64     `json:"job_id"`
+	At    time.Time `json:"at"`
+}
+
+// ParseFlakyChecks parses a comma-separated list of check names or glob patterns.
+// "none" or an empty value clears the list.
+func ParseFlakyChecks(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return nil, nil
+	}
+
+	var checks []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if _, err := path.Match(strings.ToLower(name), ""); err != nil {
+			return nil, fmt.Errorf("invalid flaky check pattern %q", name)
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		checks = append(checks, name)
+	}
+	return checks, nil
+}
+
+// IsFlakyCheck reports whether a check is on the known flaky list. Names match
+// case-insensitively and may use glob patterns such as "e2e-*".
+func (c *Config) IsFlakyCheck(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range c.FlakyChecks {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FlakyRerunLimit returns the number of automatic reruns allowed per check and commit.
+func (c *Config) FlakyRerunLimit() int {
+	if c.FlakyMaxReruns > 0 {
+		return c.FlakyMaxReruns
+	}
+	return DefaultFlakyMaxReruns
+}
+
+// RerunCount returns how often a check was re-run automatically on a commit.
+func (pr *WatchedPR) RerunCount(check, sha string) int {
+	count := 0
+	for _, rerun := range pr.Reruns {
+		if rerun.SHA == sha && rerun.Check == check {
+			count++
+		}
+	}
+	return count
+}
+
+// WasRerun reports whether the check run with the given job ID was already re-run.
+func (pr *WatchedPR) WasRerun(jobID int64) bool {
+	for _, rerun := range pr.Reruns {
+		if rerun.JobID == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// RerunsFor returns the automatic reruns recorded for a commit.
+func (pr *WatchedPR) RerunsFor(sha string) []AutoRerun {
+	var reruns []AutoRerun
+	for _, rerun := range pr.Reruns {
+		if rerun.SHA == sha {
+			reruns = append(reruns, rerun)
+		}
+	}
+	return reruns
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlakyChecks(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "e2e, Integration*, e2e", want: []string{"e2e", "Integration*"}},
+		{value: "none", want: nil},
+		{value: "", want: nil},
+		{value: "bad[", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFlakyChecks(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFlakyChecks(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFlakyChecks(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIsFlakyCheck(t *testing.T) {
+	cfg := &Config{FlakyChecks: []string{"e2e-*", "Integration"}}
+	tests := map[string]bool{
+		"e2e-chrome":  true,
+		"E2E-firefox": true,
+		"integration": true,
+		"unit":        false,
+	}
+	for name, want := range tests {
+		if got := cfg.IsFlakyCheck(name); got != want {
+			t.Errorf("IsFlakyCheck(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if got := cfg.FlakyRerunLimit(); got != DefaultFlakyMaxReruns {
+		t.Errorf("FlakyRerunLimit() = %d, want %d", got, DefaultFlakyMaxReruns)
+	}
+}
+
+func TestWatchedPRReruns(t *testing.T) {
+	pr := WatchedPR{Reruns: []AutoRerun{
+		{Check: "e2e", SHA: "a", JobID: 1},
+		{Check: "e2e", SHA: "a", JobID: 2},
+		{Check: "e2e", SHA: "b", JobID: 3},
+	}}
+	if got := pr.RerunCount("e2e", "a"); got != 2 {
+		t.Errorf("RerunCount() = %d, want 2", got)
+	}
+	if !pr.WasRerun(3) || pr.WasRerun(4) {
+		t.Error("WasRerun() did not match the recorded job IDs")
+	}
+	if got := pr.RerunsFor("b"); len(got) != 1 || got[0].JobID != 3 {
+		t.Errorf("RerunsFor() = %+v", got)
+	}
+}
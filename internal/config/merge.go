@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Requirements a PR must meet to be reported as ready to merge.
+const (
+	MergeRequireChecks     = "checks"     // CI state is success
+	MergeRequireApproval   = "approval"   // review decision is approved
+	MergeRequireMergeable  = "mergeable"  // no merge conflicts
+	MergeRequireNotDraft   = "not_draft"  // not a draft
+	MergeRequireUpToDate   = "up_to_date" // head branch is not behind the base branch
+	MergeRequireProtection = "protection" // branch protection is satisfied (not blocked)
+)
+
+// DefaultMergeRuleKey is the merge rule key that applies to repos without their own rule.
+const DefaultMergeRuleKey = "*"
+
+var mergeRequirements = []string{
+	MergeRequireChecks,
+	MergeRequireApproval,
+	MergeRequireMergeable,
+	MergeRequireNotDraft,
+	MergeRequireUpToDate,
+	MergeRequireProtection,
+}
+
+// DefaultMergeRule returns the requirements used when no merge rule is configured.
+func DefaultMergeRule() []string {
+	return []string{MergeRequireChecks, MergeRequireApproval, MergeRequireMergeable, MergeRequireNotDraft}
+}
+
+// MergeRuleFor returns the ready-to-merge requirements for owner/repo. Rules are
+// looked up by "owner/repo", then "owner/*", then "*", falling back to DefaultMergeRule.
+func (c *Config) MergeRuleFor(owner, repo string) []string {
+	for _, key := range repoRuleKeys(owner, repo) {
+		if rule, ok := c.MergeRules[key]; ok {
+			return rule
+		}
+	}
+	return DefaultMergeRule()
+}
+
+// repoRuleKeys returns the keys of per-repo settings that apply to owner/repo,
+// most specific first.
+func repoRuleKeys(owner, repo string) []string {
+	return []string{
+		strings.ToLower(owner + "/" + repo),
+		strings.ToLower(owner) + "/*",
+		DefaultMergeRuleKey,
+	}
+}
+
+// SetMergeRule stores the requirements for a rule key (owner/repo, owner/*, or *).
+func (c *Config) SetMergeRule(key string, requirements []string) {
+	if c.MergeRules == nil {
+		c.MergeRules = make(map[string][]string)
+	}
+	c.MergeRules[strings.ToLower(key)] = requirements
+}
+
+// RemoveMergeRule deletes the rule for key and reports whether it existed.
+func (c *Config) RemoveMergeRule(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := c.MergeRules[key]; !ok {
+		return false
+	}
+	delete(c.MergeRules, key)
+	if len(c.MergeRules) == 0 {
+		c.MergeRules = nil
+	}
+	return true
+}
+
+// MergeRuleKeys returns the configured rule keys in sorted order.
+func (c *Config) MergeRuleKeys() []string {
+	keys := make([]string, 0, len(c.MergeRules))
+	for key := range c.MergeRules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsValidMergeRuleKey reports whether key is owner/repo, owner/*, or *.
+func IsValidMergeRuleKey(key string) bool {
+	if key == DefaultMergeRuleKey {
+		return true
+	}
+	owner, repo, ok := strings.Cut(key, "/")
+	return ok && owner != "" && owner != "*" && repo != "" && !strings.Contains(repo, "/")
+}
+
+// ParseMergeRequirements parses a comma-separated list of requirements. An empty
+// list or "none" means every open PR counts as ready.
+func ParseMergeRequirements(value string) ([]string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "none" {
+		return []string{}, nil
+	}
+
+	var requirements []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		req := strings.TrimSpace(part)
+		if !isMergeRequirement(req) {
+			return nil, fmt.Errorf("unknown merge requirement %q (expected %s)", req, strings.Join(mergeRequirements, ", "))
+		}
+		if !seen[req] {
+			seen[req] = true
+			requirements = append(requirements, req)
+		}
+	}
+	return requirements, nil
+}
+
+func isMergeRequirement(value string) bool {
+	for _, req := range mergeRequirements {
+		if value == req {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeRuleFor(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.MergeRuleFor("owner", "repo"); !reflect.DeepEqual(got, DefaultMergeRule()) {
+		t.Errorf("expected default rule without configuration, got %v", got)
+	}
+
+	cfg.SetMergeRule("*", []string{MergeRequireChecks})
+	cfg.SetMergeRule("Owner/*", []string{MergeRequireApproval})
+	cfg.SetMergeRule("owner/special", []string{})
+
+	tests := []struct {
+		owner, repo string
+		want        []string
+	}{
+		{"owner", "special", []string{}},
+		{"OWNER", "Special", []string{}},
+		{"owner", "other", []string{MergeRequireApproval}},
+		{"someone", "repo", []string{MergeRequireChecks}},
+	}
+	for _, tt := range tests {
+		if got := cfg.MergeRuleFor(tt.owner, tt.repo); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MergeRuleFor(%s, %s) = %v, want %v", tt.owner, tt.repo, got, tt.want)
+		}
+	}
+
+	if !cfg.RemoveMergeRule("OWNER/*") || cfg.RemoveMergeRule("owner/*") {
+		t.Error("expected the owner rule to be removed exactly once")
+	}
+	if got := cfg.MergeRuleKeys(); !reflect.DeepEqual(got, []string{"*", "owner/special"}) {
+		t.Errorf("MergeRuleKeys() = %v", got)
+	}
+}
+
+func TestParseMergeRequirements(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"checks,approval", []string{MergeRequireChecks, MergeRequireApproval}, false},
+		{" Checks , checks ,up_to_date", []string{MergeRequireChecks, MergeRequireUpToDate}, false},
+		{"none", []string{}, false},
+		{"", []string{}, false},
+		{"checks,green", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMergeRequirements(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMergeRequirements(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMergeRequirements(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidMergeRuleKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"*":          true,
+		"owner/repo": true,
+		"owner/*":    true,
+		"owner":      false,
+		"*/repo":     false,
+		"a/b/c":      false,
+		"/repo":      false,
+	} {
+		if got := IsValidMergeRuleKey(key); got != want {
+			t.Errorf("IsValidMergeRuleKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
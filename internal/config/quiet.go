@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours holds back webhook and native notifications during scheduled
+// windows; they are delivered as one summary when the window ends.
+type QuietHours struct {
+	Windows       []QuietWindow `json:"windows,omitempty"`
+	Timezone      string        `json:"timezone,omitempty"`       // IANA name; empty means local time
+	AllowFailures bool          `json:"allow_failures,omitempty"` // CI failures are delivered immediately
+}
+
+// QuietWindow is a daily time range, optionally limited to some weekdays. A
+// window whose end is not after its start crosses midnight and belongs to the
+// day it starts on.
+type QuietWindow struct {
+	Days  []string `json:"days,omitempty"` // "mon" to "sun"; empty means every day
+	Start string   `json:"start"`          // HH:MM
+	End   string   `json:"end"`            // HH:MM, or 24:00 for midnight
+}
+
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseQuietWindows parses a comma-separated list of quiet windows such as
+// "mon-fri 22:00-07:00, sat-sun 00:00-24:00". Days are a day ("sat"), a range
+// ("mon-fri"), days joined with "+" ("mon+wed"), or weekdays, weekends, or
+// daily; without days a window applies every day. "none" or an empty value
+// clears the schedule.
+func ParseQuietWindows(value string) ([]QuietWindow, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return nil, nil
+	}
+
+	var windows []QuietWindow
+	for _, part := range strings.Split(value, ",") {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid quiet window %q (expected [days] HH:MM-HH:MM)", strings.TrimSpace(part))
+		}
+
+		var window QuietWindow
+		if len(fields) == 2 {
+			days, err := parseQuietDays(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			window.Days = days
+		}
+
+		start, end, ok := strings.Cut(fields[len(fields)-1], "-")
+		startMin, startErr := parseClock(start)
+		endMin, endErr := parseClock(end)
+		if !ok || startErr != nil || endErr != nil || startMin == 24*60 || startMin == endMin {
+			return nil, fmt.Errorf("invalid quiet window %q (expected [days] HH:MM-HH:MM)", strings.TrimSpace(part))
+		}
+		window.Start, window.End = start, end
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// parseQuietDays parses the days of a quiet window into day names in week order.
+func parseQuietDays(value string) ([]string, error) {
+	switch value {
+	case "daily":
+		return nil, nil
+	case "weekdays":
+		value = "mon-fri"
+	case "weekends":
+		value = "sat+sun"
+	}
+
+	selected := make([]bool, len(weekdayNames))
+	for _, part := range strings.Split(value, "+") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdayIndex(from)
+		last := first
+		if isRange {
+			var lastOK bool
+			last, lastOK = weekdayIndex(to)
+			ok = ok && lastOK
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid quiet days %q (expected e.g. mon-fri, sat+sun, weekdays, weekends, or daily)", value)
+		}
+		for day := first; ; day = (day + 1) % len(weekdayNames) {
+			selected[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+
+	// Monday first, as schedules are usually written.
+	var days []string
+	for i := 1; i <= len(weekdayNames); i++ {
+		day := i % len(weekdayNames)
+		if selected[day] {
+			days = append(days, weekdayNames[day])
+		}
+	}
+	if len(days) == len(weekdayNames) {
+		return nil, nil
+	}
+	return days, nil
+}
+
+func weekdayIndex(name string) (int, bool) {
+	for i, day := range weekdayNames {
+		if name == day {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses HH:MM into minutes after midnight; 24:00 is allowed.
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// FormatQuietWindows formats windows the way ParseQuietWindows accepts them.
+func FormatQuietWindows(windows []QuietWindow) string {
+	if len(windows) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(windows))
+	for _, window := range windows {
+		part := window.Start + "-" + window.End
+		if len(window.Days) > 0 {
+			part = strings.Join(window.Days, "+") + " " + part
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ValidateTimezone checks that name is an IANA time zone; empty means local time.
+func ValidateTimezone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown time zone %q (expected an IANA name like Europe/Berlin)", name)
+	}
+	return nil
+}
+
+// Location returns the time zone of the schedule, falling back to local time.
+func (q *QuietHours) Location() *time.Location {
+	if q.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Active reports whether t falls into one of the quiet windows.
+func (q *QuietHours) Active(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	t = t.In(q.Location())
+	now := t.Hour()*60 + t.Minute()
+	today := weekdayNames[t.Weekday()]
+	yesterday := weekdayNames[(t.Weekday()+6)%7]
+
+	for _, window := range q.Windows {
+		start, err := parseClock(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(window.End)
+		if err != nil {
+			continue
+		}
+		if start < end {
+			if window.onDay(today) && now >= start && now < end {
+				return true
+			}
+			continue
+		}
+		// The window crosses midnight: it started today or yesterday.
+		if (window.onDay(today) && now >= start) || (window.onDay(yesterday) && now < end) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w QuietWindow) onDay(day string) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuietWindows(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []QuietWindow
+		err   bool
+	}{
+		{value: "none"},
+		{value: "22:00-07:00", want: []QuietWindow{{Start: "22:00", End: "07:00"}}},
+		{
+			value: "mon-fri 22:00-07:00, weekends 00:00-24:00",
+			want: []QuietWindow{
+				{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "22:00", End: "07:00"},
+				{Days: []string{"sat", "sun"}, Start: "00:00", End: "24:00"},
+			},
+		},
+		{value: "Fri-Mon 18:00-09:00", want: []QuietWindow{{Days: []string{"mon", "fri", "sat", "sun"}, Start: "18:00", End: "09:00"}}},
+		{value: "wed+mon 12:00-13:00", want: []QuietWindow{{Days: []string{"mon", "wed"}, Start: "12:00", End: "13:00"}}},
+		{value: "daily 01:00-02:00", want: []QuietWindow{{Start: "01:00", End: "02:00"}}},
+		{value: "22:00", err: true},
+		{value: "22:00-22:00", err: true},
+		{value: "25:00-07:00", err: true},
+		{value: "7:00-08:00", err: true},
+		{value: "someday 22:00-07:00", err: true},
+		{value: "mon fri 22:00-07:00", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuietWindows(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseQuietWindows(%q) error = %v, want error %v", tt.value, err, tt.err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseQuietWindows(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+
+	windows, _ := ParseQuietWindows("mon-fri 22:00-07:00, 12:00-13:00")
+	if got := FormatQuietWindows(windows); got != "mon+tue+wed+thu+fri 22:00-07:00, 12:00-13:00" {
+		t.Errorf("FormatQuietWindows() = %q", got)
+	}
+}
+
+func TestQuietHoursActive(t *testing.T) {
+	windows, err := ParseQuietWindows("mon-fri 22:00-07:00, sat-sun 00:00-24:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quiet := &QuietHours{Windows: windows, Timezone: "America/New_York"}
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2024, 6, 3, 21, 59, 0, 0, loc), false}, // Monday evening
+		{time.Date(2024, 6, 3, 22, 0, 0, 0, loc), true},
+		{time.Date(2024, 6, 4, 6, 59, 0, 0, loc), true}, // Tuesday morning, from Monday's window
+		{time.Date(2024, 6, 4, 7, 0, 0, 0, loc), false},
+		{time.Date(2024, 6, 3, 3, 0, 0, 0, loc), false}, // Monday morning: windows belong to the day they start on
+		{time.Date(2024, 6, 8, 13, 0, 0, 0, loc), true}, // Saturday
+		{time.Date(2024, 6, 9, 23, 0, 0, 0, loc), true}, // Sunday night
+		{time.Date(2024, 6, 3, 12, 0, 0, 0, loc), false},
+		// 02:00 UTC on a Tuesday is Monday 22:00 in New York.
+		{time.Date(2024, 6, 4, 2, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := quiet.Active(tt.at); got != tt.want {
+			t.Errorf("Active(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	var none *QuietHours
+	if none.Active(time.Now()) {
+		t.Error("expected no quiet hours without a schedule")
+	}
+}
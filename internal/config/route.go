@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in notifiers that routes can name without defining them. Except for
+// the console and native notifiers, they are only active when configured.
+const (
+	NotifierConsole = "console"
+	NotifierNative  = "native"
+	NotifierWebhook = "webhook"
+	NotifierSlack   = "slack"
+	NotifierEmail   = "email"
+)
+
+// BuiltinNotifiers lists the built-in notifier names.
+var BuiltinNotifiers = []string{NotifierConsole, NotifierNative, NotifierWebhook, NotifierSlack, NotifierEmail}
+
+// NotifierConfig defines a named notifier instance that routes can deliver to.
+// Its Type is one of the built-in webhook, slack, or email notifiers; the
+// instance reuses the global settings of that type, such as the retry policy,
+// slack_bot_token, or the SMTP server, and overrides where it delivers.
+type NotifierConfig struct {
+	Type    string   `json:"type"`
+	URL     string   `json:"url,omitempty"`     // webhook URL, or Slack incoming webhook
+	Format  string   `json:"format,omitempty"`  // webhook payload format; empty means auto
+	Token   string   `json:"token,omitempty"`   // Slack bot token; empty means slack_bot_token
+	Channel string   `json:"channel,omitempty"` // Slack channel for the bot
+	To      []string `json:"to,omitempty"`      // email recipients
+}
+
+// Route sends the events it matches to named notifiers. Every non-empty
+// criterion must match; within a criterion any value may match.
+type Route struct {
+	Repos   []string `json:"repos,omitempty"`   // owner/repo glob patterns, e.g. "org/*"
+	Authors []string `json:"authors,omitempty"` // PR author logins
+	Labels  []string `json:"labels,omitempty"`  // the PR has at least one of these labels
+	Events  []string `json:"events,omitempty"`  // event types, e.g. status_change or merged
+	From    []string `json:"from,omitempty"`    // previous states
+	To      []string `json:"to,omitempty"`      // current states
+	Notify  []string `json:"notify"`            // notifier names
+	Stop    bool     `json:"stop,omitempty"`    // skip the routes after this one when it matches
+}
+
+// notifierNamePattern is the format of notifier instance names.
+var notifierNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// IsBuiltinNotifier reports whether name is a built-in notifier.
+func IsBuiltinNotifier(name string) bool {
+	for _, n := range BuiltinNotifiers {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNotifier reports whether name is a built-in notifier or a defined instance.
+func (c *Config) HasNotifier(name string) bool {
+	_, ok := c.Notifiers[name]
+	return ok || IsBuiltinNotifier(name)
+}
+
+// SetNotifier defines or replaces the named notifier instance.
+func (c *Config) SetNotifier(name string, notifier NotifierConfig) error {
+	if !notifierNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid notifier name %q (use lowercase letters, digits, - and _)", name)
+	}
+	if IsBuiltinNotifier(name) {
+		return fmt.Errorf("%q is a built-in notifier; pick another name", name)
+	}
+	switch notifier.Type {
+	case NotifierWebhook:
+		if notifier.URL == "" {
+			return fmt.Errorf("a webhook notifier needs a URL")
+		}
+	case NotifierSlack:
+		if notifier.URL == "" && notifier.Channel == "" {
+			return fmt.Errorf("a slack notifier needs an incoming webhook URL or a channel")
+		}
+	case NotifierEmail:
+		if len(notifier.To) == 0 {
+			return fmt.Errorf("an email notifier needs recipients")
+		}
+	default:
+		return fmt.Errorf("invalid notifier type %q (expected webhook, slack, or email)", notifier.Type)
+	}
+
+	if c.Notifiers == nil {
+		c.Notifiers = make(map[string]NotifierConfig)
+	}
+	c.Notifiers[name] = notifier
+	return nil
+}
+
+// RemoveNotifier deletes the named notifier instance and reports whether it
+// existed. Instances used by a route cannot be removed.
+func (c *Config) RemoveNotifier(name string) (bool, error) {
+	if _, ok := c.Notifiers[name]; !ok {
+		return false, nil
+	}
+	for i, route := range c.Routes {
+		for _, target := range route.Notify {
+			if target == name {
+				return false, fmt.Errorf("notifier %q is used by route %d; remove the route first", name, i+1)
+			}
+		}
+	}
+	delete(c.Notifiers, name)
+	if len(c.Notifiers) == 0 {
+		c.Notifiers = nil
+	}
+	return true, nil
+}
+
+// NotifierNames returns the names of the notifier instances in sorted order.
+func (c *Config) NotifierNames() []string {
+	names := make([]string, 0, len(c.Notifiers))
+	for name := range c.Notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateRoute checks that a route names known notifiers and valid repo patterns.
+func (c *Config) ValidateRoute(route Route) error {
+	if len(route.Notify) == 0 {
+		return fmt.Errorf("a route needs at least one notifier")
+	}
+	for _, name := range route.Notify {
+		if !c.HasNotifier(name) {
+			return fmt.Errorf("unknown notifier %q; define it with 'prw notifier add'", name)
+		}
+	}
+	for _, pattern := range route.Repos {
+		if !strings.Contains(pattern, "/") {
+			return fmt.Errorf("invalid repo pattern %q (expected owner/repo, e.g. org/*)", pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repo pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// AddRoute validates a route and appends it after the existing routes.
+func (c *Config) AddRoute(route Route) error {
+	if err := c.ValidateRoute(route); err != nil {
+		return err
+	}
+	c.Routes = append(c.Routes, route)
+	return nil
+}
+
+// RemoveRoute deletes the route at the 1-based position n and reports whether it existed.
+func (c *Config) RemoveRoute(n int) bool {
+	if n < 1 || n > len(c.Routes) {
+		return false
+	}
+	c.Routes = append(c.Routes[:n-1], c.Routes[n:]...)
+	if len(c.Routes) == 0 {
+		c.Routes = nil
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestNotifierInstances(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.SetNotifier("api-ci", NotifierConfig{Type: NotifierWebhook, URL: "https://example.com/hook"}); err != nil {
+		t.Fatalf("SetNotifier failed: %v", err)
+	}
+	if err := cfg.SetNotifier("team", NotifierConfig{Type: NotifierSlack, Channel: "#team"}); err != nil {
+		t.Fatalf("SetNotifier failed: %v", err)
+	}
+
+	invalid := []struct {
+		name     string
+		notifier NotifierConfig
+	}{
+		{"console", NotifierConfig{Type: NotifierWebhook, URL: "https://example.com"}},
+		{"API CI", NotifierConfig{Type: NotifierWebhook, URL: "https://example.com"}},
+		{"hook", NotifierConfig{Type: NotifierWebhook}},
+		{"chat", NotifierConfig{Type: NotifierSlack}},
+		{"mail", NotifierConfig{Type: NotifierEmail}},
+		{"pager", NotifierConfig{Type: "pagerduty"}},
+	}
+	for _, tt := range invalid {
+		if err := cfg.SetNotifier(tt.name, tt.notifier); err == nil {
+			t.Errorf("expected SetNotifier(%q, %+v) to fail", tt.name, tt.notifier)
+		}
+	}
+
+	if names := cfg.NotifierNames(); len(names) != 2 || names[0] != "api-ci" || names[1] != "team" {
+		t.Errorf("NotifierNames() = %v", names)
+	}
+	if !cfg.HasNotifier("api-ci") || !cfg.HasNotifier("native") || cfg.HasNotifier("pager") {
+		t.Error("unexpected HasNotifier results")
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetNotifier("api-ci", NotifierConfig{Type: NotifierWebhook, URL: "https://example.com/hook"})
+
+	if err := cfg.AddRoute(Route{Repos: []string{"org/api"}, To: []string{"failure"}, Notify: []string{"api-ci"}}); err != nil {
+		t.Fatalf("AddRoute failed: %v", err)
+	}
+	if err := cfg.AddRoute(Route{Notify: []string{"console"}}); err != nil {
+		t.Fatalf("AddRoute failed: %v", err)
+	}
+	for _, route := range []Route{
+		{},
+		{Notify: []string{"pager"}},
+		{Repos: []string{"org"}, Notify: []string{"console"}},
+		{Repos: []string{"org/[api"}, Notify: []string{"console"}},
+	} {
+		if err := cfg.AddRoute(route); err == nil {
+			t.Errorf("expected AddRoute(%+v) to fail", route)
+		}
+	}
+
+	if removed, err := cfg.RemoveNotifier("api-ci"); removed || err == nil {
+		t.Error("expected a notifier used by a route not to be removed")
+	}
+	if !cfg.RemoveRoute(1) || cfg.RemoveRoute(2) || len(cfg.Routes) != 1 {
+		t.Errorf("unexpected routes after removal: %+v", cfg.Routes)
+	}
+	if removed, err := cfg.RemoveNotifier("api-ci"); !removed || err != nil || cfg.Notifiers != nil {
+		t.Errorf("expected the notifier to be removed, got %v, %v", removed, err)
+	}
+	if !cfg.RemoveRoute(1) || cfg.Routes != nil {
+		t.Errorf("expected no routes left, got %+v", cfg.Routes)
+	}
+}
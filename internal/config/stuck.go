@@ -0,0 +1,50 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// StuckThresholdFor returns how long CI may stay pending on owner/repo before it
+// is reported as stuck. Thresholds are looked up like merge rules, by
+// "owner/repo", then "owner/*", then "*"; zero disables the alert.
+func (c *Config) StuckThresholdFor(owner, repo string) time.Duration {
+	for _, key := range repoRuleKeys(owner, repo) {
+		if minutes, ok := c.StuckAfterMinutes[key]; ok {
+			return time.Duration(minutes) * time.Minute
+		}
+	}
+	return 0
+}
+
+// SetStuckThreshold stores the stuck threshold in minutes for a key (owner/repo, owner/*, or *).
+func (c *Config) SetStuckThreshold(key string, minutes int) {
+	if c.StuckAfterMinutes == nil {
+		c.StuckAfterMinutes = make(map[string]int)
+	}
+	c.StuckAfterMinutes[strings.ToLower(key)] = minutes
+}
+
+// RemoveStuckThreshold deletes the threshold for key and reports whether it existed.
+func (c *Config) RemoveStuckThreshold(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := c.StuckAfterMinutes[key]; !ok {
+		return false
+	}
+	delete(c.StuckAfterMinutes, key)
+	if len(c.StuckAfterMinutes) == 0 {
+		c.StuckAfterMinutes = nil
+	}
+	return true
+}
+
+// StuckThresholdKeys returns the keys with a stuck threshold in sorted order.
+func (c *Config) StuckThresholdKeys() []string {
+	keys := make([]string, 0, len(c.StuckAfterMinutes))
+	for key := range c.StuckAfterMinutes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
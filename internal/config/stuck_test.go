@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStuckThresholdFor(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.StuckThresholdFor("owner", "repo"); got != 0 {
+		t.Errorf("expected no threshold by default, got %v", got)
+	}
+
+	cfg.SetStuckThreshold("*", 60)
+	cfg.SetStuckThreshold("Owner/*", 30)
+	cfg.SetStuckThreshold("owner/slow", 0)
+
+	tests := []struct {
+		owner, repo string
+		want        time.Duration
+	}{
+		{"owner", "repo", 30 * time.Minute},
+		{"owner", "slow", 0},
+		{"other", "repo", time.Hour},
+	}
+	for _, tt := range tests {
+		if got := cfg.StuckThresholdFor(tt.owner, tt.repo); got != tt.want {
+			t.Errorf("StuckThresholdFor(%s, %s) = %v, want %v", tt.owner, tt.repo, got, tt.want)
+		}
+	}
+
+	if !cfg.RemoveStuckThreshold("owner/*") || cfg.RemoveStuckThreshold("owner/*") {
+		t.Error("expected the threshold to be removed once")
+	}
+	if keys := cfg.StuckThresholdKeys(); len(keys) != 2 || keys[0] != "*" || keys[1] != "owner/slow" {
+		t.Errorf("StuckThresholdKeys() = %v", keys)
+	}
+}
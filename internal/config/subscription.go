@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+// Kinds of subscriptions.
+const (
+	SubscriptionRepo            = "repo"             // every open PR in owner/repo
+	SubscriptionAuthor          = "author"           // every open PR by a user
+	SubscriptionReviewRequested = "review-requested" // every open PR requesting a user's review
+	SubscriptionSearch          = "search"           // every open PR matching a search query
+)
+
+// DefaultSubscriptionIntervalSeconds is how often each subscription's search runs
+// when subscription_interval_seconds is not set. Searches are paginated and share
+// GitHub's search budget of 30 requests per minute, so they run less often than
+// PRs are polled.
+const DefaultSubscriptionIntervalSeconds = 120
+
+// SubscriptionInterval returns how often each subscription's search runs.
+func (c *Config) SubscriptionInterval() time.Duration {
+	if c.SubscriptionIntervalSeconds > 0 {
+		return time.Duration(c.SubscriptionIntervalSeconds) * time.Second
+	}
+	return DefaultSubscriptionIntervalSeconds * time.Second
+}
+
+// Subscription adds every open PR matching it to the watch list. PRs added by a
+// subscription are dropped again once they no longer match, e.g. when closed.
+type Subscription struct {
+	Host  string `json:"host,omitempty"` // empty means github.com
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// NewSubscription validates and normalizes a subscription. The value of a
+// review-requested subscription defaults to "@me", the token's user.
+func NewSubscription(host, kind, value string) (Subscription, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	value = strings.TrimSpace(value)
+	host = github.NormalizeHost(host)
+	if host == github.DefaultHost {
+		host = ""
+	}
+
+	switch kind {
+	case SubscriptionRepo:
+		owner, repo, ok := strings.Cut(value, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return Subscription{}, fmt.Errorf("repo subscription must be owner/repo, got %q", value)
+		}
+	case SubscriptionAuthor:
+		if value == "" || strings.ContainsAny(value, " /:") {
+			return Subscription{}, fmt.Errorf("author subscription needs a GitHub username, got %q", value)
+		}
+	case SubscriptionReviewRequested:
+		if value == "" {
+			value = "@me"
+		}
+		if strings.ContainsAny(value, " :") {
+			return Subscription{}, fmt.Errorf("review-requested subscription needs a GitHub username, got %q", value)
+		}
+	case SubscriptionSearch:
+		if value == "" {
+			return Subscription{}, fmt.Errorf("search subscription needs a query")
+		}
+	default:
+		return Subscription{}, fmt.Errorf("unknown subscription kind %q (expected repo, author, review-requested, or search)", kind)
+	}
+
+	return Subscription{Host: host, Kind: kind, Value: value}, nil
+}
+
+// HostName returns the normalized host of the subscription, defaulting to github.com.
+func (s Subscription) HostName() string {
+	return github.NormalizeHost(s.Host)
+}
+
+// Key identifies the subscription, e.g. "repo:owner/repo". It is stored on the
+// PRs the subscription added.
+func (s Subscription) Key() string {
+	key := s.Kind + ":" + s.Value
+	if host := s.HostName(); host != github.DefaultHost {
+		key = host + "/" + key
+	}
+	return key
+}
+
+// Query returns the GitHub search query listing the subscription's open PRs.
+func (s Subscription) Query() string {
+	const base = "is:pr is:open"
+	switch s.Kind {
+	case SubscriptionRepo:
+		return base + " repo:" + s.Value
+	case SubscriptionAuthor:
+		return base + " author:" + s.Value
+	case SubscriptionReviewRequested:
+		return base + " review-requested:" + s.Value
+	default:
+		return base + " " + s.Value
+	}
+}
+
+// AddSubscription adds a subscription if not already present.
+func (c *Config) AddSubscription(sub Subscription) bool {
+	for _, existing := range c.Subscriptions {
+		if existing.Key() == sub.Key() {
+			return false
+		}
+	}
+	c.Subscriptions = append(c.Subscriptions, sub)
+	return true
+}
+
+// RemoveSubscription removes a subscription along with the PRs it added and
+// returns those PRs.
+func (c *Config) RemoveSubscription(sub Subscription) (bool, []WatchedPR) {
+	key := sub.Key()
+	found := false
+	kept := c.Subscriptions[:0]
+	for _, existing := range c.Subscriptions {
+		if existing.Key() == key {
+			found = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	c.Subscriptions = kept
+	if !found {
+		return false, nil
+	}
+
+	var removed []WatchedPR
+	prs := c.WatchedPRs[:0]
+	for _, pr := range c.WatchedPRs {
+		if pr.Subscription == key {
+			removed = append(removed, pr)
+			continue
+		}
+		prs = append(prs, pr)
+	}
+	c.WatchedPRs = prs
+	return true, removed
+}
+
+// ReconcileSubscriptions updates the watch list with the latest search results
+// of each subscription, keyed by Subscription.Key. Matching PRs that are not
+// watched yet are added; PRs added by a subscription that no longer match any
+// subscription are removed. PRs of subscriptions missing from results, e.g.
+// because the search failed, are left alone. Explicitly watched PRs are never
+// removed.
+func (c *Config) ReconcileSubscriptions(results map[string][]github.PullRequestRef) (added, removed []WatchedPR) {
+	// matched maps each PR found by any subscription to the first subscription that found it
+	matched := make(map[string]string)
+	for _, sub := range c.Subscriptions {
+		refs, ok := results[sub.Key()]
+		if !ok {
+			continue
+		}
+		for _, ref := range refs {
+			prKey := watchKey(sub.HostName(), ref.Owner, ref.Repo, ref.Number)
+			if _, seen := matched[prKey]; !seen {
+				matched[prKey] = sub.Key()
+			}
+		}
+	}
+
+	watched := make(map[string]bool)
+	kept := c.WatchedPRs[:0]
+	for _, pr := range c.WatchedPRs {
+		if pr.Subscription != "" {
+			if _, searched := results[pr.Subscription]; searched || !c.hasSubscription(pr.Subscription) {
+				subKey, ok := matched[watchKey(pr.HostName(), pr.Owner, pr.Repo, pr.Number)]
+				if !ok {
+					removed = append(removed, pr)
+					continue
+				}
+				pr.Subscription = subKey
+			}
+		}
+		watched[watchKey(pr.HostName(), pr.Owner, pr.Repo, pr.Number)] = true
+		kept = append(kept, pr)
+	}
+	c.WatchedPRs = kept
+
+	for _, sub := range c.Subscriptions {
+		for _, ref := range results[sub.Key()] {
+			prKey := watchKey(sub.HostName(), ref.Owner, ref.Repo, ref.Number)
+			if watched[prKey] {
+				continue
+			}
+			watched[prKey] = true
+			pr := WatchedPR{
+				Host:         sub.Host,
+				Owner:        ref.Owner,
+				Repo:         ref.Repo,
+				Number:       ref.Number,
+				Title:        ref.Title,
+				Subscription: sub.Key(),
+			}
+			c.WatchedPRs = append(c.WatchedPRs, pr)
+			added = append(added, pr)
+		}
+	}
+	return added, removed
+}
+
+func (c *Config) hasSubscription(key string) bool {
+	for _, sub := range c.Subscriptions {
+		if sub.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// watchKey identifies a PR across hosts. Owner and repo are compared
+// case-insensitively because search results may differ in case from the URL a
+// PR was watched with.
+func watchKey(host, owner, repo string, number int) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s#%d", github.NormalizeHost(host), owner, repo, number))
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+func TestNewSubscription(t *testing.T) {
+	tests := []struct {
+		host, kind, value string
+		wantKey           string
+		wantQuery         string
+		wantErr           bool
+	}{
+		{"", "repo", "owner/repo", "repo:owner/repo", "is:pr is:open repo:owner/repo", false},
+		{"github.com", "Author", " alice ", "author:alice", "is:pr is:open author:alice", false},
+		{"", "review-requested", "", "review-requested:@me", "is:pr is:open review-requested:@me", false},
+		{"GitHub.Example.com", "search", "org:acme label:urgent", "github.example.com/search:org:acme label:urgent", "is:pr is:open org:acme label:urgent", false},
+		{"", "repo", "owner", "", "", true},
+		{"", "author", "", "", "", true},
+		{"", "search", "", "", "", true},
+		{"", "team", "platform", "", "", true},
+	}
+	for _, tt := range tests {
+		sub, err := NewSubscription(tt.host, tt.kind, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewSubscription(%q, %q, %q) error = %v, wantErr %v", tt.host, tt.kind, tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if sub.Key() != tt.wantKey || sub.Query() != tt.wantQuery {
+			t.Errorf("NewSubscription(%q, %q, %q): key %q query %q, want %q and %q", tt.host, tt.kind, tt.value, sub.Key(), sub.Query(), tt.wantKey, tt.wantQuery)
+		}
+	}
+}
+
+func TestAddRemoveSubscription(t *testing.T) {
+	cfg := DefaultConfig()
+	sub, _ := NewSubscription("", "repo", "owner/repo")
+	if !cfg.AddSubscription(sub) || cfg.AddSubscription(sub) {
+		t.Fatal("expected the subscription to be added exactly once")
+	}
+	cfg.AddPR(WatchedPR{Owner: "owner", Repo: "repo", Number: 1, Subscription: sub.Key()})
+	cfg.AddPR(WatchedPR{Owner: "owner", Repo: "repo", Number: 2})
+
+	ok, removed := cfg.RemoveSubscription(sub)
+	if !ok || len(removed) != 1 || removed[0].Number != 1 {
+		t.Errorf("RemoveSubscription() = %v, %v", ok, removed)
+	}
+	if len(cfg.Subscriptions) != 0 || len(cfg.WatchedPRs) != 1 || cfg.WatchedPRs[0].Number != 2 {
+		t.Errorf("expected only the explicit watch to remain, got %+v", cfg.WatchedPRs)
+	}
+	if ok, _ := cfg.RemoveSubscription(sub); ok {
+		t.Error("expected removing an unknown subscription to report false")
+	}
+}
+
+func TestReconcileSubscriptions(t *testing.T) {
+	repoSub, _ := NewSubscription("", "repo", "owner/repo")
+	authorSub, _ := NewSubscription("", "author", "alice")
+	cfg := DefaultConfig()
+	cfg.Subscriptions = []Subscription{repoSub, authorSub}
+	cfg.WatchedPRs = []WatchedPR{
+		{Owner: "Owner", Repo: "Repo", Number: 1},                                // explicit, also matched
+		{Owner: "owner", Repo: "repo", Number: 2, Subscription: repoSub.Key()},   // closed since
+		{Owner: "owner", Repo: "repo", Number: 3, Subscription: repoSub.Key()},   // still open
+		{Owner: "other", Repo: "tool", Number: 4, Subscription: authorSub.Key()}, // author search failed
+		{Owner: "owner", Repo: "repo", Number: 5},                                // explicit, closed
+		{Owner: "gone", Repo: "repo", Number: 6, Subscription: "repo:gone/repo"}, // subscription removed
+	}
+
+	added, removed := cfg.ReconcileSubscriptions(map[string][]github.PullRequestRef{
+		repoSub.Key(): {
+			{Owner: "owner", Repo: "repo", Number: 1},
+			{Owner: "owner", Repo: "repo", Number: 3},
+			{Owner: "owner", Repo: "repo", Number: 7, Title: "New"},
+		},
+	})
+
+	if len(added) != 1 || added[0].Number != 7 || added[0].Title != "New" || added[0].Subscription != repoSub.Key() {
+		t.Errorf("added = %+v", added)
+	}
+	var removedNumbers []int
+	for _, pr := range removed {
+		removedNumbers = append(removedNumbers, pr.Number)
+	}
+	if !reflect.DeepEqual(removedNumbers, []int{2, 6}) {
+		t.Errorf("removed PRs %v, want [2 6]", removedNumbers)
+	}
+	var watched []int
+	for _, pr := range cfg.WatchedPRs {
+		watched = append(watched, pr.Number)
+	}
+	if !reflect.DeepEqual(watched, []int{1, 3, 4, 5, 7}) {
+		t.Errorf("watched PRs %v, want [1 3 4 5 7]", watched)
+	}
+	if cfg.WatchedPRs[0].Subscription != "" {
+		t.Error("expected the explicit watch to stay explicit")
+	}
+}
+
+func TestWatchedHostsIncludesSubscriptions(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WatchedPRs = []WatchedPR{{Owner: "owner", Repo: "repo", Number: 1}}
+	sub, _ := NewSubscription("github.example.com", "repo", "team/service")
+	cfg.AddSubscription(sub)
+
+	if got := cfg.WatchedHosts(); !reflect.DeepEqual(got, []string{"github.com", "github.example.com"}) {
+		t.Errorf("WatchedHosts() = %v", got)
+	}
+}
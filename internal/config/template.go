@@ -0,0 +1,48 @@
+package config
+
+import "sort"
+
+// SetTemplate stores the message template of a notifier for an event type or "default".
+func (c *Config) SetTemplate(notifier, event, text string) {
+	if c.Templates == nil {
+		c.Templates = make(map[string]map[string]string)
+	}
+	if c.Templates[notifier] == nil {
+		c.Templates[notifier] = make(map[string]string)
+	}
+	c.Templates[notifier][event] = text
+}
+
+// RemoveTemplate deletes the template of a notifier for an event type, or all
+// of the notifier's templates if event is empty, and reports whether any existed.
+func (c *Config) RemoveTemplate(notifier, event string) bool {
+	templates, ok := c.Templates[notifier]
+	if !ok {
+		return false
+	}
+	if event == "" {
+		delete(c.Templates, notifier)
+	} else {
+		if _, ok := templates[event]; !ok {
+			return false
+		}
+		delete(templates, event)
+		if len(templates) == 0 {
+			delete(c.Templates, notifier)
+		}
+	}
+	if len(c.Templates) == 0 {
+		c.Templates = nil
+	}
+	return true
+}
+
+// TemplateKeys returns the event types with a template for notifier in sorted order.
+func (c *Config) TemplateKeys(notifier string) []string {
+	keys := make([]string, 0, len(c.Templates[notifier]))
+	for key := range c.Templates[notifier] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
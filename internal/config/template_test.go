@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestTemplates(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetTemplate("console", "status_change", "{{.Owner}}")
+	cfg.SetTemplate("console", "default", "{{.Repo}}")
+	cfg.SetTemplate("native", "merged", "merged")
+
+	if keys := cfg.TemplateKeys("console"); len(keys) != 2 || keys[0] != "default" || keys[1] != "status_change" {
+		t.Errorf("TemplateKeys(console) = %v", keys)
+	}
+	if cfg.RemoveTemplate("console", "merged") || cfg.RemoveTemplate("webhook", "") {
+		t.Error("expected missing templates not to be removed")
+	}
+	if !cfg.RemoveTemplate("console", "") || cfg.Templates["console"] != nil {
+		t.Error("expected all console templates to be removed")
+	}
+	if !cfg.RemoveTemplate("native", "merged") || cfg.Templates != nil {
+		t.Errorf("expected no templates left, got %v", cfg.Templates)
+	}
+}
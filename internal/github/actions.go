@@ -0,0 +1,188 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ActionsAppSlug is the app slug of check runs created by GitHub Actions jobs.
+// The IDs of these check runs are also their workflow job IDs.
+const ActionsAppSlug = "github-actions"
+
+// Job is a GitHub Actions workflow job.
+type Job struct {
+	ID         int64     `json:"id"`
+	RunID      int64     `json:"run_id"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HTMLURL    string    `json:"html_url"`
+	Steps      []JobStep `json:"steps"`
+}
+
+// JobStep is a single step of a workflow job.
+type JobStep struct {
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// WorkflowRun is a single run of a GitHub Actions workflow.
+type WorkflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	RunAttempt int    `json:"run_attempt"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// Failed reports whether the run completed without succeeding.
+func (r WorkflowRun) Failed() bool {
+	if NormalizeState(r.Status) != "completed" {
+		return false
+	}
+	state := conclusionState(r.Conclusion)
+	return state == "failure" || state == "error"
+}
+
+// IsActionsJob reports whether the check run is a GitHub Actions job, whose logs
+// can be downloaded with GetJobLogs.
+func (r CheckRun) IsActionsJob() bool {
+	return r.App.Slug == ActionsAppSlug
+}
+
+// FailedStep returns the name of the first step that failed, or "" if none did.
+func (j *Job) FailedStep() string {
+	for _, step := range j.Steps {
+		if conclusionState(step.Conclusion) == "failure" {
+			return step.Name
+		}
+	}
+	return ""
+}
+
+// GetJob fetches a workflow job, including its steps.
+func (c *Client) GetJob(owner, repo string, jobID int64) (*Job, error) {
+	path := fmt.Sprintf("/repos/%s/%s/actions/jobs/%d", owner, repo, jobID)
+
+	var job Job
+	if err := c.get(path, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetJobLogs downloads the plain-text log of a workflow job. GitHub answers with a
+// redirect to a short-lived download URL, which is followed without the token.
+func (c *Client) GetJobLogs(owner, repo string, jobID int64) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/repos/%s/%s/actions/jobs/%d/logs", c.BaseURL, owner, repo, jobID)
+
+	resp, err := c.Retry.Do(c.HTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(resp.Header)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if rateLimitErr := rateLimitError(resp); rateLimitErr != nil {
+			return nil, rateLimitErr
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// GetWorkflowRuns fetches the workflow runs triggered for a commit, across all pages.
+func (c *Client) GetWorkflowRuns(owner, repo, sha string) ([]WorkflowRun, error) {
+	var runs []WorkflowRun
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs?head_sha=%s&per_page=%d&page=%d", owner, repo, url.QueryEscape(sha), listPageSize, page)
+
+		var resp struct {
+			TotalCount   int           `json:"total_count"`
+			WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+		}
+		if err := c.get(path, &resp); err != nil {
+			return nil, err
+		}
+		runs = append(runs, resp.WorkflowRuns...)
+
+		if len(resp.WorkflowRuns) < listPageSize || len(runs) >= resp.TotalCount {
+			break
+		}
+	}
+
+	return runs, nil
+}
+
+// RerunWorkflow re-runs a workflow run, either completely or only its failed jobs
+// and the jobs depending on them.
+func (c *Client) RerunWorkflow(owner, repo string, runID int64, failedOnly bool) error {
+	endpoint := "rerun"
+	if failedOnly {
+		endpoint = "rerun-failed-jobs"
+	}
+	return c.post(fmt.Sprintf("/repos/%s/%s/actions/runs/%d/%s", owner, repo, runID, endpoint))
+}
+
+// RerunJob re-runs a single workflow job.
+func (c *Client) RerunJob(owner, repo string, jobID int64) error {
+	return c.post(fmt.Sprintf("/repos/%s/%s/actions/jobs/%d/rerun", owner, repo, jobID))
+}
+
+// logTimestamp matches the timestamp GitHub Actions prefixes every log line with.
+var logTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// LogExcerpt returns up to n lines of a job log that explain the failure: the
+// lines leading up to the last "##[error]" annotation, or the end of the log if
+// there is none. Timestamps are stripped.
+func LogExcerpt(log []byte, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	var lines []string
+	lastError := -1
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := logTimestamp.ReplaceAllString(strings.TrimRight(scanner.Text(), "\r"), "")
+		if strings.Contains(line, "##[error]") {
+			lastError = len(lines)
+		}
+		lines = append(lines, line)
+	}
+
+	end := len(lines)
+	if lastError >= 0 {
+		end = lastError + 1
+	}
+	start := end - n
+	if start < 0 {
+		start = 0
+	}
+	return lines[start:end]
+}
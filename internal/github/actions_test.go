@@ -0,0 +1,187 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/devblac/prw/internal/retry"
+)
+
+func TestLogExcerpt(t *testing.T) {
+	log := []byte("2025-01-15T10:00:00.1234567Z ##[group]Run go test ./...\n" +
+		"2025-01-15T10:00:01.0000000Z --- FAIL: TestThing (0.00s)\n" +
+		"2025-01-15T10:00:01.0000000Z     thing_test.go:12: boom\r\n" +
+		"2025-01-15T10:00:02.0000000Z ##[error]Process completed with exit code 1.\n" +
+		"2025-01-15T10:00:03.0000000Z Post job cleanup.\n")
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{2, []string{"    thing_test.go:12: boom", "##[error]Process completed with exit code 1."}},
+		{10, []string{"##[group]Run go test ./...", "--- FAIL: TestThing (0.00s)", "    thing_test.go:12: boom", "##[error]Process completed with exit code 1."}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := LogExcerpt(log, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("LogExcerpt(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+
+	// Without an error annotation the end of the log is used.
+	if got := LogExcerpt([]byte("a\nb\nc\n"), 2); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("LogExcerpt without errors = %q", got)
+	}
+}
+
+func TestJobFailedStep(t *testing.T) {
+	job := &Job{Steps: []JobStep{
+		{Name: "Checkout", Conclusion: "success"},
+		{Name: "Test", Conclusion: "failure"},
+		{Name: "Upload", Conclusion: "skipped"},
+	}}
+	if got := job.FailedStep(); got != "Test" {
+		t.Errorf("FailedStep() = %q, want Test", got)
+	}
+	if got := (&Job{}).FailedStep(); got != "" {
+		t.Errorf("FailedStep() without steps = %q", got)
+	}
+}
+
+func TestGetJobLogsFollowsRedirect(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/jobs/42/logs":
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				t.Errorf("expected the token on the API request")
+			}
+			http.Redirect(w, r, server.URL+"/download/42", http.StatusFound)
+		case "/download/42":
+			fmt.Fprint(w, "log line\n")
+		case "/repos/owner/repo/actions/jobs/7/logs":
+			w.WriteHeader(http.StatusGone)
+			fmt.Fprint(w, `{"message": "logs expired"}`)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	log, err := client.GetJobLogs("owner", "repo", 42)
+	if err != nil {
+		t.Fatalf("GetJobLogs() error = %v", err)
+	}
+	if string(log) != "log line\n" {
+		t.Errorf("GetJobLogs() = %q", log)
+	}
+
+	if _, err := client.GetJobLogs("owner", "repo", 7); err == nil {
+		t.Error("expected an error for expired logs")
+	}
+}
+
+func TestWorkflowRunsAndReruns(t *testing.T) {
+	var posts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts = append(posts, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if r.URL.Path != "/repos/owner/repo/actions/runs" || r.URL.Query().Get("head_sha") != "abc123" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"total_count": 3, "workflow_runs": [
+			{"id": 1, "name": "CI", "status": "completed", "conclusion": "failure", "run_attempt": 1},
+			{"id": 2, "name": "Lint", "status": "completed", "conclusion": "success", "run_attempt": 1},
+			{"id": 3, "name": "Deploy", "status": "in_progress", "conclusion": null, "run_attempt": 2}
+		]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	runs, err := client.GetWorkflowRuns("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() error = %v", err)
+	}
+	var failed []string
+	for _, run := range runs {
+		if run.Failed() {
+			failed = append(failed, run.Name)
+		}
+	}
+	if len(runs) != 3 || !reflect.DeepEqual(failed, []string{"CI"}) {
+		t.Errorf("GetWorkflowRuns() = %+v, failed %v", runs, failed)
+	}
+
+	if err := client.RerunWorkflow("owner", "repo", 1, false); err != nil {
+		t.Fatalf("RerunWorkflow() error = %v", err)
+	}
+	if err := client.RerunWorkflow("owner", "repo", 1, true); err != nil {
+		t.Fatalf("RerunWorkflow(failedOnly) error = %v", err)
+	}
+	if err := client.RerunJob("owner", "repo", 9); err != nil {
+		t.Fatalf("RerunJob() error = %v", err)
+	}
+	want := []string{
+		"/repos/owner/repo/actions/runs/1/rerun",
+		"/repos/owner/repo/actions/runs/1/rerun-failed-jobs",
+		"/repos/owner/repo/actions/jobs/9/rerun",
+	}
+	if !reflect.DeepEqual(posts, want) {
+		t.Errorf("rerun requests = %v, want %v", posts, want)
+	}
+}
+
+func TestGetWorkflowRunsPaginates(t *testing.T) {
+	const total = 120
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"total_count": %d, "workflow_runs": [%s]}`, total, pagedItems(t, r, total, func(i int) string {
+			return fmt.Sprintf(`{"id": %d, "name": "run-%d", "status": "completed", "conclusion": "success"}`, i, i)
+		}))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	runs, err := client.GetWorkflowRuns("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() error = %v", err)
+	}
+	if len(runs) != total || runs[total-1].Name != "run-119" || requests != 2 {
+		t.Errorf("expected %d workflow runs from 2 pages, got %d from %d requests", total, len(runs), requests)
+	}
+}
+
+func TestRerunJobNotRetriedOnServerError(t *testing.T) {
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+	client.Retry = retry.Policy{MaxAttempts: 3}
+
+	if err := client.RerunJob("owner", "repo", 9); err == nil {
+		t.Fatal("expected an error for a 502 response")
+	}
+	if posts != 1 {
+		t.Errorf("expected the rerun to be requested once, got %d requests", posts)
+	}
+}
@@ -0,0 +1,124 @@
+package github
+
+// Check sources.
+const (
+	CheckSourceStatus   = "status"
+	CheckSourceCheckRun = "check_run"
+)
+
+// Check is a normalized view of a single commit status or check run.
+type Check struct {
+	Name   string `json:"name"`
+	Source string `json:"source"` // status or check_run
+	State  string `json:"state"`  // pending, success, failure, error
+	URL    string `json:"url,omitempty"`
+}
+
+// State maps the check run status and conclusion onto the commit status vocabulary
+// (pending, success, failure, error).
+func (r CheckRun) State() string {
+	if NormalizeState(r.Status) != "completed" {
+		return "pending"
+	}
+	return conclusionState(r.Conclusion)
+}
+
+// State maps the check suite status and conclusion onto the commit status vocabulary.
+func (s CheckSuite) State() string {
+	if NormalizeState(s.Status) != "completed" {
+		return "pending"
+	}
+	return conclusionState(s.Conclusion)
+}
+
+func conclusionState(conclusion string) string {
+	switch NormalizeState(conclusion) {
+	case "success", "neutral", "skipped":
+		return "success"
+	case "failure", "timed_out", "action_required", "startup_failure":
+		return "failure"
+	case "cancelled", "stale":
+		return "error"
+	default:
+		return "pending"
+	}
+}
+
+// CollectChecks returns the per-check breakdown for a commit, statuses first.
+func CollectChecks(status *CombinedStatus, runs []CheckRun) []Check {
+	var checks []Check
+	if status != nil {
+		for _, s := range status.Statuses {
+			checks = append(checks, Check{
+				Name:   s.Context,
+				Source: CheckSourceStatus,
+				State:  NormalizeState(s.State),
+				URL:    s.TargetURL,
+			})
+		}
+	}
+	for _, r := range runs {
+		url := r.HTMLURL
+		if url == "" {
+			url = r.DetailsURL
+		}
+		checks = append(checks, Check{
+			Name:   r.Name,
+			Source: CheckSourceCheckRun,
+			State:  r.State(),
+			URL:    url,
+		})
+	}
+	return checks
+}
+
+// AggregateState combines legacy commit statuses, check suites, and check runs into
+// a single state. Any failure wins, then error, then pending; success requires every
+// reported check to have succeeded.
+func AggregateState(status *CombinedStatus, suites []CheckSuite, runs []CheckRun) string {
+	var states []string
+
+	if status != nil {
+		state := NormalizeState(status.State)
+		// GitHub reports "pending" for commits without any legacy statuses. That is
+		// meaningless when the commit reports through the Checks API instead.
+		noStatuses := status.TotalCount == 0 && len(status.Statuses) == 0
+		if state != "" && !(noStatuses && state == "pending" && (len(runs) > 0 || len(suites) > 0)) {
+			states = append(states, state)
+		}
+	}
+	for _, s := range suites {
+		// Suites without runs are created for every installed app and may stay
+		// queued forever, so only suites that actually ran something count.
+		if s.LatestCheckRunsCount > 0 {
+			states = append(states, s.State())
+		}
+	}
+	for _, r := range runs {
+		states = append(states, r.State())
+	}
+
+	if len(states) == 0 {
+		if status != nil {
+			return NormalizeState(status.State)
+		}
+		return "pending"
+	}
+
+	counts := make(map[string]int)
+	for _, s := range states {
+		counts[s]++
+	}
+	switch {
+	case counts["failure"] > 0:
+		return "failure"
+	case counts["error"] > 0:
+		return "error"
+	case counts["pending"] > 0:
+		return "pending"
+	case counts["success"] == len(states):
+		return "success"
+	default:
+		return "pending"
+	}
+}
@@ -0,0 +1,310 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCheckRunState(t *testing.T) {
+	tests := []struct {
+		status     string
+		conclusion string
+		expected   string
+	}{
+		{"queued", "", "pending"},
+		{"in_progress", "", "pending"},
+		{"completed", "success", "success"},
+		{"completed", "neutral", "success"},
+		{"completed", "skipped", "success"},
+		{"completed", "failure", "failure"},
+		{"completed", "timed_out", "failure"},
+		{"completed", "action_required", "failure"},
+		{"completed", "cancelled", "error"},
+		{"completed", "", "pending"},
+	}
+
+	for _, tt := range tests {
+		run := CheckRun{Status: tt.status, Conclusion: tt.conclusion}
+		if got := run.State(); got != tt.expected {
+			t.Errorf("CheckRun{%q, %q}.State() = %q, want %q", tt.status, tt.conclusion, got, tt.expected)
+		}
+	}
+}
+
+func TestAggregateState(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *CombinedStatus
+		suites   []CheckSuite
+		runs     []CheckRun
+		expected string
+	}{
+		{
+			name:     "legacy status only",
+			status:   &CombinedStatus{State: "success"},
+			expected: "success",
+		},
+		{
+			name:     "no checks at all",
+			status:   &CombinedStatus{State: "pending"},
+			expected: "pending",
+		},
+		{
+			name:   "check runs only ignore empty legacy pending",
+			status: &CombinedStatus{State: "pending"},
+			runs: []CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "success"},
+				{Name: "test", Status: "completed", Conclusion: "skipped"},
+			},
+			expected: "success",
+		},
+		{
+			name:   "legacy pending with real statuses still counts",
+			status: &CombinedStatus{State: "pending", TotalCount: 1, Statuses: []Status{{Context: "ci", State: "pending"}}},
+			runs: []CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "success"},
+			},
+			expected: "pending",
+		},
+		{
+			name:   "failure wins over pending",
+			status: &CombinedStatus{State: "success", TotalCount: 1},
+			runs: []CheckRun{
+				{Name: "build", Status: "in_progress"},
+				{Name: "test", Status: "completed", Conclusion: "failure"},
+			},
+			expected: "failure",
+		},
+		{
+			name:   "cancelled run is an error",
+			status: &CombinedStatus{State: "pending"},
+			runs: []CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "cancelled"},
+			},
+			expected: "error",
+		},
+		{
+			name:   "suites without runs are ignored",
+			status: &CombinedStatus{State: "pending"},
+			suites: []CheckSuite{
+				{Status: "queued"},
+				{Status: "completed", Conclusion: "success", LatestCheckRunsCount: 1},
+			},
+			runs: []CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "success"},
+			},
+			expected: "success",
+		},
+		{
+			name:   "rerequested suite is pending",
+			status: &CombinedStatus{State: "pending"},
+			suites: []CheckSuite{
+				{Status: "queued", LatestCheckRunsCount: 1},
+			},
+			runs: []CheckRun{
+				{Name: "build", Status: "completed", Conclusion: "failure"},
+			},
+			expected: "failure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AggregateState(tt.status, tt.suites, tt.runs); got != tt.expected {
+				t.Errorf("AggregateState() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollectChecks(t *testing.T) {
+	status := &CombinedStatus{
+		State:      "success",
+		TotalCount: 1,
+		Statuses: []Status{
+			{Context: "ci/jenkins", State: "SUCCESS", TargetURL: "https://ci.example.com/1"},
+		},
+	}
+	runs := []CheckRun{
+		{Name: "test", Status: "completed", Conclusion: "failure", HTMLURL: "https://github.com/owner/repo/runs/1"},
+		{Name: "deploy", Status: "queued", DetailsURL: "https://deploy.example.com"},
+	}
+
+	checks := CollectChecks(status, runs)
+	if len(checks) != 3 {
+		t.Fatalf("expected 3 checks, got %d", len(checks))
+	}
+
+	expected := []Check{
+		{Name: "ci/jenkins", Source: CheckSourceStatus, State: "success", URL: "https://ci.example.com/1"},
+		{Name: "test", Source: CheckSourceCheckRun, State: "failure", URL: "https://github.com/owner/repo/runs/1"},
+		{Name: "deploy", Source: CheckSourceCheckRun, State: "pending", URL: "https://deploy.example.com"},
+	}
+	for i, want := range expected {
+		if checks[i] != want {
+			t.Errorf("check %d = %+v, want %+v", i, checks[i], want)
+		}
+	}
+}
+
+func TestGetCheckRuns(t *testing.T) {
+	var capturedPath string
+	client := &Client{
+		BaseURL: "https://api.github.com",
+		Token:   "test-token",
+		HTTPClient: &http.Client{
+			Transport: &mockRoundTripperFunc{
+				fn: func(req *http.Request) (*http.Response, error) {
+					capturedPath = req.URL.Path
+					body := `{"total_count": 1, "check_runs": [{"id": 7, "name": "build", "status": "completed", "conclusion": "success", "check_suite": {"id": 3}}]}`
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			},
+		},
+	}
+
+	runs, err := client.GetCheckRuns("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if capturedPath != "/repos/owner/repo/commits/abc123/check-runs" {
+		t.Errorf("unexpected request path: %s", capturedPath)
+	}
+	if runs.TotalCount != 1 || len(runs.CheckRuns) != 1 {
+		t.Fatalf("unexpected check runs: %+v", runs)
+	}
+	run := runs.CheckRuns[0]
+	if run.ID != 7 || run.Name != "build" || run.CheckSuite.ID != 3 || run.State() != "success" {
+		t.Errorf("unexpected check run: %+v", run)
+	}
+}
+
+func TestGetCheckSuites(t *testing.T) {
+	client := &Client{
+		BaseURL: "https://api.github.com",
+		Token:   "test-token",
+		HTTPClient: &http.Client{
+			Transport: &mockRoundTripperFunc{
+				fn: func(req *http.Request) (*http.Response, error) {
+					if req.URL.Path != "/repos/owner/repo/commits/abc123/check-suites" {
+						t.Errorf("unexpected request path: %s", req.URL.Path)
+					}
+					body := `{"total_count": 1, "check_suites": [{"id": 3, "status": "queued", "latest_check_runs_count": 2, "app": {"slug": "github-actions"}}]}`
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			},
+		},
+	}
+
+	suites, err := client.GetCheckSuites("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suites.CheckSuites) != 1 {
+		t.Fatalf("expected 1 suite, got %d", len(suites.CheckSuites))
+	}
+	suite := suites.CheckSuites[0]
+	if suite.App.Slug != "github-actions" || suite.LatestCheckRunsCount != 2 || suite.State() != "pending" {
+		t.Errorf("unexpected check suite: %+v", suite)
+	}
+}
+
+// pagedItems joins the items of the requested page out of total, the way
+// GitHub pages list endpoints with per_page and page.
+func pagedItems(t *testing.T, req *http.Request, total int, item func(i int) string) string {
+	t.Helper()
+	perPage, _ := strconv.Atoi(req.URL.Query().Get("per_page"))
+	page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	if perPage <= 0 || page <= 0 {
+		t.Errorf("expected per_page and page parameters, got %q", req.URL.RawQuery)
+		return ""
+	}
+	var items []string
+	for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+		items = append(items, item(i))
+	}
+	return strings.Join(items, ",")
+}
+
+func TestGetChecksPaginates(t *testing.T) {
+	const total = 230
+	var requests int
+	client := &Client{
+		BaseURL: "https://api.github.com",
+		Token:   "test-token",
+		HTTPClient: &http.Client{
+			Transport: &mockRoundTripperFunc{
+				fn: func(req *http.Request) (*http.Response, error) {
+					requests++
+					var body string
+					if strings.HasSuffix(req.URL.Path, "/check-runs") {
+						body = fmt.Sprintf(`{"total_count": %d, "check_runs": [%s]}`, total, pagedItems(t, req, total, func(i int) string {
+							return fmt.Sprintf(`{"id": %d, "name": "check-%d", "status": "completed", "conclusion": "success"}`, i, i)
+						}))
+					} else {
+						body = fmt.Sprintf(`{"total_count": %d, "check_suites": [%s]}`, total, pagedItems(t, req, total, func(i int) string {
+							return fmt.Sprintf(`{"id": %d, "status": "completed", "conclusion": "success"}`, i)
+						}))
+					}
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			},
+		},
+	}
+
+	runs, err := client.GetCheckRuns("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runs.CheckRuns) != total || runs.CheckRuns[total-1].Name != "check-229" || requests != 3 {
+		t.Errorf("expected %d check runs from 3 pages, got %d from %d requests", total, len(runs.CheckRuns), requests)
+	}
+
+	requests = 0
+	suites, err := client.GetCheckSuites("owner", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(suites.CheckSuites) != total || suites.CheckSuites[total-1].ID != 229 || requests != 3 {
+		t.Errorf("expected %d check suites from 3 pages, got %d from %d requests", total, len(suites.CheckSuites), requests)
+	}
+}
+
+func TestGetCheckRuns_ErrorResponse(t *testing.T) {
+	client := &Client{
+		BaseURL: "https://api.github.com",
+		Token:   "test-token",
+		HTTPClient: &http.Client{
+			Transport: &mockRoundTripperFunc{
+				fn: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusForbidden,
+						Body:       io.NopCloser(strings.NewReader(`{"message": "Resource not accessible by integration"}`)),
+						Header:     make(http.Header),
+					}, nil
+				},
+			},
+		},
+	}
+
+	_, err := client.GetCheckRuns("owner", "repo", "abc123")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected 403 error, got %v", err)
+	}
+}
@@ -2,21 +2,43 @@ package github
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/devblac/prw/internal/retry"
 )
 
+// maxCachedResponses bounds the number of ETag-cached responses kept in memory.
+const maxCachedResponses = 500
+
+// listPageSize is the number of items requested per page of list endpoints, the API maximum.
+const listPageSize = 100
+
 // Client is a simple GitHub API client.
+// GET responses are cached by ETag so unchanged resources are revalidated with
+// conditional requests, which do not count against the rate limit.
 type Client struct {
 	BaseURL    string
 	Token      string
 	HTTPClient *http.Client
+	Retry      retry.Policy
+
+	mu              sync.Mutex
+	cache           map[string]cachedResponse
+	rateLimit       RateLimit
+	searchRateLimit RateLimit
+}
+
+// cachedResponse is a response body stored with the ETag it was served with.
+type cachedResponse struct {
+	etag string
+	body []byte
 }
 
 // NewClient creates a new GitHub client with a 15-second timeout.
@@ -27,110 +49,355 @@ func NewClient(token string) *Client {
 		HTTPClient: &http.Client{
 			Timeout: 15 * time.Second,
 		},
+		Retry: retry.DefaultPolicy(),
 	}
 }
 
 // PullRequest represents a GitHub pull request.
 type PullRequest struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	Head   struct {
+	Number   int        `json:"number"`
+	Title    string     `json:"title"`
+	State    string     `json:"state"` // open, closed
+	Merged   bool       `json:"merged"`
+	MergedAt *time.Time `json:"merged_at"`
+	ClosedAt *time.Time `json:"closed_at"`
+	Draft    bool       `json:"draft"`
+	User     User       `json:"user"` // the author
+	Labels   []Label    `json:"labels"`
+	Head     struct {
 		SHA string `json:"sha"`
 	} `json:"head"`
+	RequestedReviewers []User `json:"requested_reviewers"`
+	RequestedTeams     []Team `json:"requested_teams"`
+
+	// Mergeable is null while GitHub computes mergeability in the background.
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+}
+
+// Values of PullRequest.MergeableState.
+const (
+	MergeableStateClean    = "clean"
+	MergeableStateBehind   = "behind"
+	MergeableStateBlocked  = "blocked"
+	MergeableStateDirty    = "dirty"
+	MergeableStateUnstable = "unstable"
+	MergeableStateDraft    = "draft"
+	MergeableStateUnknown  = "unknown"
+)
+
+// Label is a label on a pull request.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// LabelNames returns the names of the pull request's labels.
+func (pr *PullRequest) LabelNames() []string {
+	var names []string
+	for _, label := range pr.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// MergeabilityKnown reports whether GitHub has finished computing mergeability.
+func (pr *PullRequest) MergeabilityKnown() bool {
+	return pr.Mergeable != nil && NormalizeState(pr.MergeableState) != MergeableStateUnknown
+}
+
+// Pull request lifecycle states as reported by Lifecycle.
+const (
+	PRStateOpen   = "open"
+	PRStateClosed = "closed"
+	PRStateMerged = "merged"
+)
+
+// Lifecycle reports whether the pull request is open, merged, or closed without merging.
+func (pr *PullRequest) Lifecycle() string {
+	switch {
+	case pr.Merged || pr.MergedAt != nil:
+		return PRStateMerged
+	case NormalizeState(pr.State) == "closed":
+		return PRStateClosed
+	default:
+		return PRStateOpen
+	}
 }
 
 // CombinedStatus represents the combined CI status for a commit.
 type CombinedStatus struct {
-	State string `json:"state"` // pending, success, failure, error
-	SHA   string `json:"sha"`
+	State      string   `json:"state"` // pending, success, failure, error
+	SHA        string   `json:"sha"`
+	TotalCount int      `json:"total_count"`
+	Statuses   []Status `json:"statuses"`
+}
+
+// Status is a single commit status reported through the legacy statuses API.
+type Status struct {
+	Context     string `json:"context"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
 }
 
-// ParsePRURL extracts owner, repo, and PR number from a GitHub PR URL.
+// CheckRun represents a single check run, such as a GitHub Actions job.
+type CheckRun struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`     // queued, in_progress, completed
+	Conclusion  string     `json:"conclusion"` // success, failure, neutral, cancelled, skipped, timed_out, action_required, stale
+	HTMLURL     string     `json:"html_url"`
+	DetailsURL  string     `json:"details_url"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CheckSuite  struct {
+		ID int64 `json:"id"`
+	} `json:"check_suite"`
+	App struct {
+		Slug string `json:"slug"`
+	} `json:"app"`
+}
+
+// CheckRunList is the response of the check runs endpoint.
+type CheckRunList struct {
+	TotalCount int        `json:"total_count"`
+	CheckRuns  []CheckRun `json:"check_runs"`
+}
+
+// CheckSuite represents a check suite, the group of check runs created by one app for a commit.
+type CheckSuite struct {
+	ID                   int64  `json:"id"`
+	Status               string `json:"status"` // queued, in_progress, completed
+	Conclusion           string `json:"conclusion"`
+	LatestCheckRunsCount int    `json:"latest_check_runs_count"`
+	App                  struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+	} `json:"app"`
+}
+
+// CheckSuiteList is the response of the check suites endpoint.
+type CheckSuiteList struct {
+	TotalCount  int          `json:"total_count"`
+	CheckSuites []CheckSuite `json:"check_suites"`
+}
+
+// ParsePRURL extracts owner, repo, and PR number from a github.com PR URL.
+// Use ParseHostPRURL to also accept GitHub Enterprise Server URLs.
 func ParsePRURL(prURL string) (owner, repo string, number int, err error) {
 	// Match patterns like:
 	// https://github.com/owner/repo/pull/123
 	// github.com/owner/repo/pull/123
-	re := regexp.MustCompile(`(?:https?://)?github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
-	matches := re.FindStringSubmatch(prURL)
-	if len(matches) != 4 {
-		return "", "", 0, fmt.Errorf("invalid GitHub PR URL format")
-	}
-
-	number, err = strconv.Atoi(matches[3])
+	host, owner, repo, number, err := ParseHostPRURL(prURL)
 	if err != nil {
-		return "", "", 0, fmt.Errorf("invalid PR number: %w", err)
+		return "", "", 0, err
+	}
+	if host != DefaultHost {
+		return "", "", 0, fmt.Errorf("invalid GitHub PR URL format")
 	}
 
-	return matches[1], matches[2], number, nil
+	return owner, repo, number, nil
 }
 
 // GetPullRequest fetches a pull request by owner, repo, and PR number.
 func (c *Client) GetPullRequest(owner, repo string, number int) (*PullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number)
-	url := c.BaseURL + path
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
+	var pr PullRequest
+	if err := c.get(path, &pr); err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	return &pr, nil
+}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+// GetCombinedStatus fetches the combined CI status for a commit.
+func (c *Client) GetCombinedStatus(owner, repo, ref string) (*CombinedStatus, error) {
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/status", owner, repo, url.PathEscape(ref))
+
+	var status CombinedStatus
+	if err := c.get(path, &status); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
+	return &status, nil
+}
+
+// GetCheckRuns fetches the check runs reported for a commit through the Checks API.
+// Only the latest run for each check name is returned, across all pages.
+func (c *Client) GetCheckRuns(owner, repo, ref string) (*CheckRunList, error) {
+	var runs CheckRunList
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs?filter=latest&per_page=%d&page=%d", owner, repo, url.PathEscape(ref), listPageSize, page)
+
+		var result CheckRunList
+		if err := c.get(path, &result); err != nil {
+			return nil, err
+		}
+		runs.TotalCount = result.TotalCount
+		runs.CheckRuns = append(runs.CheckRuns, result.CheckRuns...)
+
+		if len(result.CheckRuns) < listPageSize || len(runs.CheckRuns) >= result.TotalCount {
+			break
+		}
 	}
 
-	var pr PullRequest
-	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return &runs, nil
+}
+
+// GetCheckSuites fetches the check suites created for a commit, across all pages.
+func (c *Client) GetCheckSuites(owner, repo, ref string) (*CheckSuiteList, error) {
+	var suites CheckSuiteList
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/%s/commits/%s/check-suites?per_page=%d&page=%d", owner, repo, url.PathEscape(ref), listPageSize, page)
+
+		var result CheckSuiteList
+		if err := c.get(path, &result); err != nil {
+			return nil, err
+		}
+		suites.TotalCount = result.TotalCount
+		suites.CheckSuites = append(suites.CheckSuites, result.CheckSuites...)
+
+		if len(result.CheckSuites) < listPageSize || len(suites.CheckSuites) >= result.TotalCount {
+			break
+		}
 	}
 
-	return &pr, nil
+	return &suites, nil
 }
 
-// GetCombinedStatus fetches the combined CI status for a commit.
-func (c *Client) GetCombinedStatus(owner, repo, ref string) (*CombinedStatus, error) {
-	path := fmt.Sprintf("/repos/%s/%s/commits/%s/status", owner, repo, url.PathEscape(ref))
-	url := c.BaseURL + path
+// APIError is returned when GitHub answers a request with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API returned %d: %s", e.StatusCode, e.Body)
+}
+
+// IsPermanent reports whether err is a client error that repeating the request
+// won't fix, such as a bad token (401), missing permissions (403), or a deleted
+// PR (404). Rate limits and server errors are not permanent.
+func IsPermanent(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
 	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// get performs an authenticated GET request and decodes the JSON response into v.
+// Cached ETags are sent as If-None-Match and a 304 response reuses the cached body.
+func (c *Client) get(path string, v interface{}) error {
+	reqURL := c.BaseURL + path
+	cached, hasCached := c.cachedResponse(reqURL)
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := c.Retry.Do(c.HTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := c.HTTPClient.Do(req)
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		if hasCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	c.recordRateLimit(resp.Header)
+
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		body = cached.body
+	case resp.StatusCode == http.StatusOK:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.storeResponse(reqURL, cachedResponse{etag: etag, body: body})
+		}
+	default:
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, string(body))
+		if rateLimitErr := rateLimitError(resp); rateLimitErr != nil {
+			return rateLimitErr
+		}
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var status CombinedStatus
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &status, nil
+	return nil
+}
+
+// post performs an authenticated POST request without a body and discards the response.
+// POSTs trigger actions, so they are only retried when GitHub never received them.
+func (c *Client) post(path string) error {
+	reqURL := c.BaseURL + path
+
+	resp, err := c.Retry.DoNonIdempotent(c.HTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		if rateLimitErr := rateLimitError(resp); rateLimitErr != nil {
+			return rateLimitErr
+		}
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
+
+func (c *Client) cachedResponse(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.cache[key]
+	return cached, ok
+}
+
+func (c *Client) storeResponse(key string, resp cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache == nil {
+		c.cache = make(map[string]cachedResponse)
+	}
+	if _, ok := c.cache[key]; !ok && len(c.cache) >= maxCachedResponses {
+		// Evict an arbitrary entry; old commit SHAs are never requested again.
+		for k := range c.cache {
+			delete(c.cache, k)
+			break
+		}
+	}
+	c.cache[key] = resp
 }
 
 // FormatPRURL constructs a GitHub PR URL.
 func FormatPRURL(owner, repo string, number int) string {
-	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repo, number)
+	return FormatHostPRURL(DefaultHost, owner, repo, number)
 }
 
 // NormalizeState normalizes status state strings.
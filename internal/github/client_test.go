@@ -1,12 +1,17 @@
 package github
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/devblac/prw/internal/retry"
 )
 
 // mockRoundTripper implements http.RoundTripper for testing.
@@ -547,3 +552,126 @@ func TestGetCombinedStatus_EmptyState(t *testing.T) {
 		t.Errorf("expected empty state, got %q", status.State)
 	}
 }
+
+func TestGetPullRequest_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseBody string
+		wantState    string
+		wantDraft    bool
+	}{
+		{
+			name:         "open draft",
+			responseBody: `{"number": 1, "state": "open", "draft": true, "merged": false, "head": {"sha": "abc"}}`,
+			wantState:    PRStateOpen,
+			wantDraft:    true,
+		},
+		{
+			name:         "merged",
+			responseBody: `{"number": 1, "state": "closed", "merged": true, "merged_at": "2025-01-15T10:30:00Z", "closed_at": "2025-01-15T10:30:00Z", "head": {"sha": "abc"}}`,
+			wantState:    PRStateMerged,
+		},
+		{
+			name:         "closed without merge",
+			responseBody: `{"number": 1, "state": "closed", "merged": false, "merged_at": null, "closed_at": "2025-01-15T10:30:00Z", "head": {"sha": "abc"}}`,
+			wantState:    PRStateClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{
+				BaseURL: "https://api.github.com",
+				Token:   "test-token",
+				HTTPClient: &http.Client{
+					Transport: &mockRoundTripperFunc{
+						fn: func(req *http.Request) (*http.Response, error) {
+							return &http.Response{
+								StatusCode: http.StatusOK,
+								Body:       io.NopCloser(strings.NewReader(tt.responseBody)),
+								Header:     make(http.Header),
+							}, nil
+						},
+					},
+				},
+			}
+
+			pr, err := client.GetPullRequest("owner", "repo", 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := pr.Lifecycle(); got != tt.wantState {
+				t.Errorf("expected lifecycle %q, got %q", tt.wantState, got)
+			}
+			if pr.Draft != tt.wantDraft {
+				t.Errorf("expected draft %v, got %v", tt.wantDraft, pr.Draft)
+			}
+			if tt.wantState != PRStateOpen && pr.ClosedAt == nil {
+				t.Error("expected closed_at to be decoded")
+			}
+		})
+	}
+}
+
+func TestGetPullRequest_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"number": 7, "title": "Retry me", "state": "open", "head": {"sha": "abc"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+	client.Retry = retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	pr, err := client.GetPullRequest("owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if pr.Title != "Retry me" {
+		t.Errorf("unexpected title %q", pr.Title)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestGetPullRequest_RetriesExhausted(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "maintenance")
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+	client.Retry = retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+
+	_, err := client.GetPullRequest("owner", "repo", 7)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected 503 error after retries, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestPullRequestAuthorAndLabels(t *testing.T) {
+	var pr PullRequest
+	body := `{"number": 7, "user": {"login": "octocat"}, "labels": [{"name": "bug"}, {"name": "area/api"}]}`
+	if err := json.Unmarshal([]byte(body), &pr); err != nil {
+		t.Fatalf("failed to decode PR: %v", err)
+	}
+	if pr.User.Login != "octocat" {
+		t.Errorf("expected author octocat, got %q", pr.User.Login)
+	}
+	if got := pr.LabelNames(); len(got) != 2 || got[0] != "bug" || got[1] != "area/api" {
+		t.Errorf("LabelNames() = %v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Comparison statuses reported by the compare API, describing head relative to base.
+const (
+	CompareAhead     = "ahead"
+	CompareBehind    = "behind"
+	CompareDiverged  = "diverged"
+	CompareIdentical = "identical"
+)
+
+// Comparison is the result of comparing two commits.
+type Comparison struct {
+	Status       string          `json:"status"`
+	AheadBy      int             `json:"ahead_by"`
+	BehindBy     int             `json:"behind_by"`
+	TotalCommits int             `json:"total_commits"`
+	Commits      []CompareCommit `json:"commits"`
+}
+
+// CompareCommit is one of the commits in a comparison.
+type CompareCommit struct {
+	SHA    string `json:"sha"`
+	Author *User  `json:"author"` // nil when the commit email has no GitHub account
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+// AuthorName returns the GitHub login of the commit author, or the git author
+// name when the commit is not linked to an account.
+func (c CompareCommit) AuthorName() string {
+	if c.Author != nil && c.Author.Login != "" {
+		return c.Author.Login
+	}
+	return c.Commit.Author.Name
+}
+
+// ForcePushed reports whether base is no longer an ancestor of head, i.e. the
+// branch was rewritten rather than extended.
+func (c *Comparison) ForcePushed() bool {
+	return c.Status == CompareDiverged || c.Status == CompareBehind
+}
+
+// Authors returns the distinct authors of the compared commits in commit order.
+func (c *Comparison) Authors() []string {
+	var authors []string
+	seen := make(map[string]bool)
+	for _, commit := range c.Commits {
+		name := commit.AuthorName()
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		authors = append(authors, name)
+	}
+	return authors
+}
+
+// CompareCommits compares two commits of a repository.
+func (c *Client) CompareCommits(owner, repo, base, head string) (*Comparison, error) {
+	path := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", owner, repo, url.PathEscape(base), url.PathEscape(head))
+
+	var comparison Comparison
+	if err := c.get(path, &comparison); err != nil {
+		return nil, err
+	}
+
+	return &comparison, nil
+}
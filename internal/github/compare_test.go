@@ -0,0 +1,51 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCompareCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/compare/old123...new456" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"status": "diverged", "ahead_by": 3, "behind_by": 1, "total_commits": 3, "commits": [
+			{"sha": "a", "author": {"login": "alice"}, "commit": {"author": {"name": "Alice"}}},
+			{"sha": "b", "author": null, "commit": {"author": {"name": "Bob Builder"}}},
+			{"sha": "c", "author": {"login": "alice"}, "commit": {"author": {"name": "Alice"}}}
+		]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	comparison, err := client.CompareCommits("owner", "repo", "old123", "new456")
+	if err != nil {
+		t.Fatalf("CompareCommits() error = %v", err)
+	}
+	if comparison.AheadBy != 3 || !comparison.ForcePushed() {
+		t.Errorf("unexpected comparison: %+v", comparison)
+	}
+	if got := comparison.Authors(); !reflect.DeepEqual(got, []string{"alice", "Bob Builder"}) {
+		t.Errorf("Authors() = %v", got)
+	}
+}
+
+func TestComparisonForcePushed(t *testing.T) {
+	tests := map[string]bool{
+		CompareAhead:     false,
+		CompareIdentical: false,
+		CompareBehind:    true,
+		CompareDiverged:  true,
+	}
+	for status, want := range tests {
+		if got := (&Comparison{Status: status}).ForcePushed(); got != want {
+			t.Errorf("ForcePushed() with status %s = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultHost is the hostname of github.com.
+const DefaultHost = "github.com"
+
+// prURLPattern matches PR URLs on any host, such as
+// https://github.example.com/owner/repo/pull/123 or github.com/owner/repo/pull/123.
+var prURLPattern = regexp.MustCompile(`^(?:https?://)?([A-Za-z0-9.-]+(?::\d+)?)/([^/\s]+)/([^/\s]+)/pull/(\d+)(?:[/?#].*)?$`)
+
+// NewHostClient creates a client for the GitHub host serving PR URLs on host.
+// Hosts other than github.com are treated as GitHub Enterprise Server.
+func NewHostClient(host, token string) *Client {
+	client := NewClient(token)
+	client.BaseURL = APIBaseURL(host)
+	return client
+}
+
+// APIBaseURL returns the REST API base URL for host: https://api.github.com for
+// github.com and https://<host>/api/v3 for GitHub Enterprise Server.
+func APIBaseURL(host string) string {
+	host = NormalizeHost(host)
+	if host == DefaultHost {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// NormalizeHost lowercases a hostname and strips any scheme, path, and "www."
+// prefix. An empty host means github.com.
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimPrefix(host, "www.")
+	if host == "" || host == "api.github.com" {
+		return DefaultHost
+	}
+	return host
+}
+
+// ParseHostPRURL extracts the host, owner, repo, and PR number from a PR URL on
+// github.com or a GitHub Enterprise Server instance.
+func ParseHostPRURL(prURL string) (host, owner, repo string, number int, err error) {
+	matches := prURLPattern.FindStringSubmatch(strings.TrimSpace(prURL))
+	if len(matches) != 5 {
+		return "", "", "", 0, fmt.Errorf("invalid GitHub PR URL format")
+	}
+
+	number, err = strconv.Atoi(matches[4])
+	if err != nil {
+		return "", "", "", 0, fmt.Errorf("invalid PR number: %w", err)
+	}
+
+	return NormalizeHost(matches[1]), matches[2], matches[3], number, nil
+}
+
+// FormatHostPRURL constructs the web URL of a PR on host. An empty host means github.com.
+func FormatHostPRURL(host, owner, repo string, number int) string {
+	return fmt.Sprintf("https://%s/%s/%s/pull/%d", NormalizeHost(host), owner, repo, number)
+}
@@ -0,0 +1,117 @@
+package github
+
+import "testing"
+
+func TestParseHostPRURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantHost  string
+		wantOwner string
+		wantRepo  string
+		wantNum   int
+		wantErr   bool
+	}{
+		{
+			name:      "github.com",
+			url:       "https://github.com/owner/repo/pull/123",
+			wantHost:  "github.com",
+			wantOwner: "owner",
+			wantRepo:  "repo",
+			wantNum:   123,
+		},
+		{
+			name:      "enterprise host",
+			url:       "https://GitHub.Example.com/team/service/pull/42/files",
+			wantHost:  "github.example.com",
+			wantOwner: "team",
+			wantRepo:  "service",
+			wantNum:   42,
+		},
+		{
+			name:      "enterprise host with port and no scheme",
+			url:       "ghe.internal:8443/team/service/pull/7",
+			wantHost:  "ghe.internal:8443",
+			wantOwner: "team",
+			wantRepo:  "service",
+			wantNum:   7,
+		},
+		{
+			name:      "www prefix",
+			url:       "https://www.github.com/owner/repo/pull/1",
+			wantHost:  "github.com",
+			wantOwner: "owner",
+			wantRepo:  "repo",
+			wantNum:   1,
+		},
+		{
+			name:    "missing repo",
+			url:     "https://github.example.com/team/pull/42",
+			wantErr: true,
+		},
+		{
+			name:    "issue URL",
+			url:     "https://github.example.com/team/service/issues/42",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, owner, repo, num, err := ParseHostPRURL(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host != tt.wantHost || owner != tt.wantOwner || repo != tt.wantRepo || num != tt.wantNum {
+				t.Errorf("got (%s, %s, %s, %d), want (%s, %s, %s, %d)",
+					host, owner, repo, num, tt.wantHost, tt.wantOwner, tt.wantRepo, tt.wantNum)
+			}
+		})
+	}
+}
+
+func TestParsePRURL_RejectsEnterpriseHost(t *testing.T) {
+	if _, _, _, err := ParsePRURL("https://github.example.com/team/service/pull/42"); err == nil {
+		t.Error("expected ParsePRURL to reject non-github.com hosts")
+	}
+}
+
+func TestAPIBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                            "https://api.github.com",
+		"github.com":                  "https://api.github.com",
+		"GitHub.com":                  "https://api.github.com",
+		"github.example.com":          "https://github.example.com/api/v3",
+		"https://github.example.com/": "https://github.example.com/api/v3",
+	}
+	for host, want := range tests {
+		if got := APIBaseURL(host); got != want {
+			t.Errorf("APIBaseURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestNewHostClient(t *testing.T) {
+	client := NewHostClient("github.example.com", "token")
+	if client.BaseURL != "https://github.example.com/api/v3" {
+		t.Errorf("unexpected base URL %q", client.BaseURL)
+	}
+	if client.Token != "token" {
+		t.Errorf("unexpected token %q", client.Token)
+	}
+}
+
+func TestFormatHostPRURL(t *testing.T) {
+	if got := FormatHostPRURL("", "owner", "repo", 1); got != "https://github.com/owner/repo/pull/1" {
+		t.Errorf("unexpected github.com URL %q", got)
+	}
+	if got := FormatHostPRURL("github.example.com", "team", "service", 42); got != "https://github.example.com/team/service/pull/42" {
+		t.Errorf("unexpected enterprise URL %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is a snapshot of the REST API rate limit budget.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+}
+
+// Known reports whether the snapshot was populated from a GitHub response.
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// String formats the budget for display, e.g. "4821/5000 remaining, resets at 15:04".
+func (r RateLimit) String() string {
+	if !r.Known() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d/%d remaining, resets at %s", r.Remaining, r.Limit, r.Reset.Local().Format("15:04"))
+}
+
+// RateLimitError is returned when GitHub rejects a request because the rate limit is exhausted.
+type RateLimitError struct {
+	StatusCode int
+	Reset      time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API returned %d: rate limit exceeded, resets at %s", e.StatusCode, e.Reset.Local().Format(time.RFC3339))
+}
+
+// RateLimit returns the rate limit budget reported by the most recent response.
+func (c *Client) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
+}
+
+// SearchRateLimit returns the search API budget reported by the most recent
+// search response. The search API has its own, much smaller budget.
+func (c *Client) SearchRateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.searchRateLimit
+}
+
+// GetRateLimit fetches the current core rate limit. Calls to this endpoint do not
+// count against the rate limit, so it is also a cheap way to validate a token.
+func (c *Client) GetRateLimit() (*RateLimit, error) {
+	var resp struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Used      int   `json:"used"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := c.get("/rate_limit", &resp); err != nil {
+		return nil, err
+	}
+
+	core := resp.Resources.Core
+	return &RateLimit{
+		Limit:     core.Limit,
+		Remaining: core.Remaining,
+		Used:      core.Used,
+		Reset:     time.Unix(core.Reset, 0),
+	}, nil
+}
+
+// recordRateLimit stores the budget reported in the X-RateLimit-* headers.
+// The core and search budgets are tracked; other resources are ignored.
+func (c *Client) recordRateLimit(header http.Header) {
+	resource := header.Get("X-RateLimit-Resource")
+	if resource != "" && resource != "core" && resource != "search" {
+		return
+	}
+	limit, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resource == "search" {
+		c.searchRateLimit = limit
+	} else {
+		c.rateLimit = limit
+	}
+}
+
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	used, _ := strconv.Atoi(header.Get("X-RateLimit-Used"))
+
+	return RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// rateLimitError returns a RateLimitError when a 403/429 response reports an exhausted budget.
+func rateLimitError(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	limit, ok := parseRateLimit(resp.Header)
+	if !ok || limit.Remaining > 0 {
+		return nil
+	}
+	return &RateLimitError{StatusCode: resp.StatusCode, Reset: limit.Reset}
+}
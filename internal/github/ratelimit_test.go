@@ -0,0 +1,168 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientETagCaching(t *testing.T) {
+	var requests, conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"number": 1, "title": "Cached PR", "head": {"sha": "abc"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	for i := 0; i < 3; i++ {
+		pr, err := client.GetPullRequest("owner", "repo", 1)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		if pr.Title != "Cached PR" {
+			t.Errorf("request %d: expected cached title, got %q", i, pr.Title)
+		}
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+	if conditional != 2 {
+		t.Errorf("expected 2 conditional requests, got %d", conditional)
+	}
+}
+
+func TestClientNotModifiedWithoutCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	_, err := client.GetCombinedStatus("owner", "repo", "abc")
+	if err == nil || !strings.Contains(err.Error(), "304") {
+		t.Errorf("expected 304 error without a cached response, got %v", err)
+	}
+}
+
+func TestClientRecordsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Used", "679")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		fmt.Fprint(w, `{"state": "success", "sha": "abc"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	if client.RateLimit().Known() {
+		t.Fatal("expected rate limit to be unknown before the first request")
+	}
+	if _, err := client.GetCombinedStatus("owner", "repo", "abc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	limit := client.RateLimit()
+	if limit.Limit != 5000 || limit.Remaining != 4321 || limit.Used != 679 {
+		t.Errorf("unexpected rate limit: %+v", limit)
+	}
+	if !limit.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected reset time: %v", limit.Reset)
+	}
+}
+
+func TestClientRecordsSearchRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Resource", "search")
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", "12")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		fmt.Fprint(w, `{"total_count": 0, "items": []}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	if _, err := client.SearchPullRequests("repo:owner/repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.RateLimit().Known() {
+		t.Errorf("expected the search budget not to replace the core budget, got %+v", client.RateLimit())
+	}
+	if limit := client.SearchRateLimit(); limit.Limit != 30 || limit.Remaining != 12 {
+		t.Errorf("unexpected search rate limit: %+v", limit)
+	}
+}
+
+func TestClientRateLimitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	_, err := client.GetPullRequest("owner", "repo", 1)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if !rateLimitErr.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected reset time: %v", rateLimitErr.Reset)
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Errorf("error should mention status code: %v", err)
+	}
+}
+
+func TestGetRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"resources": {"core": {"limit": 5000, "remaining": 4990, "used": 10, "reset": 1700000000}}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	limit, err := client.GetRateLimit()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit.Limit != 5000 || limit.Remaining != 4990 || limit.Used != 10 {
+		t.Errorf("unexpected rate limit: %+v", limit)
+	}
+	if !strings.Contains(limit.String(), "4990/5000 remaining") {
+		t.Errorf("unexpected rate limit string: %s", limit)
+	}
+}
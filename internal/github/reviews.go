@@ -0,0 +1,113 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Review decisions, summarizing the reviews of a pull request.
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required" // reviewers requested, none approved yet
+	ReviewNone             = "none"            // no reviews and no requested reviewers
+)
+
+// User is a GitHub account.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Team is a GitHub team that can be requested for review.
+type Team struct {
+	Slug string `json:"slug"`
+}
+
+// Review is a pull request review.
+type Review struct {
+	ID          int64      `json:"id"`
+	User        User       `json:"user"`
+	State       string     `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED, PENDING
+	SubmittedAt *time.Time `json:"submitted_at"`
+}
+
+// GetReviews fetches the reviews of a pull request in chronological order,
+// across all pages.
+func (c *Client) GetReviews(owner, repo string, number int) ([]Review, error) {
+	var reviews []Review
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews?per_page=%d&page=%d", owner, repo, number, listPageSize, page)
+
+		var result []Review
+		if err := c.get(path, &result); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, result...)
+
+		// The endpoint has no total count; a short page is the last one
+		if len(result) < listPageSize {
+			break
+		}
+	}
+
+	return reviews, nil
+}
+
+// RequestedReviewerNames returns the users and teams whose review is requested.
+// Teams are prefixed with "team:" to tell them apart from users.
+func (pr *PullRequest) RequestedReviewerNames() []string {
+	var names []string
+	for _, u := range pr.RequestedReviewers {
+		names = append(names, u.Login)
+	}
+	for _, t := range pr.RequestedTeams {
+		names = append(names, "team:"+t.Slug)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReviewDecision summarizes reviews the way GitHub does: only each reviewer's
+// latest approval or change request counts, dismissed reviews and reviewers who
+// have been asked to review again are ignored, and any change request wins over
+// approvals. requested lists the reviewers whose review is still requested.
+func ReviewDecision(reviews []Review, requested []string) string {
+	pending := make(map[string]bool, len(requested))
+	for _, name := range requested {
+		pending[name] = true
+	}
+
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		login := r.User.Login
+		switch NormalizeState(r.State) {
+		case "approved":
+			latest[login] = ReviewApproved
+		case "changes_requested":
+			latest[login] = ReviewChangesRequested
+		case "dismissed":
+			delete(latest, login)
+		}
+	}
+
+	approved := false
+	for login, state := range latest {
+		if pending[login] {
+			continue
+		}
+		if state == ReviewChangesRequested {
+			return ReviewChangesRequested
+		}
+		approved = true
+	}
+
+	switch {
+	case approved:
+		return ReviewApproved
+	case len(requested) > 0:
+		return ReviewRequired
+	default:
+		return ReviewNone
+	}
+}
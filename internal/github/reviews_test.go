@@ -0,0 +1,94 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func review(login, state string) Review {
+	return Review{User: User{Login: login}, State: state}
+}
+
+func TestReviewDecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		reviews   []Review
+		requested []string
+		want      string
+	}{
+		{"no reviews", nil, nil, ReviewNone},
+		{"only requested", nil, []string{"alice"}, ReviewRequired},
+		{"comments don't count", []Review{review("alice", "COMMENTED")}, []string{"bob"}, ReviewRequired},
+		{"approved", []Review{review("alice", "APPROVED")}, nil, ReviewApproved},
+		{"approved with others pending", []Review{review("alice", "APPROVED")}, []string{"bob"}, ReviewApproved},
+		{"changes requested wins", []Review{review("alice", "APPROVED"), review("bob", "CHANGES_REQUESTED")}, nil, ReviewChangesRequested},
+		{"latest review per user counts", []Review{review("alice", "CHANGES_REQUESTED"), review("alice", "COMMENTED"), review("alice", "APPROVED")}, nil, ReviewApproved},
+		{"dismissed review is dropped", []Review{review("alice", "CHANGES_REQUESTED"), review("alice", "DISMISSED")}, nil, ReviewNone},
+		{"re-requested reviewer is pending again", []Review{review("alice", "CHANGES_REQUESTED")}, []string{"alice"}, ReviewRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReviewDecision(tt.reviews, tt.requested); got != tt.want {
+				t.Errorf("ReviewDecision() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestedReviewerNames(t *testing.T) {
+	pr := &PullRequest{
+		RequestedReviewers: []User{{Login: "zed"}, {Login: "alice"}},
+		RequestedTeams:     []Team{{Slug: "platform"}},
+	}
+	want := []string{"alice", "team:platform", "zed"}
+	if got := pr.RequestedReviewerNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RequestedReviewerNames() = %v, want %v", got, want)
+	}
+}
+
+func TestGetReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/pulls/7/reviews" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"id": 1, "user": {"login": "alice"}, "state": "APPROVED", "submitted_at": "2025-01-15T10:00:00Z"}]`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	reviews, err := client.GetReviews("owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("GetReviews() error = %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].User.Login != "alice" || reviews[0].State != "APPROVED" || reviews[0].SubmittedAt == nil {
+		t.Errorf("GetReviews() = %+v", reviews)
+	}
+}
+
+func TestGetReviewsPaginates(t *testing.T) {
+	const total = 205
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, "[%s]", pagedItems(t, r, total, func(i int) string {
+			return fmt.Sprintf(`{"id": %d, "user": {"login": "reviewer-%d"}, "state": "COMMENTED"}`, i, i)
+		}))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	reviews, err := client.GetReviews("owner", "repo", 7)
+	if err != nil {
+		t.Fatalf("GetReviews() error = %v", err)
+	}
+	if len(reviews) != total || reviews[total-1].User.Login != "reviewer-204" || requests != 3 {
+		t.Errorf("expected %d reviews from 3 pages, got %d from %d requests", total, len(reviews), requests)
+	}
+}
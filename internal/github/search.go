@@ -0,0 +1,81 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// searchPageSize is the number of results requested per search page, the API maximum.
+const searchPageSize = 100
+
+// maxSearchResults is the number of results the search API returns for a query at most.
+const maxSearchResults = 1000
+
+// SearchIssue is a single result of the issue search endpoint.
+type SearchIssue struct {
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	HTMLURL       string    `json:"html_url"`
+	RepositoryURL string    `json:"repository_url"`
+	PullRequest   *struct{} `json:"pull_request"`
+}
+
+// SearchResult is the response of the issue search endpoint.
+type SearchResult struct {
+	TotalCount        int           `json:"total_count"`
+	IncompleteResults bool          `json:"incomplete_results"`
+	Items             []SearchIssue `json:"items"`
+}
+
+// PullRequestRef identifies a pull request found by a search.
+type PullRequestRef struct {
+	Owner  string
+	Repo   string
+	Number int
+	Title  string
+}
+
+// SearchPullRequests returns the pull requests matching a search query, e.g.
+// "is:pr is:open repo:owner/repo". Issues in the results are skipped.
+func (c *Client) SearchPullRequests(query string) ([]PullRequestRef, error) {
+	var refs []PullRequestRef
+	for page := 1; (page-1)*searchPageSize < maxSearchResults; page++ {
+		path := fmt.Sprintf("/search/issues?q=%s&per_page=%d&page=%d", url.QueryEscape(query), searchPageSize, page)
+
+		var result SearchResult
+		if err := c.get(path, &result); err != nil {
+			return nil, err
+		}
+		if result.IncompleteResults {
+			return nil, fmt.Errorf("search for %q timed out with incomplete results", query)
+		}
+
+		for _, item := range result.Items {
+			if item.PullRequest == nil {
+				continue
+			}
+			owner, repo, err := parseRepositoryURL(item.RepositoryURL)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, PullRequestRef{Owner: owner, Repo: repo, Number: item.Number, Title: item.Title})
+		}
+
+		if len(result.Items) < searchPageSize || page*searchPageSize >= result.TotalCount {
+			break
+		}
+	}
+	return refs, nil
+}
+
+// parseRepositoryURL extracts owner and repo from an API repository URL such as
+// https://api.github.com/repos/owner/repo.
+func parseRepositoryURL(repoURL string) (owner, repo string, err error) {
+	_, path, ok := strings.Cut(repoURL, "/repos/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if !ok || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected repository URL %q", repoURL)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchPullRequests(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if q := r.URL.Query().Get("q"); q != "is:pr is:open repo:owner/repo" {
+			t.Errorf("unexpected query: %q", q)
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		w.Header().Set("X-RateLimit-Resource", "search")
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", "29")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+
+		if page == "1" {
+			// A full page, so the client asks for the next one.
+			items := make([]string, 0, searchPageSize)
+			items = append(items, `{"number": 1, "title": "First", "repository_url": "https://api.github.com/repos/owner/repo", "pull_request": {}}`)
+			items = append(items, `{"number": 2, "title": "An issue", "repository_url": "https://api.github.com/repos/owner/repo"}`)
+			for i := len(items); i < searchPageSize; i++ {
+				items = append(items, fmt.Sprintf(`{"number": %d, "repository_url": "https://api.github.com/repos/owner/repo", "pull_request": {}}`, 100+i))
+			}
+			fmt.Fprintf(w, `{"total_count": 101, "items": [%s]}`, strings.Join(items, ","))
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 101, "items": [{"number": 3, "title": "Last", "repository_url": "https://ghe.example.com/api/v3/repos/Other/tool", "pull_request": {}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	refs, err := client.SearchPullRequests("is:pr is:open repo:owner/repo")
+	if err != nil {
+		t.Fatalf("SearchPullRequests() error = %v", err)
+	}
+	if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+		t.Errorf("requested pages %v, want [1 2]", pages)
+	}
+	if len(refs) != searchPageSize {
+		t.Fatalf("expected %d pull requests (issues skipped), got %d", searchPageSize, len(refs))
+	}
+	if refs[0] != (PullRequestRef{Owner: "owner", Repo: "repo", Number: 1, Title: "First"}) {
+		t.Errorf("first result = %+v", refs[0])
+	}
+	if last := refs[len(refs)-1]; last != (PullRequestRef{Owner: "Other", Repo: "tool", Number: 3, Title: "Last"}) {
+		t.Errorf("last result = %+v", last)
+	}
+	if client.RateLimit().Known() {
+		t.Error("expected the search budget not to be recorded as the core rate limit")
+	}
+}
+
+func TestSearchPullRequestsIncomplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count": 5, "incomplete_results": true, "items": []}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	if _, err := client.SearchPullRequests("is:pr"); err == nil {
+		t.Error("expected incomplete results to be reported as an error")
+	}
+}
@@ -0,0 +1,318 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+// CheckStatsFileName is the name of the flakiness stats file stored next to the history file.
+const CheckStatsFileName = "check_stats.json"
+
+// Flakiness detection keeps the outcomes of each check on its most recent
+// commits. A check that both failed and passed on the same commit, e.g. after a
+// rerun, flipped on that commit.
+const (
+	// MaxRecentCommits is the number of commits per check the flakiness score covers.
+	MaxRecentCommits = 50
+
+	// LikelyFlakyMinCommits is the number of commits a check must have flipped on
+	// before its failures are tagged as likely flaky.
+	LikelyFlakyMinCommits = 2
+
+	// CheckStatsMaxAge is how long the stats of a check are kept after its last
+	// recorded outcome, e.g. once its repo is no longer watched.
+	CheckStatsMaxAge = 90 * 24 * time.Hour
+
+	// MaxCheckStats caps the number of checks with stats; the checks seen least
+	// recently are dropped first.
+	MaxCheckStats = 1000
+)
+
+// CheckStats holds the recent outcomes of one check in one repo.
+type CheckStats struct {
+	Host      string          `json:"host,omitempty"` // empty means github.com
+	Owner     string          `json:"owner"`
+	Repo      string          `json:"repo"`
+	Check     string          `json:"check"`
+	Recent    []CommitOutcome `json:"recent"` // oldest first, at most MaxRecentCommits
+	LastSeen  time.Time       `json:"last_seen"`
+	LastFlaky time.Time       `json:"last_flaky,omitempty"`
+}
+
+// CommitOutcome records whether a check failed and/or passed on a commit.
+type CommitOutcome struct {
+	SHA    string `json:"sha"`
+	Failed bool   `json:"failed,omitempty"`
+	Passed bool   `json:"passed,omitempty"`
+}
+
+// Flipped reports whether the check both failed and passed on the commit.
+func (o CommitOutcome) Flipped() bool {
+	return o.Failed && o.Passed
+}
+
+// HostName returns the GitHub host of the repo, defaulting to github.com.
+func (s CheckStats) HostName() string {
+	return github.NormalizeHost(s.Host)
+}
+
+// FlakyCommits returns the number of recent commits the check flipped on.
+func (s CheckStats) FlakyCommits() int {
+	n := 0
+	for _, outcome := range s.Recent {
+		if outcome.Flipped() {
+			n++
+		}
+	}
+	return n
+}
+
+// Score returns the share of recent commits the check flipped on, from 0 to 1.
+func (s CheckStats) Score() float64 {
+	if len(s.Recent) == 0 {
+		return 0
+	}
+	return float64(s.FlakyCommits()) / float64(len(s.Recent))
+}
+
+// LikelyFlaky reports whether the check flipped on enough recent commits for
+// its failures to be considered likely flaky.
+func (s CheckStats) LikelyFlaky() bool {
+	return s.FlakyCommits() >= LikelyFlakyMinCommits
+}
+
+func (s CheckStats) clone() CheckStats {
+	s.Recent = append([]CommitOutcome(nil), s.Recent...)
+	return s
+}
+
+func (s CheckStats) matches(host, owner, repo, check string) bool {
+	return s.HostName() == github.NormalizeHost(host) &&
+		strings.EqualFold(s.Owner, owner) &&
+		strings.EqualFold(s.Repo, repo) &&
+		strings.EqualFold(s.Check, check)
+}
+
+// CheckStatsStore keeps the flakiness stats of checks in a JSON file. It is
+// safe for concurrent use within one process. A nil store records nothing.
+type CheckStatsStore struct {
+	path  string
+	mu    sync.Mutex
+	stats []CheckStats
+	dirty bool
+}
+
+// NewCheckStatsStore returns an empty store backed by the file at path; use
+// Load to read the file.
+func NewCheckStatsStore(path string) *CheckStatsStore {
+	return &CheckStatsStore{path: path}
+}
+
+// DefaultCheckStatsPath returns the stats file path next to the config file.
+func DefaultCheckStatsPath() (string, error) {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), CheckStatsFileName), nil
+}
+
+// OpenCheckStats loads the store at the default path.
+func OpenCheckStats() (*CheckStatsStore, error) {
+	path, err := DefaultCheckStatsPath()
+	if err != nil {
+		return nil, err
+	}
+	store := NewCheckStatsStore(path)
+	if err := store.Load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Load reads the stats file, replacing the stats in memory. A missing file
+// yields no stats.
+func (s *CheckStatsStore) Load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		data = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read check stats file: %w", err)
+	}
+
+	var stats []CheckStats
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &stats); err != nil {
+			return fmt.Errorf("failed to parse check stats file: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = stats
+	s.dirty = false
+	return nil
+}
+
+// Save prunes stale stats and writes the file if anything changed since it was
+// loaded or last saved.
+func (s *CheckStatsStore) Save() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.prune(time.Now()) {
+		s.dirty = true
+	}
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal check stats: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create check stats directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write check stats file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write check stats file: %w", err)
+	}
+	s.dirty = false
+	return nil
+}
+
+// prune drops the stats of checks not seen for CheckStatsMaxAge and keeps at
+// most MaxCheckStats checks. It reports whether anything was dropped.
+func (s *CheckStatsStore) prune(now time.Time) bool {
+	kept := s.stats[:0]
+	for _, stats := range s.stats {
+		if now.Sub(stats.LastSeen) <= CheckStatsMaxAge {
+			kept = append(kept, stats)
+		}
+	}
+	pruned := len(kept) != len(s.stats)
+	s.stats = kept
+
+	if len(s.stats) > MaxCheckStats {
+		sort.SliceStable(s.stats, func(i, j int) bool {
+			return s.stats[i].LastSeen.After(s.stats[j].LastSeen)
+		})
+		s.stats = s.stats[:MaxCheckStats]
+		pruned = true
+	}
+	return pruned
+}
+
+// All returns a copy of the stats of every check.
+func (s *CheckStatsStore) All() []CheckStats {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]CheckStats, 0, len(s.stats))
+	for _, stats := range s.stats {
+		all = append(all, stats.clone())
+	}
+	return all
+}
+
+// Find returns the stats of a check, if any were recorded.
+func (s *CheckStatsStore) Find(host, owner, repo, check string) (CheckStats, bool) {
+	if s == nil {
+		return CheckStats{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats := s.find(host, owner, repo, check); stats != nil {
+		return stats.clone(), true
+	}
+	return CheckStats{}, false
+}
+
+func (s *CheckStatsStore) find(host, owner, repo, check string) *CheckStats {
+	for i := range s.stats {
+		if s.stats[i].matches(host, owner, repo, check) {
+			return &s.stats[i]
+		}
+	}
+	return nil
+}
+
+// LikelyFlaky reports whether a check flipped on enough recent commits for its
+// failures to be considered likely flaky.
+func (s *CheckStatsStore) LikelyFlaky(host, owner, repo, check string) bool {
+	stats, ok := s.Find(host, owner, repo, check)
+	return ok && stats.LikelyFlaky()
+}
+
+// Record records the state of a check on a commit. Only success and failure
+// count as outcomes; other states are ignored. It reports whether the check
+// just flipped on the commit.
+func (s *CheckStatsStore) Record(host, owner, repo, check, sha, state string, at time.Time) bool {
+	state = github.NormalizeState(state)
+	if s == nil || sha == "" || (state != "success" && state != "failure") {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.find(host, owner, repo, check)
+	if stats == nil {
+		s.stats = append(s.stats, CheckStats{Host: host, Owner: owner, Repo: repo, Check: check})
+		stats = &s.stats[len(s.stats)-1]
+	}
+	// LastSeen only needs to be accurate enough for pruning, so repeated
+	// outcomes within the hour don't make the file change every cycle
+	if at.Sub(stats.LastSeen) >= time.Hour {
+		stats.LastSeen = at
+		s.dirty = true
+	}
+
+	var outcome *CommitOutcome
+	for i := range stats.Recent {
+		if stats.Recent[i].SHA == sha {
+			outcome = &stats.Recent[i]
+			break
+		}
+	}
+	if outcome == nil {
+		stats.Recent = append(stats.Recent, CommitOutcome{SHA: sha})
+		if len(stats.Recent) > MaxRecentCommits {
+			stats.Recent = stats.Recent[len(stats.Recent)-MaxRecentCommits:]
+		}
+		outcome = &stats.Recent[len(stats.Recent)-1]
+		s.dirty = true
+	}
+
+	flipped := outcome.Flipped()
+	if state == "success" && !outcome.Passed {
+		outcome.Passed = true
+		s.dirty = true
+	} else if state == "failure" && !outcome.Failed {
+		outcome.Failed = true
+		s.dirty = true
+	}
+	if !flipped && outcome.Flipped() {
+		stats.LastFlaky = at
+		return true
+	}
+	return false
+}
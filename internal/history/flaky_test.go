@@ -0,0 +1,118 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckStatsRecord(t *testing.T) {
+	store := NewCheckStatsStore(filepath.Join(t.TempDir(), CheckStatsFileName))
+	now := time.Now()
+
+	if store.Record("", "owner", "repo", "e2e", "sha1", "pending", now) || len(store.All()) != 0 {
+		t.Fatal("expected pending checks to be ignored")
+	}
+	if store.Record("", "owner", "repo", "e2e", "sha1", "failure", now) {
+		t.Error("a first failure is not a flip")
+	}
+	if !store.Record("github.com", "Owner", "repo", "E2E", "sha1", "success", now) {
+		t.Error("expected passing after failing on the same commit to flip")
+	}
+	if store.Record("", "owner", "repo", "e2e", "sha1", "failure", now) {
+		t.Error("a commit flips only once")
+	}
+	store.Record("", "owner", "repo", "e2e", "sha2", "failure", now)
+	store.Record("", "owner", "repo", "e2e", "sha3", "success", now)
+
+	stats, ok := store.Find("", "owner", "repo", "e2e")
+	if !ok || len(store.All()) != 1 {
+		t.Fatalf("expected one stats entry, got %+v", store.All())
+	}
+	if stats.FlakyCommits() != 1 || len(stats.Recent) != 3 || !stats.LastFlaky.Equal(now) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.LikelyFlaky() || store.LikelyFlaky("", "owner", "repo", "e2e") {
+		t.Error("one flaky commit should not make a check likely flaky")
+	}
+
+	store.Record("", "owner", "repo", "e2e", "sha3", "failure", now)
+	if !store.LikelyFlaky("", "owner", "repo", "e2e") {
+		t.Error("expected a check flipping on two commits to be likely flaky")
+	}
+	stats, _ = store.Find("", "owner", "repo", "e2e")
+	if got := stats.Score(); got < 0.66 || got > 0.67 {
+		t.Errorf("Score() = %v, want 2/3", got)
+	}
+
+	// Only the most recent commits are kept.
+	for i := 0; i < MaxRecentCommits; i++ {
+		store.Record("", "owner", "repo", "e2e", fmt.Sprintf("new%d", i), "success", now)
+	}
+	stats, _ = store.Find("", "owner", "repo", "e2e")
+	if len(stats.Recent) != MaxRecentCommits || stats.FlakyCommits() != 0 {
+		t.Errorf("expected old commits to be dropped, got %d commits and %d flaky", len(stats.Recent), stats.FlakyCommits())
+	}
+
+	var nilStore *CheckStatsStore
+	if nilStore.Record("", "owner", "repo", "e2e", "sha1", "failure", now) || nilStore.LikelyFlaky("", "owner", "repo", "e2e") || nilStore.Save() != nil {
+		t.Error("expected a nil store to record nothing")
+	}
+}
+
+func TestCheckStatsSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", CheckStatsFileName)
+	store := NewCheckStatsStore(path)
+	now := time.Now()
+
+	store.Record("", "owner", "repo", "e2e", "sha1", "failure", now)
+	store.Record("", "owner", "repo", "e2e", "sha1", "success", now)
+	// A check last seen long ago is pruned on save
+	store.Record("", "owner", "gone", "lint", "sha1", "success", now.Add(-CheckStatsMaxAge-time.Hour))
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := NewCheckStatsStore(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	all := loaded.All()
+	if len(all) != 1 || all[0].Check != "e2e" || all[0].FlakyCommits() != 1 {
+		t.Fatalf("unexpected stats after reload: %+v", all)
+	}
+
+	// Outcomes already recorded don't rewrite the file
+	info, _ := os.Stat(path)
+	os.Chtimes(path, info.ModTime().Add(-time.Hour), info.ModTime().Add(-time.Hour))
+	loaded.Record("", "owner", "repo", "e2e", "sha1", "success", now.Add(time.Minute))
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if after, _ := os.Stat(path); !after.ModTime().Equal(info.ModTime().Add(-time.Hour)) {
+		t.Error("expected an unchanged store not to be written")
+	}
+
+	if err := NewCheckStatsStore(filepath.Join(t.TempDir(), "missing.json")).Load(); err != nil {
+		t.Errorf("expected a missing file to load as empty, got %v", err)
+	}
+}
+
+func TestCheckStatsCap(t *testing.T) {
+	store := NewCheckStatsStore(filepath.Join(t.TempDir(), CheckStatsFileName))
+	now := time.Now()
+	for i := 0; i <= MaxCheckStats; i++ {
+		store.Record("", "owner", "repo", fmt.Sprintf("check%d", i), "sha1", "success", now.Add(time.Duration(i-MaxCheckStats)*time.Hour))
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if len(store.All()) != MaxCheckStats {
+		t.Errorf("expected %d checks, got %d", MaxCheckStats, len(store.All()))
+	}
+	if _, ok := store.Find("", "owner", "repo", "check0"); ok {
+		t.Error("expected the least recently seen check to be dropped")
+	}
+}
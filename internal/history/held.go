@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// HeldEventsFileName is the name of the file, stored next to the history file,
+// that keeps the events held during quiet hours across restarts.
+const HeldEventsFileName = "quiet_held.json"
+
+// heldEventsMu serializes updates of held events files by every store in the
+// process, e.g. the quiet hours of several routed notifiers.
+var heldEventsMu sync.Mutex
+
+// HeldEventStore keeps the events a quiet hours notifier holds in a JSON file,
+// under a key naming the notifier. It implements notify.HeldEventStore.
+type HeldEventStore struct {
+	path string
+	key  string
+}
+
+// NewHeldEventStore returns a store for key backed by the file at path.
+func NewHeldEventStore(path, key string) *HeldEventStore {
+	return &HeldEventStore{path: path, key: key}
+}
+
+// DefaultHeldEventsPath returns the held events file path next to the config file.
+func DefaultHeldEventsPath() (string, error) {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), HeldEventsFileName), nil
+}
+
+// OpenHeldEvents returns the store for key at the default path.
+func OpenHeldEvents(key string) (*HeldEventStore, error) {
+	path, err := DefaultHeldEventsPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewHeldEventStore(path, key), nil
+}
+
+// LoadHeld returns the events stored for the key. A missing file has none.
+func (s *HeldEventStore) LoadHeld() ([]*notify.StatusChangeEvent, error) {
+	heldEventsMu.Lock()
+	defer heldEventsMu.Unlock()
+
+	held, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return held[s.key], nil
+}
+
+// SaveHeld replaces the events stored for the key; no events removes the key.
+func (s *HeldEventStore) SaveHeld(events []*notify.StatusChangeEvent) error {
+	heldEventsMu.Lock()
+	defer heldEventsMu.Unlock()
+
+	// An unreadable file is replaced rather than blocking quiet hours
+	held, _ := s.read()
+	if held == nil {
+		held = make(map[string][]*notify.StatusChangeEvent)
+	}
+	if len(events) == 0 {
+		if _, ok := held[s.key]; !ok {
+			return nil
+		}
+		delete(held, s.key)
+	} else {
+		held[s.key] = events
+	}
+
+	data, err := json.MarshalIndent(held, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal held events: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create held events directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write held events file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write held events file: %w", err)
+	}
+	return nil
+}
+
+// read parses the held events file. A missing file yields no events.
+func (s *HeldEventStore) read() (map[string][]*notify.StatusChangeEvent, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read held events file: %w", err)
+	}
+	var held map[string][]*notify.StatusChangeEvent
+	if err := json.Unmarshal(data, &held); err != nil {
+		return nil, fmt.Errorf("failed to parse held events file: %w", err)
+	}
+	return held, nil
+}
@@ -0,0 +1,54 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devblac/prw/internal/notify"
+)
+
+func TestHeldEventStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", HeldEventsFileName)
+	outbound := NewHeldEventStore(path, "outbound")
+
+	if events, err := outbound.LoadHeld(); err != nil || len(events) != 0 {
+		t.Fatalf("expected no events before the file exists, got %v, %v", events, err)
+	}
+	held := []*notify.StatusChangeEvent{
+		{Owner: "o", Repo: "r", Number: 1, PreviousState: "pending", CurrentState: "success"},
+		{Type: notify.EventPushed, Owner: "o", Repo: "r", Number: 2, Commits: 3},
+	}
+	if err := outbound.SaveHeld(held); err != nil {
+		t.Fatalf("SaveHeld failed: %v", err)
+	}
+
+	// Another notifier's store on the same file keeps its own events.
+	email := NewHeldEventStore(path, "email")
+	if err := email.SaveHeld(held[:1]); err != nil {
+		t.Fatalf("SaveHeld failed: %v", err)
+	}
+	events, err := outbound.LoadHeld()
+	if err != nil {
+		t.Fatalf("LoadHeld failed: %v", err)
+	}
+	if len(events) != 2 || events[0].CurrentState != "success" || events[1].EventType() != notify.EventPushed || events[1].Commits != 3 {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	// Saving no events removes the key and keeps the others.
+	if err := outbound.SaveHeld(nil); err != nil {
+		t.Fatalf("SaveHeld failed: %v", err)
+	}
+	if events, _ := outbound.LoadHeld(); len(events) != 0 {
+		t.Errorf("expected the events to be cleared, got %+v", events)
+	}
+	if events, _ := email.LoadHeld(); len(events) != 1 {
+		t.Errorf("expected the other key to be kept, got %+v", events)
+	}
+
+	os.WriteFile(path, []byte("not json"), 0600)
+	if _, err := outbound.LoadHeld(); err == nil {
+		t.Error("expected an error for a corrupt file")
+	}
+}
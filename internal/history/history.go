@@ -0,0 +1,295 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// Record kinds.
+const (
+	KindEvent = "event" // a status change, merge, or close
+	KindPoll  = "poll"  // the result of a single PR check, recorded only when enabled
+)
+
+// FileName is the name of the history file stored next to the config file.
+const FileName = "history.jsonl"
+
+// Record is a single entry in the history file.
+type Record struct {
+	Kind            string              `json:"kind"`
+	Type            string              `json:"type,omitempty"` // event type, see notify.EventType
+	Host            string              `json:"host,omitempty"`
+	Owner           string              `json:"owner"`
+	Repo            string              `json:"repo"`
+	Number          int                 `json:"number"`
+	Title           string              `json:"title,omitempty"`
+	Author          string              `json:"author,omitempty"`
+	Labels          []string            `json:"labels,omitempty"`
+	PreviousState   string              `json:"previous_state,omitempty"`
+	CurrentState    string              `json:"current_state"`
+	SHA             string              `json:"sha,omitempty"`
+	PreviousSHA     string              `json:"previous_sha,omitempty"`
+	Commits         int                 `json:"commits,omitempty"`
+	Authors         []string            `json:"authors,omitempty"`
+	CompareFailed   bool                `json:"compare_failed,omitempty"`
+	Checks          []github.Check      `json:"checks,omitempty"`
+	Reviewers       []string            `json:"reviewers,omitempty"`
+	Logs            []notify.LogExcerpt `json:"logs,omitempty"`
+	FlakyChecks     []string            `json:"flaky_checks,omitempty"`
+	DurationSeconds int64               `json:"duration_seconds,omitempty"` // time CI spent pending
+	Timestamp       time.Time           `json:"timestamp"`
+}
+
+// FromEvent converts a notification event into a history record.
+func FromEvent(event *notify.StatusChangeEvent) Record {
+	return Record{
+		Kind:            KindEvent,
+		Type:            event.EventType(),
+		Host:            event.Host,
+		Owner:           event.Owner,
+		Repo:            event.Repo,
+		Number:          event.Number,
+		Title:           event.Title,
+		Author:          event.Author,
+		Labels:          event.Labels,
+		PreviousState:   event.PreviousState,
+		CurrentState:    event.CurrentState,
+		SHA:             event.SHA,
+		PreviousSHA:     event.PreviousSHA,
+		Commits:         event.Commits,
+		Authors:         event.Authors,
+		CompareFailed:   event.CompareFailed,
+		Checks:          event.Checks,
+		Reviewers:       event.Reviewers,
+		Logs:            event.Logs,
+		FlakyChecks:     event.FlakyChecks,
+		DurationSeconds: int64(event.Duration.Seconds()),
+		Timestamp:       event.Timestamp,
+	}
+}
+
+// Event converts the record back into a notification event.
+func (r Record) Event() *notify.StatusChangeEvent {
+	return &notify.StatusChangeEvent{
+		Type:          r.Type,
+		Host:          r.Host,
+		Owner:         r.Owner,
+		Repo:          r.Repo,
+		Number:        r.Number,
+		Title:         r.Title,
+		Author:        r.Author,
+		Labels:        r.Labels,
+		PreviousState: r.PreviousState,
+		CurrentState:  r.CurrentState,
+		SHA:           r.SHA,
+		PreviousSHA:   r.PreviousSHA,
+		Commits:       r.Commits,
+		Authors:       r.Authors,
+		CompareFailed: r.CompareFailed,
+		Checks:        r.Checks,
+		Reviewers:     r.Reviewers,
+		Logs:          r.Logs,
+		FlakyChecks:   r.FlakyChecks,
+		Duration:      time.Duration(r.DurationSeconds) * time.Second,
+		Timestamp:     r.Timestamp,
+	}
+}
+
+// Filter selects records from the history. Zero values match everything.
+type Filter struct {
+	Host   string
+	Owner  string
+	Repo   string
+	Number int
+
+	Since time.Time
+	Until time.Time
+
+	// States matches the current state of a record (pending, success, failure,
+	// error, merged, closed).
+	States []string
+
+	// IncludePolls also returns poll results; by default only events are returned.
+	IncludePolls bool
+
+	// Limit keeps only the most recent records when positive.
+	Limit int
+}
+
+// Match reports whether the record passes the filter.
+func (f Filter) Match(r Record) bool {
+	if r.Kind == KindPoll && !f.IncludePolls {
+		return false
+	}
+	if f.Host != "" && github.NormalizeHost(f.Host) != github.NormalizeHost(r.Host) {
+		return false
+	}
+	if f.Owner != "" && !strings.EqualFold(f.Owner, r.Owner) {
+		return false
+	}
+	if f.Repo != "" && !strings.EqualFold(f.Repo, r.Repo) {
+		return false
+	}
+	if f.Number != 0 && f.Number != r.Number {
+		return false
+	}
+	if !f.Since.IsZero() && r.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && r.Timestamp.After(f.Until) {
+		return false
+	}
+	if len(f.States) > 0 {
+		matched := false
+		for _, s := range f.States {
+			if github.NormalizeState(s) == github.NormalizeState(r.CurrentState) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Store is an append-only history of events kept as JSON lines.
+// It is safe for concurrent use within one process.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore returns a store backed by the file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the history file path next to the config file.
+func DefaultPath() (string, error) {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), FileName), nil
+}
+
+// Open returns the store at the default path.
+func Open() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(path), nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Append adds a record to the end of the history.
+func (s *Store) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// RecordEvent appends a notification event to the history.
+func (s *Store) RecordEvent(event *notify.StatusChangeEvent) error {
+	return s.Append(FromEvent(event))
+}
+
+// RecordPoll appends the result of checking a watched PR to the history.
+func (s *Store) RecordPoll(pr config.WatchedPR) error {
+	state := pr.LastKnownState
+	if pr.IsClosed() {
+		state = pr.PRState
+	}
+	return s.Append(Record{
+		Kind:         KindPoll,
+		Host:         pr.Host,
+		Owner:        pr.Owner,
+		Repo:         pr.Repo,
+		Number:       pr.Number,
+		Title:        pr.Title,
+		CurrentState: state,
+		SHA:          pr.LastKnownSHA,
+		Timestamp:    pr.LastChecked,
+	})
+}
+
+// Query returns the records matching filter in the order they were appended.
+// A missing history file yields no records. Lines that cannot be parsed, such as
+// a partially written last line, are skipped.
+func (s *Store) Query(filter Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			continue
+		}
+		if filter.Match(r) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[len(records)-filter.Limit:]
+	}
+	return records, nil
+}
@@ -0,0 +1,210 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+func newTestStore(t *testing.T) *Store {
+	return NewStore(filepath.Join(t.TempDir(), "nested", FileName))
+}
+
+func TestStoreAppendAndQuery(t *testing.T) {
+	store := newTestStore(t)
+	base := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	events := []*notify.StatusChangeEvent{
+		{Owner: "owner", Repo: "repo", Number: 1, PreviousState: "pending", CurrentState: "failure", Timestamp: base},
+		{Owner: "owner", Repo: "other", Number: 2, PreviousState: "pending", CurrentState: "success", Timestamp: base.Add(time.Hour)},
+		{Type: notify.EventMerged, Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 1, PreviousState: "open", CurrentState: "merged", Timestamp: base.Add(2 * time.Hour)},
+	}
+	for _, e := range events {
+		if err := store.RecordEvent(e); err != nil {
+			t.Fatalf("RecordEvent failed: %v", err)
+		}
+	}
+	if err := store.RecordPoll(config.WatchedPR{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "failure", LastChecked: base.Add(3 * time.Hour)}); err != nil {
+		t.Fatalf("RecordPoll failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int // indexes into events, or -1 for the poll record
+	}{
+		{name: "all events", filter: Filter{}, want: []int{0, 1, 2}},
+		{name: "with polls", filter: Filter{IncludePolls: true}, want: []int{0, 1, 2, -1}},
+		{name: "by repo", filter: Filter{Owner: "owner", Repo: "repo"}, want: []int{0, 2}},
+		{name: "by host", filter: Filter{Host: "github.com", Owner: "owner", Repo: "repo", Number: 1}, want: []int{0}},
+		{name: "by state", filter: Filter{States: []string{"failure", "MERGED"}}, want: []int{0, 2}},
+		{name: "since", filter: Filter{Since: base.Add(30 * time.Minute)}, want: []int{1, 2}},
+		{name: "until", filter: Filter{Until: base.Add(time.Hour)}, want: []int{0, 1}},
+		{name: "limit keeps most recent", filter: Filter{Limit: 2}, want: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if len(records) != len(tt.want) {
+				t.Fatalf("expected %d records, got %d: %+v", len(tt.want), len(records), records)
+			}
+			for i, idx := range tt.want {
+				if idx == -1 {
+					if records[i].Kind != KindPoll {
+						t.Errorf("record %d: expected poll, got %+v", i, records[i])
+					}
+					continue
+				}
+				if !records[i].Timestamp.Equal(events[idx].Timestamp) || records[i].Kind != KindEvent {
+					t.Errorf("record %d: expected event %d, got %+v", i, idx, records[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStoreRoundTripsEvents(t *testing.T) {
+	store := newTestStore(t)
+	event := &notify.StatusChangeEvent{
+		Type:          notify.EventClosed,
+		Host:          "github.example.com",
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        5,
+		Title:         "Close me",
+		PreviousState: "open",
+		CurrentState:  "closed",
+		SHA:           "abc",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := store.RecordEvent(event); err != nil {
+		t.Fatalf("RecordEvent failed: %v", err)
+	}
+
+	records, err := store.Query(Filter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one record, got %v (%v)", records, err)
+	}
+	got := records[0].Event()
+	if got.EventType() != notify.EventClosed || got.URL() != event.URL() || got.Title != event.Title || !got.Timestamp.Equal(event.Timestamp) {
+		t.Errorf("event did not round-trip: %+v", got)
+	}
+}
+
+func TestStoreRoundTripsAllEventFields(t *testing.T) {
+	store := newTestStore(t)
+	event := &notify.StatusChangeEvent{
+		Type:          notify.EventPushed,
+		Host:          "github.example.com",
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        7,
+		Title:         "Add feature",
+		Author:        "alice",
+		Labels:        []string{"bug", "backend"},
+		PreviousState: "failure",
+		CurrentState:  "pending",
+		SHA:           "def",
+		PreviousSHA:   "abc",
+		Commits:       2,
+		Authors:       []string{"alice", "bob"},
+		CompareFailed: true,
+		Checks:        []github.Check{{Name: "ci", Source: github.CheckSourceCheckRun, State: "failure", URL: "https://example.com/ci"}},
+		Reviewers:     []string{"carol"},
+		Logs:          []notify.LogExcerpt{{Check: "ci", Step: "Run tests", URL: "https://example.com/ci", Lines: []string{"FAIL", "exit 1"}}},
+		FlakyChecks:   []string{"ci"},
+		Duration:      90 * time.Second,
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := store.RecordEvent(event); err != nil {
+		t.Fatalf("RecordEvent failed: %v", err)
+	}
+
+	records, err := store.Query(Filter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected one record, got %v (%v)", records, err)
+	}
+	if got := records[0].Event(); !reflect.DeepEqual(got, event) {
+		t.Errorf("event did not round-trip:\ngot  %+v\nwant %+v", got, event)
+	}
+
+	// Lines written before author, labels, and logs were recorded still parse
+	f, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("failed to open history: %v", err)
+	}
+	f.WriteString(`{"kind":"event","type":"status_change","owner":"o","repo":"r","number":1,"current_state":"success","timestamp":"2025-01-03T00:00:00Z"}` + "\n")
+	f.Close()
+
+	records, err = store.Query(Filter{})
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected two records, got %v (%v)", records, err)
+	}
+	old := records[1].Event()
+	if old.Number != 1 || old.Author != "" || old.Labels != nil || old.Logs != nil {
+		t.Errorf("unexpected event from an older line: %+v", old)
+	}
+}
+
+func TestStoreQueryMissingFile(t *testing.T) {
+	records, err := newTestStore(t).Query(Filter{})
+	if err != nil {
+		t.Fatalf("expected no error for missing history, got %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+}
+
+func TestStoreSkipsMalformedLines(t *testing.T) {
+	store := newTestStore(t)
+	if err := store.Append(Record{Kind: KindEvent, Owner: "o", Repo: "r", Number: 1, CurrentState: "success"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	// Simulate a write interrupted half-way
+	f, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("failed to open history: %v", err)
+	}
+	f.WriteString(`{"kind":"event","owner":"o"` + "\n\n")
+	f.Close()
+
+	if err := store.Append(Record{Kind: KindEvent, Owner: "o", Repo: "r", Number: 2, CurrentState: "failure"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	records, err := store.Query(Filter{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(records) != 2 || records[0].Number != 1 || records[1].Number != 2 {
+		t.Errorf("expected the two valid records, got %+v", records)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join("/tmp", ".prw", "config.json"), nil
+	}
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath failed: %v", err)
+	}
+	if path != filepath.Join("/tmp", ".prw", FileName) {
+		t.Errorf("expected history next to config, got %s", path)
+	}
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+)
+
+// SlackThreadsFileName is the name of the Slack threads file stored next to the history file.
+const SlackThreadsFileName = "slack_threads.json"
+
+// SlackThreadMaxAge is how long the thread of a PR is continued. Older threads
+// are dropped, and the next event of the PR starts a new one.
+const SlackThreadMaxAge = 90 * 24 * time.Hour
+
+// slackThreadsMu serializes updates of threads files by every store in the
+// process, e.g. several Slack notifiers posting to different channels.
+var slackThreadsMu sync.Mutex
+
+// slackThread is a thread started by a Slack bot notifier.
+type slackThread struct {
+	Channel string    `json:"channel"`
+	TS      string    `json:"ts"`
+	Started time.Time `json:"started"`
+}
+
+// SlackThreadStore keeps the Slack thread of each PR in a JSON file. It
+// implements notify.SlackThreadStore.
+type SlackThreadStore struct {
+	path string
+}
+
+// NewSlackThreadStore returns a store backed by the file at path.
+func NewSlackThreadStore(path string) *SlackThreadStore {
+	return &SlackThreadStore{path: path}
+}
+
+// DefaultSlackThreadsPath returns the Slack threads file path next to the config file.
+func DefaultSlackThreadsPath() (string, error) {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), SlackThreadsFileName), nil
+}
+
+// OpenSlackThreads returns the store at the default path.
+func OpenSlackThreads() (*SlackThreadStore, error) {
+	path, err := DefaultSlackThreadsPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewSlackThreadStore(path), nil
+}
+
+// LoadSlackThread returns the thread stored for key. A missing or unreadable
+// file has no threads.
+func (s *SlackThreadStore) LoadSlackThread(key string) (channel, ts string, ok bool) {
+	slackThreadsMu.Lock()
+	defer slackThreadsMu.Unlock()
+
+	threads, _ := s.read()
+	thread, ok := threads[key]
+	if !ok || time.Since(thread.Started) > SlackThreadMaxAge {
+		return "", "", false
+	}
+	return thread.Channel, thread.TS, true
+}
+
+// SaveSlackThread stores the thread started for key and drops expired threads.
+func (s *SlackThreadStore) SaveSlackThread(key, channel, ts string) error {
+	slackThreadsMu.Lock()
+	defer slackThreadsMu.Unlock()
+
+	// An unreadable file is replaced rather than blocking new threads
+	threads, _ := s.read()
+	if threads == nil {
+		threads = make(map[string]slackThread)
+	}
+	now := time.Now()
+	for k, thread := range threads {
+		if now.Sub(thread.Started) > SlackThreadMaxAge {
+			delete(threads, k)
+		}
+	}
+	threads[key] = slackThread{Channel: channel, TS: ts, Started: now}
+
+	data, err := json.MarshalIndent(threads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack threads: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create Slack threads directory: %w", err)
+	}
+	// Write to a temporary file first so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write Slack threads file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write Slack threads file: %w", err)
+	}
+	return nil
+}
+
+// read parses the threads file. A missing file yields no threads.
+func (s *SlackThreadStore) read() (map[string]slackThread, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read Slack threads file: %w", err)
+	}
+	var threads map[string]slackThread
+	if err := json.Unmarshal(data, &threads); err != nil {
+		return nil, fmt.Errorf("failed to parse Slack threads file: %w", err)
+	}
+	return threads, nil
+}
@@ -0,0 +1,59 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSlackThreadStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", SlackThreadsFileName)
+	store := NewSlackThreadStore(path)
+
+	if _, _, ok := store.LoadSlackThread("#ci https://github.com/o/r/pull/1"); ok {
+		t.Fatal("expected no thread before the file exists")
+	}
+	if err := store.SaveSlackThread("#ci https://github.com/o/r/pull/1", "C123", "1700000000.000001"); err != nil {
+		t.Fatalf("SaveSlackThread failed: %v", err)
+	}
+
+	// Another store on the same file, e.g. a second notifier, keeps both threads.
+	other := NewSlackThreadStore(path)
+	if err := other.SaveSlackThread("#api https://github.com/o/r/pull/1", "C456", "1700000000.000002"); err != nil {
+		t.Fatalf("SaveSlackThread failed: %v", err)
+	}
+	channel, ts, ok := store.LoadSlackThread("#ci https://github.com/o/r/pull/1")
+	if !ok || channel != "C123" || ts != "1700000000.000001" {
+		t.Errorf("LoadSlackThread() = %q, %q, %v", channel, ts, ok)
+	}
+	if _, ts, ok := store.LoadSlackThread("#api https://github.com/o/r/pull/1"); !ok || ts != "1700000000.000002" {
+		t.Errorf("expected the other store's thread to be kept, got %q, %v", ts, ok)
+	}
+
+	// Expired threads are not continued and are dropped on the next save.
+	data, _ := os.ReadFile(path)
+	var threads map[string]slackThread
+	if err := json.Unmarshal(data, &threads); err != nil {
+		t.Fatalf("failed to parse threads file: %v", err)
+	}
+	old := threads["#ci https://github.com/o/r/pull/1"]
+	old.Started = time.Now().Add(-SlackThreadMaxAge - time.Hour)
+	threads["#ci https://github.com/o/r/pull/1"] = old
+	data, _ = json.Marshal(threads)
+	os.WriteFile(path, data, 0600)
+
+	if _, _, ok := store.LoadSlackThread("#ci https://github.com/o/r/pull/1"); ok {
+		t.Error("expected an expired thread not to be continued")
+	}
+	if err := store.SaveSlackThread("#ci https://github.com/o/r/pull/2", "C123", "1700000000.000003"); err != nil {
+		t.Fatalf("SaveSlackThread failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	threads = nil
+	json.Unmarshal(data, &threads)
+	if _, ok := threads["#ci https://github.com/o/r/pull/1"]; ok || len(threads) != 2 {
+		t.Errorf("expected the expired thread to be dropped, got %+v", threads)
+	}
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devblac/prw/internal/retry"
+)
+
+// DiscordNotifier posts events to a Discord webhook as embeds colored by state.
+type DiscordNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+	Retry      retry.Policy
+	Template   *MessageTemplate // renders the request body instead of the embed
+}
+
+// NewDiscordNotifier creates a Discord webhook notifier.
+func NewDiscordNotifier(url string) *DiscordNotifier {
+	return &DiscordNotifier{
+		URL:        url,
+		HTTPClient: newHTTPClient(),
+		Retry:      retry.DefaultPolicy(),
+	}
+}
+
+// discordMessage is the body of a Discord webhook execution.
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// Discord limits embed titles to 256 characters and descriptions to 4096.
+const (
+	discordMaxTitle        = 256
+	discordMaxSummaryLines = 20
+)
+
+// discordColors maps event tones to embed colors.
+var discordColors = map[string]int{
+	toneGood:    0x2EA043,
+	toneBad:     0xCF222E,
+	toneWarning: 0xDBAB0A,
+	toneInfo:    0x0969DA,
+	toneNeutral: 0x6E7781,
+}
+
+// Notify posts the event to the Discord webhook.
+func (d *DiscordNotifier) Notify(event *StatusChangeEvent) error {
+	if d.URL == "" {
+		return nil
+	}
+	if sent, err := postTemplate(d.HTTPClient, d.Retry, d.URL, d.Template, event); sent {
+		return err
+	}
+	return postJSON(d.HTTPClient, d.Retry, d.URL, discordMessage{
+		Username: "prw",
+		Embeds:   []discordEmbed{discordEventEmbed(event)},
+	})
+}
+
+// NotifySummary posts one embed listing the events held during quiet hours.
+func (d *DiscordNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	if d.URL == "" {
+		return nil
+	}
+
+	var lines []string
+	for i, event := range events {
+		if i == discordMaxSummaryLines {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(events)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("[%s/%s#%d](%s): %s", event.Owner, event.Repo, event.Number, event.URL(), eventHeadline(event)))
+	}
+	return postJSON(d.HTTPClient, d.Retry, d.URL, discordMessage{
+		Username: "prw",
+		Embeds: []discordEmbed{{
+			Title:       summaryTitle(events),
+			Description: strings.Join(lines, "\n"),
+			Color:       discordColors[toneInfo],
+		}},
+	})
+}
+
+// discordEventEmbed builds the embed of an event.
+func discordEventEmbed(event *StatusChangeEvent) discordEmbed {
+	title := fmt.Sprintf("%s/%s#%d", event.Owner, event.Repo, event.Number)
+	if event.Title != "" {
+		title += ": " + event.Title
+	}
+	if runes := []rune(title); len(runes) > discordMaxTitle {
+		title = string(runes[:discordMaxTitle-1]) + "…"
+	}
+
+	embed := discordEmbed{
+		Title:       title,
+		URL:         event.URL(),
+		Description: "**" + eventHeadline(event) + "**",
+		Color:       discordColors[eventTone(event)],
+	}
+	for _, fact := range eventFacts(event) {
+		embed.Fields = append(embed.Fields, discordField{Name: fact.Name, Value: fact.Value})
+	}
+	if event.SHA != "" {
+		embed.Footer = &discordFooter{Text: shortSHA(event.SHA)}
+	}
+	if !event.Timestamp.IsZero() {
+		embed.Timestamp = event.Timestamp.UTC().Format(time.RFC3339)
+	}
+	return embed
+}
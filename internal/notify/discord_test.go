@@ -0,0 +1,25 @@
+package notify
+
+import "testing"
+
+func TestDiscordNotifierGolden(t *testing.T) {
+	for name, event := range goldenEvents {
+		t.Run(name, func(t *testing.T) {
+			got := capturePost(t, func(url string) error {
+				return NewDiscordNotifier(url).Notify(event)
+			})
+			assertGolden(t, "discord_"+name+".golden", got)
+		})
+	}
+
+	got := capturePost(t, func(url string) error {
+		return NewDiscordNotifier(url).NotifySummary(goldenSummary())
+	})
+	assertGolden(t, "discord_summary.golden", got)
+}
+
+func TestDiscordNotifierEmptyURL(t *testing.T) {
+	if err := NewDiscordNotifier("").Notify(goldenEvents["failure"]); err != nil {
+		t.Errorf("expected no error without a URL, got %v", err)
+	}
+}
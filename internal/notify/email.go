@@ -0,0 +1,417 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Connection security of an SMTP server.
+const (
+	EmailSecurityStartTLS = "starttls" // upgrade a plain connection, usually on port 587
+	EmailSecurityTLS      = "tls"      // implicit TLS, usually on port 465
+	EmailSecurityNone     = "none"     // no encryption, for local relays only
+)
+
+// IsValidEmailSecurity reports whether security is a known SMTP security mode.
+func IsValidEmailSecurity(security string) bool {
+	switch security {
+	case "", EmailSecurityStartTLS, EmailSecurityTLS, EmailSecurityNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultSMTPPort returns the usual port for a security mode.
+func DefaultSMTPPort(security string) int {
+	switch security {
+	case EmailSecurityTLS:
+		return 465
+	case EmailSecurityNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// smtpTimeout bounds connecting to and talking with the SMTP server.
+const smtpTimeout = 30 * time.Second
+
+// EmailNotifier sends each event as an HTML and plain-text email over SMTP.
+// With a Digest window, events are batched and sent as one email when the
+// window after the first of them ends.
+type EmailNotifier struct {
+	Host     string
+	Port     int    // 0 means the default port of Security
+	Security string // starttls (default), tls, or none
+	Username string // empty disables authentication
+	Password string
+	From     string
+	To       []string
+	Digest   time.Duration // 0 sends one email per event
+
+	// Template replaces the built-in email of a single event for the event
+	// types it covers; the first line of its output is the subject and the
+	// rest the plain-text body. Digests keep the built-in format.
+	Template *MessageTemplate
+
+	// TLSConfig overrides the TLS settings used to talk to Host.
+	TLSConfig *tls.Config
+
+	// OnError is called when sending a digest in the background fails.
+	// It defaults to printing a warning to stderr.
+	OnError func(error)
+
+	mu      sync.Mutex
+	pending []*StatusChangeEvent
+	timer   *time.Timer
+}
+
+// NewEmailNotifier creates an email notifier that sends from one address to a list of recipients.
+func NewEmailNotifier(host string, port int, from string, to []string) *EmailNotifier {
+	return &EmailNotifier{
+		Host:     host,
+		Port:     port,
+		Security: EmailSecurityStartTLS,
+		From:     from,
+		To:       to,
+	}
+}
+
+// Notify emails the event, or adds it to the digest when digests are enabled.
+func (e *EmailNotifier) Notify(event *StatusChangeEvent) error {
+	if e.Digest <= 0 {
+		subject := fmt.Sprintf("[prw] %s/%s#%d: %s", event.Owner, event.Repo, event.Number, eventHeadline(event))
+		text, ok, err := e.Template.Render(event)
+		if !ok {
+			return e.sendEvents(subject, []*StatusChangeEvent{event})
+		}
+		if err != nil {
+			return err
+		}
+		title, body := SplitTitle(text)
+		if title != "" {
+			subject = title
+		}
+		return e.send(subject, body, "<pre>"+template.HTMLEscapeString(body)+"</pre>")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pending = append(e.pending, event)
+	if e.timer == nil {
+		e.timer = time.AfterFunc(e.Digest, func() {
+			if err := e.Flush(); err != nil {
+				if e.OnError != nil {
+					e.OnError(err)
+				} else {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+		})
+	}
+	return nil
+}
+
+// NotifySummary emails the events held during quiet hours as one digest.
+func (e *EmailNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	return e.sendEvents("[prw] "+summaryTitle(events), events)
+}
+
+// Flush sends the pending digest right away.
+func (e *EmailNotifier) Flush() error {
+	e.mu.Lock()
+	events := e.pending
+	e.pending = nil
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	e.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+	subject := fmt.Sprintf("[prw] %d PR updates", len(events))
+	if len(events) == 1 {
+		subject = "[prw] 1 PR update"
+	}
+	if err := e.sendEvents(subject, events); err != nil {
+		return fmt.Errorf("email digest failed: %w", err)
+	}
+	return nil
+}
+
+// sendEvents delivers one email with the built-in bodies for the events.
+func (e *EmailNotifier) sendEvents(subject string, events []*StatusChangeEvent) error {
+	html, err := emailHTML(events)
+	if err != nil {
+		return err
+	}
+	return e.send(subject, emailText(events), html)
+}
+
+// send delivers one email with plain-text and HTML bodies.
+func (e *EmailNotifier) send(subject, text, html string) error {
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return fmt.Errorf("invalid email sender %q: %w", e.From, err)
+	}
+	if len(e.To) == 0 {
+		return fmt.Errorf("no email recipients configured")
+	}
+	var to []*mail.Address
+	for _, raw := range e.To {
+		addr, err := mail.ParseAddress(raw)
+		if err != nil {
+			return fmt.Errorf("invalid email recipient %q: %w", raw, err)
+		}
+		to = append(to, addr)
+	}
+
+	msg, err := buildEmail(from, to, subject, text, html, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := e.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if e.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("SMTP server %s does not support authentication", e.Host)
+		}
+		if err := client.Auth(smtp.PlainAuth("", e.Username, e.Password, e.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s failed: %w", addr.Address, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the email: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects to the SMTP server with the configured security.
+func (e *EmailNotifier) dial() (*smtp.Client, error) {
+	security := e.Security
+	if security == "" {
+		security = EmailSecurityStartTLS
+	}
+	port := e.Port
+	if port == 0 {
+		port = DefaultSMTPPort(security)
+	}
+	addr := net.JoinHostPort(e.Host, strconv.Itoa(port))
+
+	tlsConfig := e.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = e.Host
+	}
+
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	var err error
+	if security == EmailSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, e.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP handshake with %s failed: %w", addr, err)
+	}
+	if security == EmailSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("SMTP server %s does not support STARTTLS (set the security to tls or none)", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("STARTTLS with %s failed: %w", addr, err)
+		}
+	}
+	return client, nil
+}
+
+// buildEmail renders a multipart/alternative message with plain-text and HTML bodies.
+func buildEmail(from *mail.Address, to []*mail.Address, subject, text, html string, now time.Time) ([]byte, error) {
+	var recipients []string
+	for _, addr := range to {
+		recipients = append(recipients, addr.String())
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", messageID(from))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp.Close()
+	}
+	parts.Close()
+
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain.
+func messageID(from *mail.Address) string {
+	domain := "prw.local"
+	if _, d, ok := strings.Cut(from.Address, "@"); ok && d != "" {
+		domain = d
+	}
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain)
+}
+
+// emailText renders the plain-text body.
+func emailText(events []*StatusChangeEvent) string {
+	var b strings.Builder
+	for i, event := range events {
+		if i > 0 {
+			b.WriteString("\n----\n\n")
+		}
+		fmt.Fprintf(&b, "%s/%s#%d", event.Owner, event.Repo, event.Number)
+		if event.Title != "" {
+			fmt.Fprintf(&b, ": %s", event.Title)
+		}
+		fmt.Fprintf(&b, "\n%s\n\n", eventHeadline(event))
+		for _, fact := range eventFacts(event) {
+			fmt.Fprintf(&b, "%s: %s\n", fact.Name, fact.Value)
+		}
+		fmt.Fprintf(&b, "Link: %s\n", event.URL())
+		if !event.Timestamp.IsZero() {
+			fmt.Fprintf(&b, "Time: %s\n", event.Timestamp.Format(time.RFC3339))
+		}
+	}
+	return b.String()
+}
+
+// emailColors maps event tones to headline colors in HTML emails.
+var emailColors = map[string]string{
+	toneGood:    "#2ea043",
+	toneBad:     "#cf222e",
+	toneWarning: "#9a6700",
+	toneInfo:    "#0969da",
+	toneNeutral: "#6e7781",
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; color: #1f2328;">
+{{- range .}}
+<div style="margin-bottom: 24px;">
+<p style="margin: 0; color: #59636e;"><a href="{{.URL}}" style="color: #59636e;">{{.Ref}}</a></p>
+{{- if .Title}}
+<h2 style="margin: 4px 0;">{{.Title}}</h2>
+{{- end}}
+<p style="margin: 4px 0; font-weight: bold; color: {{.Color}};">{{.Headline}}</p>
+{{- if .Facts}}
+<table style="border-collapse: collapse; margin: 8px 0;">
+{{- range .Facts}}
+<tr><td style="padding: 2px 12px 2px 0; color: #59636e;">{{.Name}}</td><td style="padding: 2px 0;">{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<p style="margin: 8px 0;"><a href="{{.URL}}">Open pull request</a>{{if .Time}} <span style="color: #59636e;">· {{.Time}}</span>{{end}}</p>
+</div>
+{{- end}}
+</body>
+</html>
+`))
+
+// emailHTML renders the HTML body.
+func emailHTML(events []*StatusChangeEvent) (string, error) {
+	type emailEvent struct {
+		Ref, Title, Headline, URL, Time string
+		Color                           template.CSS
+		Facts                           []eventFact
+	}
+	var data []emailEvent
+	for _, event := range events {
+		item := emailEvent{
+			Ref:      fmt.Sprintf("%s/%s#%d", event.Owner, event.Repo, event.Number),
+			Title:    event.Title,
+			Headline: eventHeadline(event),
+			URL:      event.URL(),
+			Color:    template.CSS(emailColors[eventTone(event)]),
+			Facts:    eventFacts(event),
+		}
+		if !event.Timestamp.IsZero() {
+			item.Time = event.Timestamp.Format(time.RFC3339)
+		}
+		data = append(data, item)
+	}
+
+	var b strings.Builder
+	if err := emailTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render email: %w", err)
+	}
+	return b.String(), nil
+}
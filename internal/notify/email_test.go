@@ -0,0 +1,371 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+// smtpStandIn is a minimal in-process SMTP server that records the messages it receives.
+type smtpStandIn struct {
+	ln          net.Listener
+	tlsConfig   *tls.Config
+	startTLS    bool // advertise STARTTLS on plain connections
+	implicitTLS bool // expect TLS from the first byte
+
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	auth string // decoded AUTH PLAIN credentials
+	tls  bool
+	data string
+}
+
+// newSMTPStandIn starts a stand-in and returns it with a client TLS config that trusts it.
+func newSMTPStandIn(t *testing.T, startTLS, implicitTLS bool) (*smtpStandIn, *tls.Config) {
+	t.Helper()
+	// Borrow the test certificate of httptest, which is valid for 127.0.0.1.
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+	clientTLS := ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpStandIn{ln: ln, tlsConfig: ts.TLS, startTLS: startTLS, implicitTLS: implicitTLS}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s, clientTLS
+}
+
+func (s *smtpStandIn) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) received() []smtpMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...)
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	isTLS := false
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+		isTLS = true
+	}
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 standin ESMTP")
+
+	msg := smtpMessage{}
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-standin")
+			if s.startTLS && !isTLS {
+				tp.PrintfLine("250-STARTTLS")
+			}
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			tp.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, isTLS = tlsConn, true
+			tp = textproto.NewConn(conn)
+		case "AUTH":
+			_, initial, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(initial)
+			msg.auth = string(decoded)
+			tp.PrintfLine("235 authenticated")
+		case "MAIL":
+			msg.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			msg.to = append(msg.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data, msg.tls = string(data), isTLS
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			msg = smtpMessage{}
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+// parseEmail returns the decoded subject and the plain-text and HTML bodies of a message.
+func parseEmail(t *testing.T, data string) (subject, text, html string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatalf("failed to parse email: %v", err)
+	}
+	subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q (%v)", mediaType, err)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		body, _ := io.ReadAll(part) // quoted-printable is decoded by the reader
+		switch {
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain"):
+			text = string(body)
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/html"):
+			html = string(body)
+		}
+	}
+	return subject, text, html
+}
+
+func TestEmailNotifierSTARTTLS(t *testing.T) {
+	server, clientTLS := newSMTPStandIn(t, true, false)
+
+	notifier := NewEmailNotifier("127.0.0.1", server.port(), "prw <prw@example.com>", []string{"dev@example.com", "Lead <lead@example.com>"})
+	notifier.Username = "prw"
+	notifier.Password = "secret"
+	notifier.TLSConfig = clientTLS
+
+	event := &StatusChangeEvent{
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        42,
+		Title:         "Fix <script> handling",
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		SHA:           "abc123def456",
+		Checks:        []github.Check{{Name: "e2e-tests", State: "failure"}},
+		Timestamp:     time.Date(2025, 12, 6, 10, 32, 15, 0, time.UTC),
+	}
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(messages))
+	}
+	msg := messages[0]
+	if !msg.tls {
+		t.Error("expected the email to be sent after STARTTLS")
+	}
+	if msg.auth != "\x00prw\x00secret" {
+		t.Errorf("unexpected AUTH PLAIN credentials %q", msg.auth)
+	}
+	if msg.from != "prw@example.com" || len(msg.to) != 2 || msg.to[1] != "lead@example.com" {
+		t.Errorf("unexpected envelope: from %q to %v", msg.from, msg.to)
+	}
+
+	subject, text, html := parseEmail(t, msg.data)
+	if subject != "[prw] owner/repo#42: CI failed (pending → failure)" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	for _, want := range []string{"owner/repo#42: Fix <script> handling", "Failing checks: e2e-tests", "Link: https://github.com/owner/repo/pull/42"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text body missing %q:\n%s", want, text)
+		}
+	}
+	for _, want := range []string{"Fix &lt;script&gt; handling", `href="https://github.com/owner/repo/pull/42"`, "color: #cf222e;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML body missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("expected the PR title to be escaped in the HTML body")
+	}
+}
+
+func TestEmailNotifierImplicitTLS(t *testing.T) {
+	server, clientTLS := newSMTPStandIn(t, false, true)
+
+	notifier := NewEmailNotifier("127.0.0.1", server.port(), "prw@example.com", []string{"dev@example.com"})
+	notifier.Security = EmailSecurityTLS
+	notifier.TLSConfig = clientTLS
+
+	if err := notifier.Notify(&StatusChangeEvent{Type: EventMerged, Owner: "o", Repo: "r", Number: 1}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	messages := server.received()
+	if len(messages) != 1 || !messages[0].tls || messages[0].auth != "" {
+		t.Fatalf("expected one unauthenticated email over TLS, got %+v", messages)
+	}
+}
+
+func TestEmailNotifierRequiresSTARTTLS(t *testing.T) {
+	server, _ := newSMTPStandIn(t, false, false)
+
+	notifier := NewEmailNotifier("127.0.0.1", server.port(), "prw@example.com", []string{"dev@example.com"})
+	err := notifier.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, CurrentState: "success"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("expected a STARTTLS error, got %v", err)
+	}
+	if len(server.received()) != 0 {
+		t.Error("expected no email to be sent in plain text")
+	}
+
+	notifier.Security = EmailSecurityNone
+	if err := notifier.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, CurrentState: "success"}); err != nil {
+		t.Fatalf("Notify without encryption failed: %v", err)
+	}
+	if len(server.received()) != 1 {
+		t.Error("expected the email to be sent without encryption when configured")
+	}
+}
+
+func TestEmailNotifierDigest(t *testing.T) {
+	server, _ := newSMTPStandIn(t, false, false)
+
+	notifier := NewEmailNotifier("127.0.0.1", server.port(), "prw@example.com", []string{"dev@example.com"})
+	notifier.Security = EmailSecurityNone
+	notifier.Digest = 50 * time.Millisecond
+
+	for i := 1; i <= 2; i++ {
+		if err := notifier.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: i, PreviousState: "pending", CurrentState: "success"}); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+	if len(server.received()) != 0 {
+		t.Fatal("expected events to be batched until the digest window ends")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected one digest email, got %d", len(messages))
+	}
+	subject, text, _ := parseEmail(t, messages[0].data)
+	if subject != "[prw] 2 PR updates" {
+		t.Errorf("unexpected digest subject %q", subject)
+	}
+	if !strings.Contains(text, "o/r#1") || !strings.Contains(text, "o/r#2") {
+		t.Errorf("expected both events in the digest:\n%s", text)
+	}
+}
+
+func TestFlushSendsDigestThroughNotifierChain(t *testing.T) {
+	server, _ := newSMTPStandIn(t, false, false)
+
+	email := NewEmailNotifier("127.0.0.1", server.port(), "prw@example.com", []string{"dev@example.com"})
+	email.Security = EmailSecurityNone
+	email.Digest = time.Hour
+
+	// The chain prw builds with routes and quiet hours outside their window
+	quiet := NewQuietNotifier(email, &fakeSchedule{}, false)
+	router := NewRouter([]Route{{Notifiers: []string{"email"}}}, map[string]Notifier{"email": quiet})
+	chain := NewMultiNotifier(router, &mockNotifier{})
+
+	if err := chain.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, PreviousState: "pending", CurrentState: "failure"}); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if len(server.received()) != 0 {
+		t.Fatal("expected the event to wait for the digest window")
+	}
+
+	if err := Flush(chain); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected the digest to be sent on flush, got %d emails", len(messages))
+	}
+	if subject, _, _ := parseEmail(t, messages[0].data); subject != "[prw] 1 PR update" {
+		t.Errorf("unexpected digest subject %q", subject)
+	}
+
+	if err := Flush(chain); err != nil || len(server.received()) != 1 {
+		t.Errorf("expected flushing an empty digest to send nothing, got %v and %d emails", err, len(server.received()))
+	}
+}
+
+func TestEmailNotifierTemplate(t *testing.T) {
+	server, _ := newSMTPStandIn(t, false, false)
+
+	notifier := NewEmailNotifier("127.0.0.1", server.port(), "prw@example.com", []string{"dev@example.com"})
+	notifier.Security = EmailSecurityNone
+	tmpl, err := NewMessageTemplate("email", map[string]string{
+		EventStatusChange: "CI {{.CurrentState}} on {{.Repo}}#{{.Number}}\n\n{{.Title}}\n{{prURL .}}",
+	})
+	if err != nil {
+		t.Fatalf("NewMessageTemplate failed: %v", err)
+	}
+	notifier.Template = tmpl
+
+	if err := notifier.Notify(SampleEvent(EventStatusChange)); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(messages))
+	}
+	subject, text, html := parseEmail(t, messages[0].data)
+	if subject != "CI failure on api#1347" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if text != "Add rate limiting to the public API\nhttps://github.com/octo-org/api/pull/1347" {
+		t.Errorf("unexpected text body %q", text)
+	}
+	if !strings.HasPrefix(html, "<pre>Add rate limiting") {
+		t.Errorf("unexpected HTML body %q", html)
+	}
+}
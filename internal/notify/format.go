@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Webhook formats select the payload posted to a webhook URL.
+const (
+	WebhookFormatAuto    = "auto" // detect from the URL's host, falling back to json
+	WebhookFormatJSON    = "json" // prw's WebhookPayload
+	WebhookFormatSlack   = "slack"
+	WebhookFormatDiscord = "discord"
+	WebhookFormatTeams   = "teams"
+)
+
+// IsValidWebhookFormat reports whether format is a known webhook format.
+func IsValidWebhookFormat(format string) bool {
+	switch format {
+	case "", WebhookFormatAuto, WebhookFormatJSON, WebhookFormatSlack, WebhookFormatDiscord, WebhookFormatTeams:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResolveWebhookFormat returns the format to use for a webhook URL. An empty or
+// auto format is detected from the host: Slack incoming webhooks, Discord
+// webhooks, and Microsoft Teams incoming webhooks or workflows get their own
+// format, and everything else gets prw's JSON payload.
+func ResolveWebhookFormat(rawURL, format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "" && format != WebhookFormatAuto {
+		return format
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return WebhookFormatJSON
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "hooks.slack.com":
+		return WebhookFormatSlack
+	case (host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com")) &&
+		strings.HasPrefix(u.Path, "/api/webhooks/"):
+		return WebhookFormatDiscord
+	case strings.HasSuffix(host, ".webhook.office.com") || host == "outlook.office.com" ||
+		strings.HasSuffix(host, ".logic.azure.com") || strings.HasSuffix(host, ".powerplatform.com"):
+		return WebhookFormatTeams
+	default:
+		return WebhookFormatJSON
+	}
+}
+
+// eventHeadline describes an event in a few words, e.g. "CI failed (pending → failure)".
+func eventHeadline(event *StatusChangeEvent) string {
+	switch event.EventType() {
+	case EventMerged:
+		return "Merged"
+	case EventClosed:
+		return "Closed without merging"
+	case EventApproved:
+		return "Approved"
+	case EventChangesRequested:
+		return "Changes requested"
+	case EventReviewRequested:
+		return "Review requested"
+	case EventReadyToMerge:
+		return "Ready to merge"
+	case EventPushed:
+		return "New commits pushed"
+	case EventForcePushed:
+		return "Force-pushed"
+	case EventStuck:
+		return "CI stuck"
+	}
+
+	headline := "CI " + event.CurrentState
+	switch event.CurrentState {
+	case "success":
+		headline = "CI passed"
+	case "failure":
+		headline = "CI failed"
+	case "error":
+		headline = "CI errored"
+	case "pending":
+		headline = "CI running"
+	}
+	if event.PreviousState != "" {
+		headline += fmt.Sprintf(" (%s → %s)", event.PreviousState, event.CurrentState)
+	}
+	return headline
+}
+
+// eventFact is a labeled detail of an event, shown as an embed field or card fact.
+type eventFact struct {
+	Name  string
+	Value string
+}
+
+// eventFacts returns the details of an event worth showing besides its headline.
+func eventFacts(event *StatusChangeEvent) []eventFact {
+	var facts []eventFact
+	if failing := FailingChecks(event.Checks); len(failing) > 0 {
+		facts = append(facts, eventFact{"Failing checks", strings.Join(failing, ", ")})
+	}
+	if len(event.FlakyChecks) > 0 {
+		facts = append(facts, eventFact{"Likely flaky", strings.Join(event.FlakyChecks, ", ")})
+	}
+	if len(event.Reviewers) > 0 {
+		facts = append(facts, eventFact{"Reviewers", strings.Join(event.Reviewers, ", ")})
+	}
+	if event.IsPush() {
+		facts = append(facts, eventFact{"Head", fmt.Sprintf("%s → %s", shortSHA(event.PreviousSHA), shortSHA(event.SHA))})
+		if commits := commitSummary(event); commits != "" {
+			facts = append(facts, eventFact{"Commits", commits})
+		}
+	}
+	if event.Duration > 0 {
+		name := "CI duration"
+		if event.EventType() == EventStuck {
+			name = "Pending for"
+		}
+		facts = append(facts, eventFact{name, FormatDuration(event.Duration)})
+	}
+	return facts
+}
+
+// summaryTitle is the title of a summary of events held during quiet hours.
+func summaryTitle(events []*StatusChangeEvent) string {
+	if len(events) == 1 {
+		return "1 notification during quiet hours"
+	}
+	return fmt.Sprintf("%d notifications during quiet hours", len(events))
+}
+
+// Tones of an event, mapped to colors by the Discord and Teams notifiers.
+const (
+	toneGood    = "good"
+	toneBad     = "bad"
+	toneWarning = "warning"
+	toneInfo    = "info"
+	toneNeutral = "neutral"
+)
+
+// eventTone classifies an event as good, bad, warning, informational, or neutral news.
+func eventTone(event *StatusChangeEvent) string {
+	switch event.EventType() {
+	case EventMerged, EventApproved, EventReadyToMerge:
+		return toneGood
+	case EventClosed:
+		return toneNeutral
+	case EventChangesRequested, EventForcePushed, EventStuck:
+		return toneWarning
+	case EventReviewRequested, EventPushed:
+		return toneInfo
+	}
+
+	switch event.CurrentState {
+	case "success":
+		return toneGood
+	case "failure", "error":
+		return toneBad
+	case "pending":
+		return toneWarning
+	default:
+		return toneNeutral
+	}
+}
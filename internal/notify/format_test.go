@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// capturePost runs send against a test server and returns the indented JSON body it posted.
+func capturePost(t *testing.T, send func(url string) error) []byte {
+	t.Helper()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected Content-Type application/json, got %s", ct)
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := send(server.URL); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		t.Fatalf("posted body is not JSON: %v\n%s", err, body)
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes()
+}
+
+// assertGolden compares got with testdata/name, rewriting the file with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("payload does not match %s (run go test -update to accept it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// goldenEvents are the events rendered by the golden payload tests.
+var goldenEvents = map[string]*StatusChangeEvent{
+	"failure": {
+		Owner:         "kubernetes",
+		Repo:          "kubernetes",
+		Number:        12345,
+		Title:         "Fix controller race condition",
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		SHA:           "abc123def456",
+		Checks: []github.Check{
+			{Name: "unit-tests", Source: github.CheckSourceCheckRun, State: "success"},
+			{Name: "e2e-tests", Source: github.CheckSourceCheckRun, State: "failure"},
+		},
+		FlakyChecks: []string{"e2e-tests"},
+		Duration:    754 * time.Second,
+		Timestamp:   time.Date(2025, 12, 6, 10, 32, 15, 0, time.UTC),
+	},
+	"force_pushed": {
+		Type:          EventForcePushed,
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        7,
+		PreviousState: "success",
+		CurrentState:  "pending",
+		PreviousSHA:   "1111111aaaaaaa",
+		SHA:           "2222222bbbbbbb",
+		Commits:       2,
+		Authors:       []string{"alice", "bob"},
+		Timestamp:     time.Date(2025, 12, 6, 11, 0, 0, 0, time.UTC),
+	},
+	"merged": {
+		Type:      EventMerged,
+		Host:      "github.example.com",
+		Owner:     "owner",
+		Repo:      "repo",
+		Number:    8,
+		Title:     "Add feature",
+		SHA:       "3333333ccccccc",
+		Timestamp: time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC),
+	},
+}
+
+func goldenSummary() []*StatusChangeEvent {
+	return []*StatusChangeEvent{goldenEvents["failure"], goldenEvents["force_pushed"], goldenEvents["merged"]}
+}
+
+func TestResolveWebhookFormat(t *testing.T) {
+	tests := []struct {
+		url, format, want string
+	}{
+		{"https://hooks.slack.com/services/T/B/X", "", WebhookFormatSlack},
+		{"https://discord.com/api/webhooks/1/abc", "auto", WebhookFormatDiscord},
+		{"https://discordapp.com/api/webhooks/1/abc", "", WebhookFormatDiscord},
+		{"https://canary.discord.com/api/webhooks/1/abc", "", WebhookFormatDiscord},
+		{"https://discord.com/channels/1/2", "", WebhookFormatJSON},
+		{"https://contoso.webhook.office.com/webhookb2/abc", "", WebhookFormatTeams},
+		{"https://prod-01.westus.logic.azure.com/workflows/abc", "", WebhookFormatTeams},
+		{"https://example.com/hook", "", WebhookFormatJSON},
+		{"https://hooks.slack.com/services/T/B/X", "json", WebhookFormatJSON},
+		{"https://example.com/hook", "Teams", WebhookFormatTeams},
+	}
+	for _, tt := range tests {
+		if got := ResolveWebhookFormat(tt.url, tt.format); got != tt.want {
+			t.Errorf("ResolveWebhookFormat(%q, %q) = %q, want %q", tt.url, tt.format, got, tt.want)
+		}
+	}
+
+	if !IsValidWebhookFormat("discord") || IsValidWebhookFormat("irc") {
+		t.Error("unexpected IsValidWebhookFormat result")
+	}
+}
+
+func TestWebhookNotifiersTimeOut(t *testing.T) {
+	clients := map[string]*http.Client{
+		"discord": NewDiscordNotifier("https://discord.com/api/webhooks/1/x").HTTPClient,
+		"teams":   NewTeamsNotifier("https://example.webhook.office.com/x").HTTPClient,
+		"json":    NewWebhookNotifier("https://hooks.example.com/x").HTTPClient,
+	}
+	for name, client := range clients {
+		if client == http.DefaultClient || client.Timeout != httpTimeout {
+			t.Errorf("%s: expected a client with a %s timeout, got %+v", name, httpTimeout, client)
+		}
+	}
+}
@@ -7,28 +7,157 @@ import (
 	"net/http"
 	"os/exec"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/retry"
+)
+
+// Event types carried by StatusChangeEvent.
+const (
+	EventStatusChange = "status_change"
+	EventMerged       = "merged"
+	EventClosed       = "closed"
+
+	// Review events carry the previous and current review decision as states.
+	EventApproved         = "approved"
+	EventChangesRequested = "changes_requested"
+	EventReviewRequested  = "review_requested"
+
+	// EventReadyToMerge fires once when a PR meets every requirement of its
+	// merge rule; its states are ReadyStateNotReady and ReadyStateReady.
+	EventReadyToMerge = "ready_to_merge"
+
+	// Push events fire when the head commit of a PR changes. Their states are
+	// the CI state before the push and the CI state of the new commit.
+	EventPushed      = "pushed"
+	EventForcePushed = "force_pushed"
+
+	// EventStuck fires once when CI on the head commit stays pending longer
+	// than the configured threshold; its Duration is the time spent pending.
+	EventStuck = "stuck"
+
+	// EventAutoRerun is recorded in the history when failed flaky checks are
+	// re-run automatically. It is not sent to notifiers.
+	EventAutoRerun = "auto_rerun"
+)
+
+// States carried by ready_to_merge events.
+const (
+	ReadyStateNotReady = "not_ready"
+	ReadyStateReady    = "ready"
 )
 
 // StatusChangeEvent represents a CI status change for a PR.
+// Type distinguishes CI transitions from lifecycle events such as merges;
+// an empty Type is treated as a status change.
 type StatusChangeEvent struct {
+	Type          string
+	Host          string // empty means github.com
 	Owner         string
 	Repo          string
 	Number        int
 	Title         string
+	Author        string   // login of the PR author, if known
+	Labels        []string // labels of the PR
 	PreviousState string
 	CurrentState  string
 	SHA           string
+	PreviousSHA   string   // head commit before a push, for push events
+	Commits       int      // number of new commits, for push events; 0 if unknown
+	Authors       []string // authors of the new commits, for push events
+	CompareFailed bool     // the push could not be compared, so whether it was forced is unknown
+	Checks        []github.Check
+	Reviewers     []string // newly requested reviewers, for review_requested events
+	Logs          []LogExcerpt
+	FlakyChecks   []string      // failing checks that are likely flaky
+	Duration      time.Duration // time CI spent pending, for CI results and stuck events; 0 if unknown
 	Timestamp     time.Time
 }
 
+// LogExcerpt is the tail of a failed CI job's log, attached to failure events
+// when log excerpts are enabled.
+type LogExcerpt struct {
+	Check string   `json:"check"`
+	Step  string   `json:"step,omitempty"` // the step that failed, if known
+	URL   string   `json:"url,omitempty"`
+	Lines []string `json:"lines"`
+}
+
+// IsReview reports whether the event is about reviews rather than CI or the PR lifecycle.
+func (e *StatusChangeEvent) IsReview() bool {
+	switch e.EventType() {
+	case EventApproved, EventChangesRequested, EventReviewRequested:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPush reports whether the event is about new commits on the PR.
+func (e *StatusChangeEvent) IsPush() bool {
+	return e.EventType() == EventPushed || e.EventType() == EventForcePushed
+}
+
+// IsFailure reports whether the event is CI turning failed.
+func (e *StatusChangeEvent) IsFailure() bool {
+	return e.EventType() == EventStatusChange && (e.CurrentState == "failure" || e.CurrentState == "error")
+}
+
+// LikelyFlaky reports whether every failing check of the event is likely flaky.
+func (e *StatusChangeEvent) LikelyFlaky() bool {
+	failing := FailingChecks(e.Checks)
+	if len(failing) == 0 {
+		return false
+	}
+	for _, name := range failing {
+		flaky := false
+		for _, f := range e.FlakyChecks {
+			if f == name {
+				flaky = true
+				break
+			}
+		}
+		if !flaky {
+			return false
+		}
+	}
+	return true
+}
+
+// EventType returns the event type, defaulting to a status change.
+func (e *StatusChangeEvent) EventType() string {
+	if e.Type == "" {
+		return EventStatusChange
+	}
+	return e.Type
+}
+
+// URL returns the web URL of the PR.
+func (e *StatusChangeEvent) URL() string {
+	return github.FormatHostPRURL(e.Host, e.Owner, e.Repo, e.Number)
+}
+
 // Notifier sends notifications about status changes.
 type Notifier interface {
 	Notify(event *StatusChangeEvent) error
 }
 
+// Flusher is implemented by notifiers that hold events back, such as email
+// digests. Flush delivers the held events right away, e.g. before prw exits.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush flushes n if it holds events back.
+func Flush(n Notifier) error {
+	if f, ok := n.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
 // MultiNotifier combines multiple notifiers.
 type MultiNotifier struct {
 	notifiers []Notifier
@@ -39,18 +168,34 @@ func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
 	return &MultiNotifier{notifiers: notifiers}
 }
 
-// Notify sends the event to all notifiers.
+// Notify sends the event to all notifiers. A failing notifier does not keep
+// the event from the others, such as the dashboard stream; the first error is
+// returned.
 func (m *MultiNotifier) Notify(event *StatusChangeEvent) error {
+	var firstErr error
 	for _, n := range m.notifiers {
-		if err := n.Notify(event); err != nil {
-			return err
+		if err := n.Notify(event); err != nil && firstErr == nil {
+			firstErr = err
 		}
 	}
-	return nil
+	return firstErr
+}
+
+// Flush flushes every notifier that holds events back; the first error is returned.
+func (m *MultiNotifier) Flush() error {
+	var firstErr error
+	for _, n := range m.notifiers {
+		if err := Flush(n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
 // ConsoleNotifier prints notifications to stdout.
-type ConsoleNotifier struct{}
+type ConsoleNotifier struct {
+	Template *MessageTemplate // replaces the built-in output for the event types it covers
+}
 
 // NewConsoleNotifier creates a console notifier.
 func NewConsoleNotifier() *ConsoleNotifier {
@@ -59,46 +204,176 @@ func NewConsoleNotifier() *ConsoleNotifier {
 
 // Notify prints the status change to console.
 func (c *ConsoleNotifier) Notify(event *StatusChangeEvent) error {
-	prURL := github.FormatPRURL(event.Owner, event.Repo, event.Number)
+	if text, ok, err := c.Template.Render(event); ok {
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n%s\n\n", strings.TrimRight(text, "\n"))
+		return nil
+	}
 
-	fmt.Printf("\n🔔 Status Change Detected!\n")
+	prURL := event.URL()
+
+	switch event.EventType() {
+	case EventMerged:
+		fmt.Printf("\n🎉 PR Merged!\n")
+	case EventClosed:
+		fmt.Printf("\n🚫 PR Closed!\n")
+	case EventApproved:
+		fmt.Printf("\n✅ PR Approved!\n")
+	case EventChangesRequested:
+		fmt.Printf("\n✋ Changes Requested!\n")
+	case EventReviewRequested:
+		fmt.Printf("\n👀 Review Requested!\n")
+	case EventReadyToMerge:
+		fmt.Printf("\n🚀 Ready to Merge!\n")
+	case EventPushed:
+		fmt.Printf("\n⬆️  New Commits Pushed!\n")
+	case EventForcePushed:
+		fmt.Printf("\n⚠️  Force-Pushed!\n")
+	case EventStuck:
+		fmt.Printf("\n⏳ CI Stuck!\n")
+	default:
+		fmt.Printf("\n🔔 Status Change Detected!\n")
+	}
 	fmt.Printf("   PR: %s/%s#%d\n", event.Owner, event.Repo, event.Number)
 	if event.Title != "" {
 		fmt.Printf("   Title: %s\n", event.Title)
 	}
-	fmt.Printf("   Status: %s → %s\n", event.PreviousState, event.CurrentState)
+	if event.EventType() == EventStatusChange {
+		fmt.Printf("   Status: %s → %s\n", event.PreviousState, event.CurrentState)
+	}
+	if event.IsReview() {
+		fmt.Printf("   Review: %s → %s\n", event.PreviousState, event.CurrentState)
+	}
+	if event.Duration > 0 {
+		if event.EventType() == EventStuck {
+			fmt.Printf("   Pending for: %s\n", FormatDuration(event.Duration))
+		} else {
+			fmt.Printf("   CI duration: %s\n", FormatDuration(event.Duration))
+		}
+	}
+	if len(event.Reviewers) > 0 {
+		fmt.Printf("   Reviewers: %s\n", strings.Join(event.Reviewers, ", "))
+	}
+	if event.IsPush() {
+		fmt.Printf("   Head: %s → %s\n", shortSHA(event.PreviousSHA), shortSHA(event.SHA))
+		if commits := commitSummary(event); commits != "" {
+			fmt.Printf("   Commits: %s\n", commits)
+		}
+	}
+	if failing := FailingChecks(event.Checks); len(failing) > 0 {
+		fmt.Printf("   Failing: %s\n", strings.Join(failing, ", "))
+	}
+	if len(event.FlakyChecks) > 0 {
+		fmt.Printf("   Likely flaky: %s\n", strings.Join(event.FlakyChecks, ", "))
+	}
+	for _, excerpt := range event.Logs {
+		name := excerpt.Check
+		if excerpt.Step != "" {
+			name += " / " + excerpt.Step
+		}
+		fmt.Printf("   Log (%s):\n", name)
+		for _, line := range excerpt.Lines {
+			fmt.Printf("     %s\n", line)
+		}
+	}
 	fmt.Printf("   Link: %s\n", prURL)
 	fmt.Printf("   Time: %s\n\n", event.Timestamp.Format(time.RFC3339))
 
 	return nil
 }
 
+// commitSummary describes the new commits of a push event, e.g. "3 by alice, bob".
+func commitSummary(event *StatusChangeEvent) string {
+	if event.CompareFailed {
+		return "unknown (compare failed)"
+	}
+	var summary string
+	if event.Commits > 0 {
+		summary = fmt.Sprintf("%d", event.Commits)
+	}
+	if len(event.Authors) > 0 {
+		summary = strings.TrimSpace(summary + " by " + strings.Join(event.Authors, ", "))
+	}
+	return summary
+}
+
+// FormatDuration formats a CI duration rounded to the second, e.g. "1h2m3s".
+func FormatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// FailingChecks returns the names of checks that ended in failure or error.
+func FailingChecks(checks []github.Check) []string {
+	var names []string
+	for _, c := range checks {
+		if c.State == "failure" || c.State == "error" {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// httpTimeout bounds each request of the notifiers that post over HTTP, like
+// the GitHub client's timeout, so an endpoint that never answers can't stall
+// the watcher.
+const httpTimeout = 15 * time.Second
+
+// newHTTPClient returns the client notifiers post with by default.
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: httpTimeout}
+}
+
 // WebhookNotifier sends notifications to a webhook URL.
 type WebhookNotifier struct {
 	URL        string
 	HTTPClient *http.Client
+	Retry      retry.Policy
+	Template   *MessageTemplate // renders the request body instead of WebhookPayload
 }
 
 // NewWebhookNotifier creates a webhook notifier.
 func NewWebhookNotifier(url string) *WebhookNotifier {
 	return &WebhookNotifier{
 		URL:        url,
-		HTTPClient: http.DefaultClient,
+		HTTPClient: newHTTPClient(),
+		Retry:      retry.DefaultPolicy(),
 	}
 }
 
 // WebhookPayload is the JSON structure sent to the webhook.
 type WebhookPayload struct {
-	Type          string    `json:"type"`
-	Owner         string    `json:"owner"`
-	Repo          string    `json:"repo"`
-	PRNumber      int       `json:"pr_number"`
-	Title         string    `json:"title,omitempty"`
-	PreviousState string    `json:"previous_state"`
-	CurrentState  string    `json:"current_state"`
-	SHA           string    `json:"sha"`
-	URL           string    `json:"url"`
-	Timestamp     time.Time `json:"timestamp"`
+	Type            string         `json:"type"`
+	Owner           string         `json:"owner"`
+	Repo            string         `json:"repo"`
+	PRNumber        int            `json:"pr_number"`
+	Title           string         `json:"title,omitempty"`
+	Author          string         `json:"author,omitempty"`
+	Labels          []string       `json:"labels,omitempty"`
+	PreviousState   string         `json:"previous_state"`
+	CurrentState    string         `json:"current_state"`
+	SHA             string         `json:"sha"`
+	PreviousSHA     string         `json:"previous_sha,omitempty"`
+	Commits         int            `json:"commits,omitempty"`
+	Authors         []string       `json:"authors,omitempty"`
+	CompareFailed   bool           `json:"compare_failed,omitempty"`
+	URL             string         `json:"url"`
+	Checks          []github.Check `json:"checks,omitempty"`
+	FailingChecks   []string       `json:"failing_checks,omitempty"`
+	Logs            []LogExcerpt   `json:"logs,omitempty"`
+	LikelyFlaky     bool           `json:"likely_flaky,omitempty"`
+	FlakyChecks     []string       `json:"flaky_checks,omitempty"`
+	DurationSeconds int64          `json:"duration_seconds,omitempty"`
+	Reviewers       []string       `json:"reviewers,omitempty"`
+	Timestamp       time.Time      `json:"timestamp"`
 }
 
 // Notify sends the status change to the webhook.
@@ -106,33 +381,76 @@ func (w *WebhookNotifier) Notify(event *StatusChangeEvent) error {
 	if w.URL == "" {
 		return nil
 	}
+	if sent, err := postTemplate(w.HTTPClient, w.Retry, w.URL, w.Template, event); sent {
+		return err
+	}
+
+	return w.post(newWebhookPayload(event))
+}
 
-	payload := WebhookPayload{
-		Type:          "pr_status_change",
-		Owner:         event.Owner,
-		Repo:          event.Repo,
-		PRNumber:      event.Number,
-		Title:         event.Title,
-		PreviousState: event.PreviousState,
-		CurrentState:  event.CurrentState,
-		SHA:           event.SHA,
-		URL:           github.FormatPRURL(event.Owner, event.Repo, event.Number),
-		Timestamp:     event.Timestamp,
+// newWebhookPayload converts an event to its webhook payload.
+func newWebhookPayload(event *StatusChangeEvent) WebhookPayload {
+	return WebhookPayload{
+		Type:            webhookType(event),
+		Owner:           event.Owner,
+		Repo:            event.Repo,
+		PRNumber:        event.Number,
+		Title:           event.Title,
+		Author:          event.Author,
+		Labels:          event.Labels,
+		PreviousState:   event.PreviousState,
+		CurrentState:    event.CurrentState,
+		SHA:             event.SHA,
+		PreviousSHA:     event.PreviousSHA,
+		Commits:         event.Commits,
+		Authors:         event.Authors,
+		CompareFailed:   event.CompareFailed,
+		URL:             event.URL(),
+		Checks:          event.Checks,
+		FailingChecks:   FailingChecks(event.Checks),
+		Logs:            event.Logs,
+		LikelyFlaky:     event.LikelyFlaky(),
+		FlakyChecks:     event.FlakyChecks,
+		DurationSeconds: int64(event.Duration.Seconds()),
+		Reviewers:       event.Reviewers,
+		Timestamp:       event.Timestamp,
 	}
+}
+
+// post sends a JSON payload to the webhook URL.
+func (w *WebhookNotifier) post(payload interface{}) error {
+	return postJSON(w.HTTPClient, w.Retry, w.URL, payload)
+}
 
+// postJSON POSTs a JSON payload to a webhook URL, retrying transient failures.
+func postJSON(client *http.Client, policy retry.Policy, url string, payload interface{}) error {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
+	return postBody(client, policy, url, data)
+}
 
-	req, err := http.NewRequest("POST", w.URL, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("failed to create webhook request: %w", err)
+// postTemplate POSTs the event rendered with tmpl as the request body. It
+// reports false without sending if tmpl has no template for the event.
+func postTemplate(client *http.Client, policy retry.Policy, url string, tmpl *MessageTemplate, event *StatusChangeEvent) (bool, error) {
+	body, ok, err := tmpl.Render(event)
+	if !ok || err != nil {
+		return ok, err
 	}
+	return true, postBody(client, policy, url, []byte(body))
+}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.HTTPClient.Do(req)
+// postBody POSTs a JSON request body to a webhook URL, retrying transient failures.
+func postBody(client *http.Client, policy retry.Policy, url string, data []byte) error {
+	resp, err := policy.Do(client, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return fmt.Errorf("webhook request failed: %w", err)
 	}
@@ -145,9 +463,39 @@ func (w *WebhookNotifier) Notify(event *StatusChangeEvent) error {
 	return nil
 }
 
+// webhookType returns the payload type for an event.
+func webhookType(event *StatusChangeEvent) string {
+	switch event.EventType() {
+	case EventMerged:
+		return "pr_merged"
+	case EventClosed:
+		return "pr_closed"
+	case EventApproved:
+		return "pr_approved"
+	case EventChangesRequested:
+		return "pr_changes_requested"
+	case EventReviewRequested:
+		return "pr_review_requested"
+	case EventReadyToMerge:
+		return "pr_ready_to_merge"
+	case EventPushed:
+		return "pr_pushed"
+	case EventForcePushed:
+		return "pr_force_pushed"
+	case EventStuck:
+		return "pr_ci_stuck"
+	default:
+		return "pr_status_change"
+	}
+}
+
 // NativeNotifier sends notifications using OS-native notification systems.
 type NativeNotifier struct {
 	enabled bool
+
+	// Template replaces the built-in text for the event types it covers; the
+	// first line of its output is the title and the rest the message.
+	Template *MessageTemplate
 }
 
 // NewNativeNotifier creates a native notifier.
@@ -164,13 +512,63 @@ func (n *NativeNotifier) Notify(event *StatusChangeEvent) error {
 		// Silently skip if not supported - this is expected on unsupported platforms
 		return nil
 	}
+	if text, ok, err := n.Template.Render(event); ok {
+		if err != nil {
+			return err
+		}
+		return n.send(SplitTitle(text))
+	}
 
 	title := fmt.Sprintf("PR Status Change: %s/%s#%d", event.Owner, event.Repo, event.Number)
 	message := fmt.Sprintf("%s → %s", event.PreviousState, event.CurrentState)
+	switch event.EventType() {
+	case EventMerged:
+		title = fmt.Sprintf("PR Merged: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "Pull request was merged"
+	case EventClosed:
+		title = fmt.Sprintf("PR Closed: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "Pull request was closed without merging"
+	case EventApproved:
+		title = fmt.Sprintf("PR Approved: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "Pull request was approved"
+	case EventChangesRequested:
+		title = fmt.Sprintf("Changes Requested: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "A reviewer requested changes"
+	case EventReviewRequested:
+		title = fmt.Sprintf("Review Requested: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "Review requested from " + strings.Join(event.Reviewers, ", ")
+	case EventReadyToMerge:
+		title = fmt.Sprintf("Ready to Merge: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "Pull request is ready to merge"
+	case EventPushed:
+		title = fmt.Sprintf("New Commits: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "New commits were pushed"
+		if event.CompareFailed {
+			message = "New commits were pushed; whether the branch was force-pushed is unknown (compare failed)"
+		} else if commits := commitSummary(event); commits != "" {
+			message = fmt.Sprintf("New commits were pushed (%s)", commits)
+		}
+	case EventForcePushed:
+		title = fmt.Sprintf("Force-Pushed: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "The branch was force-pushed"
+		if commits := commitSummary(event); commits != "" {
+			message = fmt.Sprintf("The branch was force-pushed (%s)", commits)
+		}
+	case EventStuck:
+		title = fmt.Sprintf("CI Stuck: %s/%s#%d", event.Owner, event.Repo, event.Number)
+		message = "CI has been pending for " + FormatDuration(event.Duration)
+	}
+	if event.LikelyFlaky() {
+		message += " (likely flaky)"
+	}
 	if event.Title != "" {
 		message = fmt.Sprintf("%s\n%s", event.Title, message)
 	}
+	return n.send(title, message)
+}
 
+// send shows a notification with the platform's notification tool.
+func (n *NativeNotifier) send(title, message string) error {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "darwin":
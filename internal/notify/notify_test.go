@@ -7,8 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/retry"
 )
 
 func TestConsoleNotifier(t *testing.T) {
@@ -117,6 +121,7 @@ func TestWebhookNotifierServerError(t *testing.T) {
 	defer server.Close()
 
 	notifier := NewWebhookNotifier(server.URL)
+	notifier.Retry = retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond}
 
 	event := &StatusChangeEvent{
 		Owner:         "owner",
@@ -132,6 +137,47 @@ func TestWebhookNotifierServerError(t *testing.T) {
 	}
 }
 
+func TestWebhookNotifierRetries(t *testing.T) {
+	var calls int32
+	var lastBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastBody, _ = io.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	notifier.Retry = retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	event := &StatusChangeEvent{
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        123,
+		PreviousState: "pending",
+		CurrentState:  "failure",
+	}
+
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+
+	// The payload must be re-sent in full on every attempt
+	var payload WebhookPayload
+	if err := json.Unmarshal(lastBody, &payload); err != nil {
+		t.Fatalf("retried request had invalid body: %v", err)
+	}
+	if payload.PRNumber != 123 || payload.CurrentState != "failure" {
+		t.Errorf("unexpected retried payload: %+v", payload)
+	}
+}
+
 func TestMultiNotifier(t *testing.T) {
 	mock1 := &mockNotifier{}
 	mock2 := &mockNotifier{}
@@ -189,6 +235,19 @@ func TestMultiNotifierError(t *testing.T) {
 	}
 }
 
+func TestMultiNotifierContinuesAfterError(t *testing.T) {
+	failing := &mockNotifier{err: fmt.Errorf("notification failed")}
+	next := &mockNotifier{}
+	multi := NewMultiNotifier(failing, next)
+
+	if err := multi.Notify(&StatusChangeEvent{CurrentState: "failure"}); err == nil || err.Error() != "notification failed" {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if len(next.events) != 1 {
+		t.Error("expected the event to reach the notifiers after the failing one")
+	}
+}
+
 func TestWebhookNotifierMarshalError(t *testing.T) {
 	// This is hard to test directly, but we can test with invalid timestamp
 	notifier := NewWebhookNotifier("http://example.com")
@@ -309,3 +368,285 @@ func TestNativeNotifierUnsupportedPlatform(t *testing.T) {
 	err := notifier.Notify(event)
 	_ = err
 }
+
+func TestWebhookNotifierIncludesChecks(t *testing.T) {
+	var receivedPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &receivedPayload); err != nil {
+			t.Errorf("failed to parse webhook payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+
+	event := &StatusChangeEvent{
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        123,
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		Checks: []github.Check{
+			{Name: "build", Source: github.CheckSourceCheckRun, State: "success"},
+			{Name: "test", Source: github.CheckSourceCheckRun, State: "failure", URL: "https://github.com/owner/repo/runs/1"},
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+
+	if len(receivedPayload.Checks) != 2 {
+		t.Fatalf("expected 2 checks in payload, got %d", len(receivedPayload.Checks))
+	}
+	if receivedPayload.Checks[1].Name != "test" || receivedPayload.Checks[1].State != "failure" {
+		t.Errorf("unexpected check in payload: %+v", receivedPayload.Checks[1])
+	}
+}
+
+func TestFailingChecks(t *testing.T) {
+	checks := []github.Check{
+		{Name: "build", State: "success"},
+		{Name: "test", State: "failure"},
+		{Name: "lint", State: "pending"},
+		{Name: "deploy", State: "error"},
+	}
+
+	failing := FailingChecks(checks)
+	if len(failing) != 2 || failing[0] != "test" || failing[1] != "deploy" {
+		t.Errorf("unexpected failing checks: %v", failing)
+	}
+	if FailingChecks(nil) != nil {
+		t.Error("expected nil for no checks")
+	}
+}
+
+func TestWebhookNotifierLifecycleType(t *testing.T) {
+	tests := []struct {
+		eventType string
+		expected  string
+	}{
+		{"", "pr_status_change"},
+		{EventStatusChange, "pr_status_change"},
+		{EventMerged, "pr_merged"},
+		{EventClosed, "pr_closed"},
+		{EventApproved, "pr_approved"},
+		{EventChangesRequested, "pr_changes_requested"},
+		{EventReviewRequested, "pr_review_requested"},
+		{EventReadyToMerge, "pr_ready_to_merge"},
+		{EventPushed, "pr_pushed"},
+		{EventForcePushed, "pr_force_pushed"},
+	}
+
+	for _, tt := range tests {
+		var receivedPayload WebhookPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &receivedPayload)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		event := &StatusChangeEvent{
+			Type:          tt.eventType,
+			Owner:         "owner",
+			Repo:          "repo",
+			Number:        123,
+			PreviousState: "open",
+			CurrentState:  "merged",
+			Timestamp:     time.Now(),
+		}
+		if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+			t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+		}
+		server.Close()
+
+		if receivedPayload.Type != tt.expected {
+			t.Errorf("event type %q: expected payload type %q, got %q", tt.eventType, tt.expected, receivedPayload.Type)
+		}
+	}
+}
+
+func TestConsoleNotifierLifecycleEvents(t *testing.T) {
+	notifier := NewConsoleNotifier()
+
+	for _, eventType := range []string{EventMerged, EventClosed, EventApproved, EventChangesRequested, EventReviewRequested, EventReadyToMerge, EventPushed, EventForcePushed} {
+		event := &StatusChangeEvent{
+			Type:          eventType,
+			Owner:         "owner",
+			Repo:          "repo",
+			Number:        123,
+			PreviousState: "open",
+			CurrentState:  eventType,
+			Timestamp:     time.Now(),
+		}
+		if err := notifier.Notify(event); err != nil {
+			t.Errorf("ConsoleNotifier.Notify failed for %s: %v", eventType, err)
+		}
+	}
+}
+
+func TestWebhookNotifierIncludesReviewers(t *testing.T) {
+	var receivedPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	event := &StatusChangeEvent{
+		Type:          EventReviewRequested,
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        123,
+		PreviousState: "review_required",
+		CurrentState:  "review_required",
+		Reviewers:     []string{"alice", "team:platform"},
+		Timestamp:     time.Now(),
+	}
+	if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+
+	if len(receivedPayload.Reviewers) != 2 || receivedPayload.Reviewers[1] != "team:platform" {
+		t.Errorf("expected reviewers in payload, got %v", receivedPayload.Reviewers)
+	}
+	if !event.IsReview() {
+		t.Error("expected review_requested to be a review event")
+	}
+}
+
+func TestWebhookNotifierIncludesFailuresAndLogs(t *testing.T) {
+	var receivedPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	event := &StatusChangeEvent{
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        123,
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		Checks: []github.Check{
+			{Name: "build", State: "success"},
+			{Name: "test", State: "failure"},
+		},
+		Logs:      []LogExcerpt{{Check: "test", Step: "Run tests", Lines: []string{"##[error]boom"}}},
+		Timestamp: time.Now(),
+	}
+	if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+
+	if len(receivedPayload.FailingChecks) != 1 || receivedPayload.FailingChecks[0] != "test" {
+		t.Errorf("expected failing checks in payload, got %v", receivedPayload.FailingChecks)
+	}
+	if len(receivedPayload.Logs) != 1 || receivedPayload.Logs[0].Step != "Run tests" || receivedPayload.Logs[0].Lines[0] != "##[error]boom" {
+		t.Errorf("expected log excerpts in payload, got %+v", receivedPayload.Logs)
+	}
+	if err := NewConsoleNotifier().Notify(event); err != nil {
+		t.Errorf("ConsoleNotifier.Notify failed: %v", err)
+	}
+}
+
+func TestLikelyFlakyEvents(t *testing.T) {
+	checks := []github.Check{
+		{Name: "build", State: "success"},
+		{Name: "e2e", State: "failure"},
+		{Name: "unit", State: "failure"},
+	}
+	tests := []struct {
+		name  string
+		flaky []string
+		want  bool
+	}{
+		{"no flaky checks", nil, false},
+		{"some failing checks flaky", []string{"e2e"}, false},
+		{"every failing check flaky", []string{"e2e", "unit"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &StatusChangeEvent{CurrentState: "failure", Checks: checks, FlakyChecks: tt.flaky}
+			if got := event.LikelyFlaky(); got != tt.want {
+				t.Errorf("LikelyFlaky() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var receivedPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	event := &StatusChangeEvent{
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        1,
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		Checks:        checks[:2],
+		FlakyChecks:   []string{"e2e"},
+		Timestamp:     time.Now(),
+	}
+	if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+	if !receivedPayload.LikelyFlaky || len(receivedPayload.FlakyChecks) != 1 || receivedPayload.FlakyChecks[0] != "e2e" {
+		t.Errorf("expected a likely flaky payload, got likely_flaky=%v flaky_checks=%v", receivedPayload.LikelyFlaky, receivedPayload.FlakyChecks)
+	}
+}
+
+func TestWebhookNotifierIncludesPushDetails(t *testing.T) {
+	var receivedPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	event := &StatusChangeEvent{
+		Type:          EventForcePushed,
+		Owner:         "owner",
+		Repo:          "repo",
+		Number:        123,
+		PreviousState: "success",
+		CurrentState:  "pending",
+		SHA:           "def4567890",
+		PreviousSHA:   "abc1234567",
+		Commits:       2,
+		Authors:       []string{"alice", "bob"},
+		Timestamp:     time.Now(),
+	}
+	if err := NewWebhookNotifier(server.URL).Notify(event); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+
+	if receivedPayload.Type != "pr_force_pushed" || receivedPayload.PreviousSHA != "abc1234567" || receivedPayload.Commits != 2 || len(receivedPayload.Authors) != 2 {
+		t.Errorf("expected push details in payload, got %+v", receivedPayload)
+	}
+	if got := commitSummary(event); got != "2 by alice, bob" {
+		t.Errorf("commitSummary() = %q", got)
+	}
+
+	failed := &StatusChangeEvent{Type: EventPushed, Owner: "owner", Repo: "repo", Number: 123, CompareFailed: true, Timestamp: time.Now()}
+	if err := NewWebhookNotifier(server.URL).Notify(failed); err != nil {
+		t.Fatalf("WebhookNotifier.Notify failed: %v", err)
+	}
+	if !receivedPayload.CompareFailed {
+		t.Errorf("expected compare_failed in payload, got %+v", receivedPayload)
+	}
+	if got := commitSummary(failed); got != "unknown (compare failed)" {
+		t.Errorf("commitSummary() = %q for a failed comparison", got)
+	}
+}
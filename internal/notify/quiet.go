@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule reports whether notifications should be held back at a given time.
+type Schedule interface {
+	Active(t time.Time) bool
+}
+
+// SummaryNotifier is implemented by notifiers that can deliver several events
+// as one notification. Notifiers that don't implement it get each event.
+type SummaryNotifier interface {
+	NotifySummary(events []*StatusChangeEvent) error
+}
+
+// HeldEventStore keeps the events a QuietNotifier holds, so that they are
+// still delivered after prw restarts during quiet hours.
+type HeldEventStore interface {
+	LoadHeld() ([]*StatusChangeEvent, error)
+	SaveHeld(events []*StatusChangeEvent) error
+}
+
+// DefaultQuietCheckInterval is how often a QuietNotifier holding events checks
+// whether quiet hours have ended.
+const DefaultQuietCheckInterval = time.Minute
+
+// QuietNotifier holds back events while its schedule is active and delivers them
+// to the wrapped notifier as one summary once the schedule ends. Events that
+// arrive after quiet hours are delivered right away, after any held summary.
+type QuietNotifier struct {
+	next          Notifier
+	schedule      Schedule
+	allowFailures bool
+
+	// OnError is called when delivering a summary in the background fails.
+	// It defaults to printing a warning to stderr.
+	OnError func(error)
+
+	// Store keeps the held events across restarts; see Restore. Without it,
+	// events still held when prw exits are lost.
+	Store HeldEventStore
+
+	checkInterval time.Duration
+	now           func() time.Time
+
+	mu      sync.Mutex
+	held    []*StatusChangeEvent
+	waiting bool
+}
+
+// NewQuietNotifier wraps next so that its events are held during schedule.
+// With allowFailures, CI failures are delivered immediately even during quiet hours.
+func NewQuietNotifier(next Notifier, schedule Schedule, allowFailures bool) *QuietNotifier {
+	return &QuietNotifier{
+		next:          next,
+		schedule:      schedule,
+		allowFailures: allowFailures,
+		checkInterval: DefaultQuietCheckInterval,
+		now:           time.Now,
+	}
+}
+
+// Notify holds the event during quiet hours and delivers it otherwise.
+func (q *QuietNotifier) Notify(event *StatusChangeEvent) error {
+	if q.schedule.Active(q.now()) {
+		if !q.allowFailures || !event.IsFailure() {
+			return q.hold(event)
+		}
+		return q.next.Notify(event)
+	}
+
+	summaryErr := q.deliverHeld()
+	if err := q.next.Notify(event); err != nil {
+		return err
+	}
+	return summaryErr
+}
+
+// Held returns the number of events waiting for quiet hours to end.
+func (q *QuietNotifier) Held() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.held)
+}
+
+// Restore holds the events left in Store by an earlier run again, to be
+// delivered once quiet hours end.
+func (q *QuietNotifier) Restore() error {
+	if q.Store == nil {
+		return nil
+	}
+	events, err := q.Store.LoadHeld()
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.held = append(events, q.held...)
+	if len(q.held) > 0 && !q.waiting {
+		q.waiting = true
+		go q.waitForEnd()
+	}
+	return nil
+}
+
+// Flush delivers the held events as one summary unless quiet hours are still
+// active, then flushes the wrapped notifier. Events that stay held are only
+// kept if there is a Store; otherwise Flush reports that they are lost.
+func (q *QuietNotifier) Flush() error {
+	summaryErr := q.deliverHeld()
+	if summaryErr == nil && q.Store == nil {
+		if held := q.Held(); held > 0 {
+			summaryErr = fmt.Errorf("%d notifications held for quiet hours were not sent", held)
+		}
+	}
+	if err := Flush(q.next); err != nil && summaryErr == nil {
+		summaryErr = err
+	}
+	return summaryErr
+}
+
+// deliverHeld delivers the held events as one summary unless quiet hours are still active.
+func (q *QuietNotifier) deliverHeld() error {
+	if q.schedule.Active(q.now()) {
+		return nil
+	}
+
+	q.mu.Lock()
+	events := q.held
+	q.held = nil
+	var storeErr error
+	if q.Store != nil && len(events) > 0 {
+		storeErr = q.Store.SaveHeld(nil)
+	}
+	q.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := notifySummary(q.next, events); err != nil {
+		return fmt.Errorf("quiet hours summary failed: %w", err)
+	}
+	if storeErr != nil {
+		return fmt.Errorf("failed to clear held events: %w", storeErr)
+	}
+	return nil
+}
+
+// hold adds the event to the held events and persists them in Store.
+func (q *QuietNotifier) hold(event *StatusChangeEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.held = append(q.held, event)
+	if !q.waiting {
+		q.waiting = true
+		go q.waitForEnd()
+	}
+	if q.Store != nil {
+		if err := q.Store.SaveHeld(append([]*StatusChangeEvent(nil), q.held...)); err != nil {
+			return fmt.Errorf("failed to keep the event held for quiet hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// waitForEnd delivers the held events once quiet hours end, even if no new
+// event arrives to trigger it.
+func (q *QuietNotifier) waitForEnd() {
+	for {
+		time.Sleep(q.checkInterval)
+		if q.schedule.Active(q.now()) {
+			continue
+		}
+
+		q.mu.Lock()
+		q.waiting = false
+		q.mu.Unlock()
+
+		if err := q.deliverHeld(); err != nil {
+			if q.OnError != nil {
+				q.OnError(err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		return
+	}
+}
+
+// notifySummary delivers events to n as one summary if it supports it, and one by one otherwise.
+func notifySummary(n Notifier, events []*StatusChangeEvent) error {
+	if s, ok := n.(SummaryNotifier); ok {
+		return s.NotifySummary(events)
+	}
+	for _, event := range events {
+		if err := n.Notify(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifySummary sends the events as a summary to all notifiers.
+func (m *MultiNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	var firstErr error
+	for _, n := range m.notifiers {
+		if err := notifySummary(n, events); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WebhookSummaryPayload is the JSON structure sent to the webhook for events
+// held during quiet hours.
+type WebhookSummaryPayload struct {
+	Type      string           `json:"type"`
+	Count     int              `json:"count"`
+	Events    []WebhookPayload `json:"events"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// NotifySummary sends the events to the webhook in one quiet_hours_summary payload.
+func (w *WebhookNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	if w.URL == "" {
+		return nil
+	}
+
+	payload := WebhookSummaryPayload{
+		Type:      "quiet_hours_summary",
+		Count:     len(events),
+		Events:    make([]WebhookPayload, 0, len(events)),
+		Timestamp: time.Now(),
+	}
+	for _, event := range events {
+		payload.Events = append(payload.Events, newWebhookPayload(event))
+	}
+	return w.post(payload)
+}
+
+// maxSummaryLines is the number of events listed in a native summary notification.
+const maxSummaryLines = 5
+
+// NotifySummary shows one native notification listing the events.
+func (n *NativeNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	if !n.enabled {
+		return nil
+	}
+
+	return n.send("prw: "+summaryTitle(events), summaryText(events, maxSummaryLines))
+}
+
+// summaryText lists up to limit events, one per line.
+func summaryText(events []*StatusChangeEvent, limit int) string {
+	var lines []string
+	for i, event := range events {
+		if i == limit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(events)-limit))
+			break
+		}
+		lines = append(lines, summaryLine(event))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summaryLine describes an event in one line, e.g. "owner/repo#1: pending → failure".
+func summaryLine(event *StatusChangeEvent) string {
+	what := fmt.Sprintf("%s → %s", event.PreviousState, event.CurrentState)
+	if event.EventType() != EventStatusChange {
+		what = strings.ReplaceAll(event.EventType(), "_", " ")
+	}
+	return fmt.Sprintf("%s/%s#%d: %s", event.Owner, event.Repo, event.Number, what)
+}
@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeSchedule struct{ active atomic.Bool }
+
+func (s *fakeSchedule) Active(time.Time) bool { return s.active.Load() }
+
+type recordingNotifier struct {
+	mu        sync.Mutex
+	events    []*StatusChangeEvent
+	summaries [][]*StatusChangeEvent
+}
+
+func (r *recordingNotifier) Notify(event *StatusChangeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summaries = append(r.summaries, events)
+	return nil
+}
+
+func (r *recordingNotifier) counts() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events), len(r.summaries)
+}
+
+func TestQuietNotifierHoldsAndSummarizes(t *testing.T) {
+	schedule := &fakeSchedule{}
+	schedule.active.Store(true)
+	next := &recordingNotifier{}
+	quiet := NewQuietNotifier(next, schedule, true)
+	quiet.checkInterval = 5 * time.Millisecond
+
+	success := &StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, PreviousState: "pending", CurrentState: "success"}
+	push := &StatusChangeEvent{Type: EventPushed, Owner: "o", Repo: "r", Number: 2}
+	failure := &StatusChangeEvent{Owner: "o", Repo: "r", Number: 3, PreviousState: "pending", CurrentState: "failure"}
+	for _, event := range []*StatusChangeEvent{success, push, failure} {
+		if err := quiet.Notify(event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+
+	if events, summaries := next.counts(); events != 1 || summaries != 0 || next.events[0] != failure {
+		t.Fatalf("expected only the failure to break through, got %d events and %d summaries", events, summaries)
+	}
+	if quiet.Held() != 2 {
+		t.Fatalf("expected 2 held events, got %d", quiet.Held())
+	}
+
+	schedule.active.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	_, summaries := next.counts()
+	for summaries == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, summaries = next.counts()
+	}
+	if summaries != 1 || len(next.summaries[0]) != 2 || next.summaries[0][0] != success || next.summaries[0][1] != push {
+		t.Fatalf("expected one summary of the held events once quiet hours ended, got %+v", next.summaries)
+	}
+
+	if err := quiet.Notify(success); err != nil {
+		t.Fatal(err)
+	}
+	if events, summaries := next.counts(); events != 2 || summaries != 1 {
+		t.Errorf("expected events to pass through after quiet hours, got %d events and %d summaries", events, summaries)
+	}
+}
+
+func TestQuietNotifierHoldsFailuresByDefault(t *testing.T) {
+	schedule := &fakeSchedule{}
+	schedule.active.Store(true)
+	next := &recordingNotifier{}
+	quiet := NewQuietNotifier(next, schedule, false)
+	quiet.checkInterval = time.Hour
+
+	if err := quiet.Notify(&StatusChangeEvent{CurrentState: "failure"}); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := next.counts(); events != 0 || quiet.Held() != 1 {
+		t.Fatalf("expected the failure to be held, got %d events delivered", events)
+	}
+
+	// The next event after quiet hours delivers the summary first.
+	schedule.active.Store(false)
+	if err := quiet.Notify(&StatusChangeEvent{CurrentState: "success"}); err != nil {
+		t.Fatal(err)
+	}
+	if events, summaries := next.counts(); events != 1 || summaries != 1 || quiet.Held() != 0 {
+		t.Errorf("expected a summary and the new event, got %d events and %d summaries", events, summaries)
+	}
+}
+
+// memoryHeldEvents is a HeldEventStore in memory.
+type memoryHeldEvents struct {
+	mu     sync.Mutex
+	events []*StatusChangeEvent
+}
+
+func (m *memoryHeldEvents) LoadHeld() ([]*StatusChangeEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.events, nil
+}
+
+func (m *memoryHeldEvents) SaveHeld(events []*StatusChangeEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = events
+	return nil
+}
+
+func (m *memoryHeldEvents) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.events)
+}
+
+func TestQuietNotifierKeepsHeldEventsAcrossRestarts(t *testing.T) {
+	schedule := &fakeSchedule{}
+	schedule.active.Store(true)
+	store := &memoryHeldEvents{}
+
+	first := NewQuietNotifier(&recordingNotifier{}, schedule, false)
+	first.checkInterval = time.Hour
+	first.Store = store
+	for i := 1; i <= 2; i++ {
+		if err := first.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: i, CurrentState: "success"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Exiting during quiet hours leaves the events in the store
+	if err := first.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if store.count() != 2 {
+		t.Fatalf("expected 2 stored events, got %d", store.count())
+	}
+
+	next := &recordingNotifier{}
+	second := NewQuietNotifier(next, schedule, false)
+	second.checkInterval = 5 * time.Millisecond
+	second.Store = store
+	if err := second.Restore(); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if second.Held() != 2 {
+		t.Fatalf("expected the stored events to be held again, got %d", second.Held())
+	}
+
+	schedule.active.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	_, summaries := next.counts()
+	for summaries == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, summaries = next.counts()
+	}
+	if summaries != 1 || len(next.summaries[0]) != 2 {
+		t.Fatalf("expected one summary of the restored events, got %+v", next.summaries)
+	}
+	if store.count() != 0 {
+		t.Errorf("expected delivered events to be removed from the store, got %d", store.count())
+	}
+}
+
+func TestQuietNotifierFlushReportsLostEvents(t *testing.T) {
+	schedule := &fakeSchedule{}
+	schedule.active.Store(true)
+	quiet := NewQuietNotifier(&recordingNotifier{}, schedule, false)
+	quiet.checkInterval = time.Hour
+
+	if err := quiet.Notify(&StatusChangeEvent{CurrentState: "success"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := quiet.Flush(); err == nil || !strings.Contains(err.Error(), "1 notifications held for quiet hours were not sent") {
+		t.Errorf("expected Flush to report the held event without a store, got %v", err)
+	}
+}
+
+func TestMultiNotifierSummaryFallsBackToEvents(t *testing.T) {
+	summarizing := &recordingNotifier{}
+	var plain []*StatusChangeEvent
+	multi := NewMultiNotifier(summarizing, notifierFunc(func(e *StatusChangeEvent) error {
+		plain = append(plain, e)
+		return nil
+	}))
+
+	events := []*StatusChangeEvent{{Number: 1}, {Number: 2}}
+	if err := multi.NotifySummary(events); err != nil {
+		t.Fatal(err)
+	}
+	if _, summaries := summarizing.counts(); summaries != 1 {
+		t.Errorf("expected one summary, got %d", summaries)
+	}
+	if len(plain) != 2 {
+		t.Errorf("expected both events for notifiers without summaries, got %d", len(plain))
+	}
+}
+
+type notifierFunc func(*StatusChangeEvent) error
+
+func (f notifierFunc) Notify(event *StatusChangeEvent) error { return f(event) }
+
+func TestWebhookNotifierSummary(t *testing.T) {
+	var payload WebhookSummaryPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("failed to parse summary payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	events := []*StatusChangeEvent{
+		{Owner: "o", Repo: "r", Number: 1, PreviousState: "pending", CurrentState: "success"},
+		{Type: EventMerged, Owner: "o", Repo: "r", Number: 2},
+	}
+	if err := NewWebhookNotifier(server.URL).NotifySummary(events); err != nil {
+		t.Fatalf("NotifySummary failed: %v", err)
+	}
+	if payload.Type != "quiet_hours_summary" || payload.Count != 2 || len(payload.Events) != 2 {
+		t.Fatalf("unexpected summary payload: %+v", payload)
+	}
+	if payload.Events[0].Type != "pr_status_change" || payload.Events[1].Type != "pr_merged" {
+		t.Errorf("unexpected event types: %s, %s", payload.Events[0].Type, payload.Events[1].Type)
+	}
+}
+
+func TestSummaryText(t *testing.T) {
+	var events []*StatusChangeEvent
+	for i := 1; i <= 7; i++ {
+		events = append(events, &StatusChangeEvent{Owner: "o", Repo: "r", Number: i, PreviousState: "pending", CurrentState: "success"})
+	}
+	events[1].Type = EventForcePushed
+
+	lines := strings.Split(summaryText(events, 5), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 5 events and a remainder line, got %q", lines)
+	}
+	if lines[0] != "o/r#1: pending → success" || lines[1] != "o/r#2: force pushed" || lines[5] != "…and 2 more" {
+		t.Errorf("unexpected summary lines: %q", lines)
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Route sends the events it matches to the named notifiers. Every non-empty
+// criterion must match; within a criterion any value may match. Repos are
+// case-insensitive owner/repo glob patterns such as "org/*"; authors, labels,
+// and states are compared case-insensitively.
+type Route struct {
+	Repos     []string
+	Authors   []string
+	Labels    []string // the PR has at least one of these labels
+	Events    []string // event types, e.g. status_change or merged
+	From      []string // previous states
+	To        []string // current states
+	Notifiers []string
+	Stop      bool // skip the routes after this one when it matches
+}
+
+// Matches reports whether the event meets every criterion of the route.
+func (r Route) Matches(event *StatusChangeEvent) bool {
+	repo := strings.ToLower(event.Owner + "/" + event.Repo)
+	return matchesAny(r.Repos, func(pattern string) bool {
+		ok, _ := path.Match(strings.ToLower(pattern), repo)
+		return ok
+	}) &&
+		matchesAny(r.Authors, func(author string) bool { return strings.EqualFold(author, event.Author) }) &&
+		matchesAny(r.Labels, func(label string) bool { return containsFold(event.Labels, label) }) &&
+		matchesAny(r.Events, func(eventType string) bool { return strings.EqualFold(eventType, event.EventType()) }) &&
+		matchesAny(r.From, func(state string) bool { return strings.EqualFold(state, event.PreviousState) }) &&
+		matchesAny(r.To, func(state string) bool { return strings.EqualFold(state, event.CurrentState) })
+}
+
+// matchesAny reports whether match accepts one of values; no values match everything.
+func matchesAny(values []string, match func(string) bool) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Router delivers each event to the notifiers named by the routes it matches,
+// in route order and at most once per notifier. Events matching no route are
+// dropped.
+type Router struct {
+	routes    []Route
+	notifiers map[string]Notifier
+}
+
+// NewRouter creates a router over named notifiers. Routes may name notifiers
+// that are missing from notifiers, e.g. because they are not configured; those
+// names are skipped.
+func NewRouter(routes []Route, notifiers map[string]Notifier) *Router {
+	return &Router{routes: routes, notifiers: notifiers}
+}
+
+// Targets returns the names of the notifiers an event is routed to.
+func (r *Router) Targets(event *StatusChangeEvent) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, route := range r.routes {
+		if !route.Matches(event) {
+			continue
+		}
+		for _, name := range route.Notifiers {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		if route.Stop {
+			break
+		}
+	}
+	return names
+}
+
+// Notify sends the event to its notifiers. A failing notifier does not keep
+// the event from the others; the first error is returned.
+func (r *Router) Notify(event *StatusChangeEvent) error {
+	var firstErr error
+	for _, name := range r.Targets(event) {
+		n, ok := r.notifiers[name]
+		if !ok {
+			continue
+		}
+		if err := n.Notify(event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Flush flushes every notifier that holds events back, in name order; the
+// first error is returned.
+func (r *Router) Flush() error {
+	names := make([]string, 0, len(r.notifiers))
+	for name := range r.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var firstErr error
+	for _, name := range names {
+		if err := Flush(r.notifiers[name]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRouteMatches(t *testing.T) {
+	event := &StatusChangeEvent{
+		Owner:         "Org",
+		Repo:          "api",
+		Author:        "octocat",
+		Labels:        []string{"bug", "Area/API"},
+		PreviousState: "pending",
+		CurrentState:  "failure",
+	}
+
+	tests := []struct {
+		name  string
+		route Route
+		want  bool
+	}{
+		{"empty route matches everything", Route{}, true},
+		{"repo glob", Route{Repos: []string{"other/*", "org/*"}}, true},
+		{"repo mismatch", Route{Repos: []string{"org/web"}}, false},
+		{"author", Route{Authors: []string{"OctoCat"}}, true},
+		{"author mismatch", Route{Authors: []string{"hubot"}}, false},
+		{"any label", Route{Labels: []string{"urgent", "area/api"}}, true},
+		{"label mismatch", Route{Labels: []string{"urgent"}}, false},
+		{"event type", Route{Events: []string{EventStatusChange}}, true},
+		{"event type mismatch", Route{Events: []string{EventMerged}}, false},
+		{"states", Route{From: []string{"pending"}, To: []string{"failure", "error"}}, true},
+		{"state mismatch", Route{To: []string{"success"}}, false},
+		{"all criteria must match", Route{Repos: []string{"org/api"}, Authors: []string{"hubot"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.route.Matches(event); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	api, native, console := &mockNotifier{}, &mockNotifier{}, &mockNotifier{}
+	router := NewRouter([]Route{
+		{Repos: []string{"org/api"}, To: []string{"failure", "error"}, Notifiers: []string{"api-ci"}},
+		{Authors: []string{"me"}, To: []string{"success"}, Notifiers: []string{"native"}, Stop: true},
+		{Notifiers: []string{"console", "api-ci", "unconfigured"}},
+	}, map[string]Notifier{"api-ci": api, "native": native, "console": console})
+
+	failure := &StatusChangeEvent{Owner: "org", Repo: "api", CurrentState: "failure"}
+	if got := router.Targets(failure); !reflect.DeepEqual(got, []string{"api-ci", "console", "unconfigured"}) {
+		t.Errorf("Targets(failure) = %v", got)
+	}
+	success := &StatusChangeEvent{Owner: "org", Repo: "web", Author: "me", CurrentState: "success"}
+	if got := router.Targets(success); !reflect.DeepEqual(got, []string{"native"}) {
+		t.Errorf("expected the stop route to end routing, got %v", got)
+	}
+
+	for _, event := range []*StatusChangeEvent{failure, success} {
+		if err := router.Notify(event); err != nil {
+			t.Fatalf("Notify failed: %v", err)
+		}
+	}
+	if len(api.events) != 1 || len(native.events) != 1 || len(console.events) != 1 {
+		t.Errorf("unexpected deliveries: api-ci %d, native %d, console %d", len(api.events), len(native.events), len(console.events))
+	}
+}
+
+func TestRouterDeliversDespiteErrors(t *testing.T) {
+	failing, next := &mockNotifier{err: errors.New("boom")}, &mockNotifier{}
+	router := NewRouter([]Route{{Notifiers: []string{"failing", "next"}}},
+		map[string]Notifier{"failing": failing, "next": next})
+
+	if err := router.Notify(&StatusChangeEvent{}); err == nil || err.Error() != "boom" {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if len(next.events) != 1 {
+		t.Error("expected the event to reach the other notifiers")
+	}
+}
@@ -0,0 +1,359 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/devblac/prw/internal/retry"
+)
+
+// DefaultSlackAPIURL is the base URL of the Slack Web API.
+const DefaultSlackAPIURL = "https://slack.com/api"
+
+// SlackNotifier posts Block Kit messages to Slack, either through an incoming
+// webhook or, with a bot token and channel, through the Web API. With a bot
+// token the first event of a PR starts a thread: later events are replied in
+// the thread and the original message is updated to show the latest one.
+// Messages are only re-posted when Slack never received them, so a failure
+// after Slack accepted a message doesn't post it twice.
+type SlackNotifier struct {
+	WebhookURL string
+	Token      string
+	Channel    string
+	APIURL     string
+	HTTPClient *http.Client
+	Retry      retry.Policy
+	Template   *MessageTemplate // renders the mrkdwn text instead of the built-in blocks
+	Threads    SlackThreadStore // persists threads across restarts; nil keeps them in memory only
+
+	mu      sync.Mutex
+	threads map[string]slackThread // keyed by PR URL
+}
+
+// SlackThreadStore persists the thread started for each PR, so events keep
+// going to the same thread after prw restarts. Keys combine the channel and
+// the PR URL.
+type SlackThreadStore interface {
+	LoadSlackThread(key string) (channel, ts string, ok bool)
+	SaveSlackThread(key, channel, ts string) error
+}
+
+// slackThread identifies the first message posted about a PR.
+type slackThread struct {
+	channel string
+	ts      string
+}
+
+// NewSlackWebhookNotifier creates a Slack notifier that posts to an incoming webhook.
+func NewSlackWebhookNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL: url,
+		HTTPClient: newHTTPClient(),
+		Retry:      retry.DefaultPolicy(),
+	}
+}
+
+// NewSlackBotNotifier creates a Slack notifier that posts to a channel with a
+// bot token and threads the events of each PR.
+func NewSlackBotNotifier(token, channel string) *SlackNotifier {
+	return &SlackNotifier{
+		Token:      token,
+		Channel:    channel,
+		APIURL:     DefaultSlackAPIURL,
+		HTTPClient: newHTTPClient(),
+		Retry:      retry.DefaultPolicy(),
+	}
+}
+
+// slackMessage is the body of chat.postMessage, chat.update, and incoming webhooks.
+type slackMessage struct {
+	Channel  string       `json:"channel,omitempty"`
+	TS       string       `json:"ts,omitempty"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	Text     string       `json:"text"`
+	Blocks   []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a Block Kit section or context block.
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackResponse is the part of a Web API response prw uses.
+type slackResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// Notify posts the event to Slack.
+func (s *SlackNotifier) Notify(event *StatusChangeEvent) error {
+	msg := slackMessage{
+		Text:   fmt.Sprintf("%s/%s#%d: %s", event.Owner, event.Repo, event.Number, eventHeadline(event)),
+		Blocks: slackBlocks(event),
+	}
+	if text, ok, err := s.Template.Render(event); ok {
+		if err != nil {
+			return err
+		}
+		msg.Text = text
+		msg.Blocks = []slackBlock{slackSection(text)}
+	}
+	if !s.useBot() {
+		return s.postWebhook(msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := event.URL()
+	thread, ok := s.threads[key]
+	if !ok && s.Threads != nil {
+		thread.channel, thread.ts, ok = s.Threads.LoadSlackThread(s.Channel + " " + key)
+	}
+	if s.threads == nil {
+		s.threads = make(map[string]slackThread)
+	}
+	if !ok {
+		msg.Channel = s.Channel
+		resp, err := s.call("chat.postMessage", msg)
+		if err != nil {
+			return err
+		}
+		s.threads[key] = slackThread{channel: resp.Channel, ts: resp.TS}
+		if s.Threads != nil {
+			if err := s.Threads.SaveSlackThread(s.Channel+" "+key, resp.Channel, resp.TS); err != nil {
+				return fmt.Errorf("failed to save Slack thread: %w", err)
+			}
+		}
+		return nil
+	}
+	s.threads[key] = thread
+
+	reply := msg
+	reply.Channel = thread.channel
+	reply.ThreadTS = thread.ts
+	if _, err := s.call("chat.postMessage", reply); err != nil {
+		return err
+	}
+
+	update := msg
+	update.Channel = thread.channel
+	update.TS = thread.ts
+	_, err := s.call("chat.update", update)
+	return err
+}
+
+// NotifySummary posts one message listing the events held during quiet hours.
+func (s *SlackNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	title := summaryTitle(events)
+
+	var lines []string
+	for _, event := range events {
+		lines = append(lines, fmt.Sprintf("%s <%s|%s/%s#%d> %s",
+			slackEmoji(event), event.URL(), event.Owner, event.Repo, event.Number, slackEscape(eventHeadline(event))))
+	}
+	msg := slackMessage{
+		Text: title,
+		Blocks: []slackBlock{
+			slackSection("*" + title + "*"),
+			slackSection(strings.Join(lines, "\n")),
+		},
+	}
+	if !s.useBot() {
+		return s.postWebhook(msg)
+	}
+
+	msg.Channel = s.Channel
+	_, err := s.call("chat.postMessage", msg)
+	return err
+}
+
+func (s *SlackNotifier) useBot() bool {
+	return s.Token != "" && s.Channel != ""
+}
+
+// postWebhook sends a message to the incoming webhook.
+func (s *SlackNotifier) postWebhook(msg slackMessage) error {
+	if s.WebhookURL == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+	resp, err := s.Retry.DoNonIdempotent(s.HTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", s.WebhookURL, bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Slack request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned non-2xx status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// call invokes a Slack Web API method with the bot token.
+func (s *SlackNotifier) call(method string, msg slackMessage) (*slackResponse, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+
+	apiURL := s.APIURL
+	if apiURL == "" {
+		apiURL = DefaultSlackAPIURL
+	}
+	// Updating a message is safe to repeat; posting one twice duplicates it
+	do := s.Retry.DoNonIdempotent
+	if method == "chat.update" {
+		do = s.Retry.Do
+	}
+	resp, err := do(s.HTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequest("POST", strings.TrimSuffix(apiURL, "/")+"/"+method, bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Slack request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("slack %s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("slack %s returned non-2xx status: %d", method, resp.StatusCode)
+	}
+	var result slackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode Slack %s response: %w", method, err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("slack %s failed: %s", method, result.Error)
+	}
+	return &result, nil
+}
+
+// slackBlocks builds the Block Kit blocks of an event.
+func slackBlocks(event *StatusChangeEvent) []slackBlock {
+	heading := fmt.Sprintf("*<%s|%s/%s#%d>*", event.URL(), event.Owner, event.Repo, event.Number)
+	if event.Title != "" {
+		heading += " " + slackEscape(event.Title)
+	}
+	blocks := []slackBlock{
+		slackSection(heading),
+		slackSection(slackEmoji(event) + " *" + slackEscape(eventHeadline(event)) + "*"),
+	}
+
+	var details []string
+	if failing := FailingChecks(event.Checks); len(failing) > 0 {
+		line := "*Failing checks:* " + slackEscape(strings.Join(failing, ", "))
+		if event.LikelyFlaky() {
+			line += " _(likely flaky)_"
+		}
+		details = append(details, line)
+	}
+	if len(event.Reviewers) > 0 {
+		details = append(details, "*Reviewers:* "+slackEscape(strings.Join(event.Reviewers, ", ")))
+	}
+	if event.IsPush() {
+		line := fmt.Sprintf("*Head:* `%s` → `%s`", shortSHA(event.PreviousSHA), shortSHA(event.SHA))
+		if commits := commitSummary(event); commits != "" {
+			line += " (" + slackEscape(commits) + ")"
+		}
+		details = append(details, line)
+	}
+	if event.Duration > 0 {
+		label := "CI duration"
+		if event.EventType() == EventStuck {
+			label = "Pending for"
+		}
+		details = append(details, fmt.Sprintf("*%s:* %s", label, FormatDuration(event.Duration)))
+	}
+	if len(details) > 0 {
+		blocks = append(blocks, slackSection(strings.Join(details, "\n")))
+	}
+
+	var context []string
+	if event.SHA != "" {
+		context = append(context, "`"+shortSHA(event.SHA)+"`")
+	}
+	if !event.Timestamp.IsZero() {
+		context = append(context, fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>",
+			event.Timestamp.Unix(), event.Timestamp.UTC().Format("2006-01-02 15:04 UTC")))
+	}
+	if len(context) > 0 {
+		blocks = append(blocks, slackBlock{
+			Type:     "context",
+			Elements: []slackText{{Type: "mrkdwn", Text: strings.Join(context, " · ")}},
+		})
+	}
+	return blocks
+}
+
+func slackSection(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+// slackEmoji returns the emoji shown next to an event's headline.
+func slackEmoji(event *StatusChangeEvent) string {
+	switch event.EventType() {
+	case EventMerged:
+		return ":tada:"
+	case EventClosed:
+		return ":no_entry_sign:"
+	case EventApproved:
+		return ":white_check_mark:"
+	case EventChangesRequested:
+		return ":raised_hand:"
+	case EventReviewRequested:
+		return ":eyes:"
+	case EventReadyToMerge:
+		return ":rocket:"
+	case EventPushed:
+		return ":arrow_up:"
+	case EventForcePushed:
+		return ":warning:"
+	case EventStuck:
+		return ":hourglass:"
+	}
+
+	switch event.CurrentState {
+	case "success":
+		return ":large_green_circle:"
+	case "failure", "error":
+		return ":red_circle:"
+	case "pending":
+		return ":large_yellow_circle:"
+	default:
+		return ":bell:"
+	}
+}
+
+// slackEscape escapes the characters Slack treats as markup in mrkdwn text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
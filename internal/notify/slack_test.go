@@ -0,0 +1,251 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/retry"
+)
+
+type slackCall struct {
+	method string
+	auth   string
+	msg    slackMessage
+}
+
+// fakeSlackAPI records Web API calls and answers them like Slack does.
+func fakeSlackAPI(t *testing.T) (*httptest.Server, func() []slackCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []slackCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode Slack request: %v", err)
+		}
+		mu.Lock()
+		calls = append(calls, slackCall{method: strings.TrimPrefix(r.URL.Path, "/"), auth: r.Header.Get("Authorization"), msg: msg})
+		n := len(calls)
+		mu.Unlock()
+
+		if msg.Channel == "#missing" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": "channel_not_found"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "channel": "C123", "ts": fmt.Sprintf("1700000000.%06d", n)})
+	}))
+	return server, func() []slackCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]slackCall(nil), calls...)
+	}
+}
+
+func TestSlackNotifierThreadsEventsPerPR(t *testing.T) {
+	server, calls := fakeSlackAPI(t)
+	defer server.Close()
+
+	notifier := NewSlackBotNotifier("xoxb-token", "#ci")
+	notifier.APIURL = server.URL
+
+	failing := &StatusChangeEvent{
+		Owner: "owner", Repo: "repo", Number: 1, Title: "Fix <everything>",
+		PreviousState: "pending", CurrentState: "failure", SHA: "abcdef1234567",
+		Checks:    []github.Check{{Name: "test", State: "failure"}, {Name: "lint", State: "success"}},
+		Timestamp: time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC),
+	}
+	if err := notifier.Notify(failing); err != nil {
+		t.Fatalf("first Notify failed: %v", err)
+	}
+	merged := &StatusChangeEvent{Type: EventMerged, Owner: "owner", Repo: "repo", Number: 1}
+	if err := notifier.Notify(merged); err != nil {
+		t.Fatalf("second Notify failed: %v", err)
+	}
+	other := &StatusChangeEvent{Owner: "owner", Repo: "repo", Number: 2, PreviousState: "pending", CurrentState: "success"}
+	if err := notifier.Notify(other); err != nil {
+		t.Fatalf("Notify for another PR failed: %v", err)
+	}
+
+	got := calls()
+	if len(got) != 4 {
+		t.Fatalf("expected 4 API calls (post, reply, update, post), got %d", len(got))
+	}
+	first, reply, update, second := got[0], got[1], got[2], got[3]
+
+	if first.method != "chat.postMessage" || first.auth != "Bearer xoxb-token" || first.msg.Channel != "#ci" || first.msg.ThreadTS != "" {
+		t.Errorf("unexpected first call: %+v", first)
+	}
+	if reply.method != "chat.postMessage" || reply.msg.Channel != "C123" || reply.msg.ThreadTS != "1700000000.000001" {
+		t.Errorf("expected a threaded reply to the first message, got %+v", reply)
+	}
+	if update.method != "chat.update" || update.msg.TS != "1700000000.000001" || !strings.Contains(update.msg.Text, "Merged") {
+		t.Errorf("expected the first message to be updated to the latest event, got %+v", update)
+	}
+	if second.method != "chat.postMessage" || second.msg.ThreadTS != "" {
+		t.Errorf("expected another PR to start its own thread, got %+v", second)
+	}
+
+	var text []string
+	for _, block := range first.msg.Blocks {
+		if block.Text != nil {
+			text = append(text, block.Text.Text)
+		}
+		for _, element := range block.Elements {
+			text = append(text, element.Text)
+		}
+	}
+	joined := strings.Join(text, "\n")
+	for _, want := range []string{
+		"*<https://github.com/owner/repo/pull/1|owner/repo#1>* Fix &lt;everything&gt;",
+		":red_circle: *CI failed (pending → failure)*",
+		"*Failing checks:* test",
+		"`abcdef1`",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("blocks missing %q:\n%s", want, joined)
+		}
+	}
+	if first.msg.Text != "owner/repo#1: CI failed (pending → failure)" {
+		t.Errorf("unexpected fallback text %q", first.msg.Text)
+	}
+}
+
+// memorySlackThreads is a SlackThreadStore shared by notifiers in tests.
+type memorySlackThreads map[string][2]string
+
+func (m memorySlackThreads) LoadSlackThread(key string) (string, string, bool) {
+	thread, ok := m[key]
+	return thread[0], thread[1], ok
+}
+
+func (m memorySlackThreads) SaveSlackThread(key, channel, ts string) error {
+	m[key] = [2]string{channel, ts}
+	return nil
+}
+
+func TestSlackNotifierContinuesStoredThreads(t *testing.T) {
+	server, calls := fakeSlackAPI(t)
+	defer server.Close()
+
+	threads := memorySlackThreads{}
+	event := &StatusChangeEvent{Owner: "owner", Repo: "repo", Number: 1, PreviousState: "pending", CurrentState: "failure"}
+	notifier := NewSlackBotNotifier("xoxb-token", "#ci")
+	notifier.APIURL = server.URL
+	notifier.Threads = threads
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if _, ok := threads["#ci https://github.com/owner/repo/pull/1"]; !ok {
+		t.Fatalf("expected the thread to be stored, got %v", threads)
+	}
+
+	// A notifier created after a restart replies in the stored thread.
+	restarted := NewSlackBotNotifier("xoxb-token", "#ci")
+	restarted.APIURL = server.URL
+	restarted.Threads = threads
+	if err := restarted.Notify(event); err != nil {
+		t.Fatalf("Notify after restart failed: %v", err)
+	}
+	got := calls()
+	if len(got) != 3 || got[1].msg.ThreadTS != "1700000000.000001" || got[2].method != "chat.update" {
+		t.Errorf("expected a reply in the stored thread, got %+v", got)
+	}
+
+	// Threads are kept per channel.
+	other := NewSlackBotNotifier("xoxb-token", "#api")
+	other.APIURL = server.URL
+	other.Threads = threads
+	if err := other.Notify(event); err != nil {
+		t.Fatalf("Notify to another channel failed: %v", err)
+	}
+	if got := calls(); got[len(got)-1].msg.ThreadTS != "" {
+		t.Errorf("expected another channel to start its own thread, got %+v", got[len(got)-1])
+	}
+}
+
+func TestSlackNotifierDoesNotRepostOnServerErrors(t *testing.T) {
+	var mu sync.Mutex
+	methods := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods[strings.TrimPrefix(r.URL.Path, "/")]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackBotNotifier("xoxb-token", "#ci")
+	notifier.APIURL = server.URL
+	notifier.Retry = retry.Policy{MaxAttempts: 3}
+	if err := notifier.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, CurrentState: "success"}); err == nil {
+		t.Fatal("expected an error for a 502 response")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if methods["chat.postMessage"] != 1 {
+		t.Errorf("expected chat.postMessage to be sent once, got %d", methods["chat.postMessage"])
+	}
+}
+
+func TestSlackNotifierReportsAPIErrors(t *testing.T) {
+	server, _ := fakeSlackAPI(t)
+	defer server.Close()
+
+	notifier := NewSlackBotNotifier("xoxb-token", "#missing")
+	notifier.APIURL = server.URL
+	err := notifier.Notify(&StatusChangeEvent{Owner: "o", Repo: "r", Number: 1, CurrentState: "success"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("expected the Slack error to be returned, got %v", err)
+	}
+}
+
+func TestSlackWebhookNotifier(t *testing.T) {
+	var messages []slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode Slack webhook body: %v", err)
+		}
+		messages = append(messages, msg)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	notifier := NewSlackWebhookNotifier(server.URL)
+	event := &StatusChangeEvent{Type: EventForcePushed, Owner: "o", Repo: "r", Number: 3, PreviousSHA: "1111111aaa", SHA: "2222222bbb", Commits: 2, Authors: []string{"alice"}}
+	if err := notifier.Notify(event); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if err := notifier.NotifySummary([]*StatusChangeEvent{event, {Owner: "o", Repo: "r", Number: 4, CurrentState: "success"}}); err != nil {
+		t.Fatalf("NotifySummary failed: %v", err)
+	}
+
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 webhook posts, got %d", len(messages))
+	}
+	if messages[0].Channel != "" || messages[0].ThreadTS != "" {
+		t.Errorf("webhook messages should not address a channel or thread: %+v", messages[0])
+	}
+	if len(messages[0].Blocks) < 3 || !strings.Contains(messages[0].Blocks[2].Text.Text, "*Head:* `1111111` → `2222222` (2 by alice)") {
+		t.Errorf("unexpected push blocks: %+v", messages[0].Blocks)
+	}
+	summary := messages[1]
+	if summary.Text != "2 notifications during quiet hours" || !strings.Contains(summary.Blocks[1].Text.Text, ":large_green_circle: <https://github.com/o/r/pull/4|o/r#4> CI passed") {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestSlackNotifiersTimeOut(t *testing.T) {
+	for _, notifier := range []*SlackNotifier{NewSlackWebhookNotifier("https://hooks.slack.com/x"), NewSlackBotNotifier("xoxb-token", "#ci")} {
+		if notifier.HTTPClient == http.DefaultClient || notifier.HTTPClient.Timeout != httpTimeout {
+			t.Errorf("expected a client with a %s timeout, got %+v", httpTimeout, notifier.HTTPClient)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devblac/prw/internal/retry"
+)
+
+// TeamsNotifier posts events to a Microsoft Teams incoming webhook or workflow
+// as Adaptive Cards.
+type TeamsNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+	Retry      retry.Policy
+	Template   *MessageTemplate // renders the request body instead of the Adaptive Card
+}
+
+// NewTeamsNotifier creates a Microsoft Teams webhook notifier.
+func NewTeamsNotifier(url string) *TeamsNotifier {
+	return &TeamsNotifier{
+		URL:        url,
+		HTTPClient: newHTTPClient(),
+		Retry:      retry.DefaultPolicy(),
+	}
+}
+
+// teamsMessage wraps an Adaptive Card the way Teams webhooks expect it.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	ContentURL  *string      `json:"contentUrl"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []cardElement `json:"body"`
+	Actions []cardAction  `json:"actions,omitempty"`
+}
+
+// cardElement is an Adaptive Card TextBlock or FactSet.
+type cardElement struct {
+	Type     string     `json:"type"`
+	Text     string     `json:"text,omitempty"`
+	Size     string     `json:"size,omitempty"`
+	Weight   string     `json:"weight,omitempty"`
+	Color    string     `json:"color,omitempty"`
+	IsSubtle bool       `json:"isSubtle,omitempty"`
+	Wrap     bool       `json:"wrap,omitempty"`
+	Facts    []cardFact `json:"facts,omitempty"`
+}
+
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type cardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// teamsMaxSummaryLines is the number of events listed in a summary card.
+const teamsMaxSummaryLines = 20
+
+// teamsColors maps event tones to Adaptive Card text colors.
+var teamsColors = map[string]string{
+	toneGood:    "Good",
+	toneBad:     "Attention",
+	toneWarning: "Warning",
+	toneInfo:    "Accent",
+	toneNeutral: "Default",
+}
+
+// Notify posts the event to the Teams webhook.
+func (t *TeamsNotifier) Notify(event *StatusChangeEvent) error {
+	if t.URL == "" {
+		return nil
+	}
+	if sent, err := postTemplate(t.HTTPClient, t.Retry, t.URL, t.Template, event); sent {
+		return err
+	}
+	return postJSON(t.HTTPClient, t.Retry, t.URL, teamsCard(teamsEventCard(event)))
+}
+
+// NotifySummary posts one card listing the events held during quiet hours.
+func (t *TeamsNotifier) NotifySummary(events []*StatusChangeEvent) error {
+	if t.URL == "" {
+		return nil
+	}
+
+	body := []cardElement{{Type: "TextBlock", Text: summaryTitle(events), Size: "Medium", Weight: "Bolder", Wrap: true}}
+	for i, event := range events {
+		if i == teamsMaxSummaryLines {
+			body = append(body, cardElement{Type: "TextBlock", Text: fmt.Sprintf("…and %d more", len(events)-i), IsSubtle: true})
+			break
+		}
+		body = append(body, cardElement{
+			Type: "TextBlock",
+			Text: fmt.Sprintf("[%s/%s#%d](%s): %s", event.Owner, event.Repo, event.Number, event.URL(), eventHeadline(event)),
+			Wrap: true,
+		})
+	}
+	return postJSON(t.HTTPClient, t.Retry, t.URL, teamsCard(adaptiveCard{Body: body}))
+}
+
+// teamsCard wraps a card body in a Teams message.
+func teamsCard(card adaptiveCard) teamsMessage {
+	card.Schema = "http://adaptivecards.io/schemas/adaptive-card.json"
+	card.Type = "AdaptiveCard"
+	card.Version = "1.4"
+	return teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
+
+// teamsEventCard builds the Adaptive Card of an event.
+func teamsEventCard(event *StatusChangeEvent) adaptiveCard {
+	body := []cardElement{
+		{Type: "TextBlock", Text: fmt.Sprintf("%s/%s#%d", event.Owner, event.Repo, event.Number), IsSubtle: true},
+	}
+	if event.Title != "" {
+		body = append(body, cardElement{Type: "TextBlock", Text: event.Title, Size: "Medium", Weight: "Bolder", Wrap: true})
+	}
+	body = append(body, cardElement{
+		Type:   "TextBlock",
+		Text:   eventHeadline(event),
+		Weight: "Bolder",
+		Color:  teamsColors[eventTone(event)],
+		Wrap:   true,
+	})
+
+	if facts := eventFacts(event); len(facts) > 0 {
+		set := cardElement{Type: "FactSet"}
+		for _, fact := range facts {
+			set.Facts = append(set.Facts, cardFact{Title: fact.Name, Value: fact.Value})
+		}
+		body = append(body, set)
+	}
+
+	var footer string
+	if event.SHA != "" {
+		footer = shortSHA(event.SHA)
+	}
+	if !event.Timestamp.IsZero() {
+		if footer != "" {
+			footer += " · "
+		}
+		footer += event.Timestamp.UTC().Format(time.RFC3339)
+	}
+	if footer != "" {
+		body = append(body, cardElement{Type: "TextBlock", Text: footer, Size: "Small", IsSubtle: true})
+	}
+
+	return adaptiveCard{
+		Body:    body,
+		Actions: []cardAction{{Type: "Action.OpenUrl", Title: "Open pull request", URL: event.URL()}},
+	}
+}
@@ -0,0 +1,25 @@
+package notify
+
+import "testing"
+
+func TestTeamsNotifierGolden(t *testing.T) {
+	for name, event := range goldenEvents {
+		t.Run(name, func(t *testing.T) {
+			got := capturePost(t, func(url string) error {
+				return NewTeamsNotifier(url).Notify(event)
+			})
+			assertGolden(t, "teams_"+name+".golden", got)
+		})
+	}
+
+	got := capturePost(t, func(url string) error {
+		return NewTeamsNotifier(url).NotifySummary(goldenSummary())
+	})
+	assertGolden(t, "teams_summary.golden", got)
+}
+
+func TestTeamsNotifierEmptyURL(t *testing.T) {
+	if err := NewTeamsNotifier("").Notify(goldenEvents["failure"]); err != nil {
+		t.Errorf("expected no error without a URL, got %v", err)
+	}
+}
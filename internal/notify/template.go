@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+// Notifiers whose messages can be replaced with user-defined templates.
+// TemplateWebhook covers every webhook_url format, including Discord and Teams.
+const (
+	TemplateConsole = "console"
+	TemplateNative  = "native"
+	TemplateWebhook = "webhook"
+	TemplateSlack   = "slack"
+	TemplateEmail   = "email"
+)
+
+// TemplateDefault is the event key of the template used for event types
+// without a template of their own.
+const TemplateDefault = "default"
+
+// TemplateNotifiers lists the notifiers that support templates.
+var TemplateNotifiers = []string{TemplateConsole, TemplateNative, TemplateWebhook, TemplateSlack, TemplateEmail}
+
+// NotifiedEventTypes lists the event types delivered to notifiers.
+var NotifiedEventTypes = []string{
+	EventStatusChange, EventMerged, EventClosed,
+	EventApproved, EventChangesRequested, EventReviewRequested,
+	EventReadyToMerge, EventPushed, EventForcePushed, EventStuck,
+}
+
+// IsValidTemplateNotifier reports whether notifier supports templates.
+func IsValidTemplateNotifier(notifier string) bool {
+	for _, n := range TemplateNotifiers {
+		if n == notifier {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidTemplateEvent reports whether key is an event type or TemplateDefault.
+func IsValidTemplateEvent(key string) bool {
+	if key == TemplateDefault {
+		return true
+	}
+	for _, t := range NotifiedEventTypes {
+		if t == key {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageTemplate renders user-defined messages for events, with one
+// text/template per event type and an optional default. Templates execute
+// against the *StatusChangeEvent, so fields such as {{.Owner}} and methods
+// such as {{.URL}} are available alongside the functions of templateFuncs.
+type MessageTemplate struct {
+	templates map[string]*template.Template
+}
+
+// templateFuncs are the helper functions available in message templates.
+var templateFuncs = template.FuncMap{
+	"duration": FormatDuration,
+	"prURL":    func(e *StatusChangeEvent) string { return e.URL() },
+	"emoji":    stateEmoji,
+	"headline": eventHeadline,
+	"failing":  FailingChecks,
+	"shortSHA": shortSHA,
+	"join":     strings.Join,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// NewMessageTemplate parses the templates of a notifier, keyed by event type
+// or TemplateDefault. It returns nil if sources is empty.
+func NewMessageTemplate(notifier string, sources map[string]string) (*MessageTemplate, error) {
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	t := &MessageTemplate{templates: make(map[string]*template.Template, len(sources))}
+	for _, key := range keys {
+		if !IsValidTemplateEvent(key) {
+			return nil, fmt.Errorf("unknown event type %q in %s templates", key, notifier)
+		}
+		parsed, err := template.New(notifier + "." + key).Funcs(templateFuncs).Parse(sources[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template for %s: %w", notifier, key, err)
+		}
+		t.templates[key] = parsed
+	}
+	return t, nil
+}
+
+// Render renders the template for the event's type, falling back to the
+// default template. It reports false if neither exists; a nil MessageTemplate
+// has no templates.
+func (t *MessageTemplate) Render(event *StatusChangeEvent) (string, bool, error) {
+	if t == nil {
+		return "", false, nil
+	}
+	tmpl, ok := t.templates[event.EventType()]
+	if !ok {
+		tmpl, ok = t.templates[TemplateDefault]
+	}
+	if !ok {
+		return "", false, nil
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, event); err != nil {
+		return "", true, fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	return b.String(), true, nil
+}
+
+// SplitTitle splits rendered text into its first line, used as a title or
+// subject, and the remaining lines.
+func SplitTitle(text string) (title, body string) {
+	title, body, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
+
+// stateEmoji returns an emoji for a CI state, review decision, or event type.
+func stateEmoji(state string) string {
+	switch state {
+	case "success", EventApproved, ReadyStateReady:
+		return "✅"
+	case "failure", "error":
+		return "❌"
+	case "pending", EventStuck:
+		return "⏳"
+	case EventChangesRequested:
+		return "✋"
+	case EventReviewRequested, github.ReviewRequired:
+		return "👀"
+	case EventReadyToMerge:
+		return "🚀"
+	case EventMerged:
+		return "🎉"
+	case EventClosed:
+		return "🚫"
+	case EventPushed:
+		return "⬆️"
+	case EventForcePushed:
+		return "⚠️"
+	default:
+		return "🔔"
+	}
+}
+
+// SampleEvent returns a realistic event of the given type for trying out templates.
+func SampleEvent(eventType string) *StatusChangeEvent {
+	event := &StatusChangeEvent{
+		Type:          eventType,
+		Owner:         "octo-org",
+		Repo:          "api",
+		Number:        1347,
+		Title:         "Add rate limiting to the public API",
+		PreviousState: "pending",
+		CurrentState:  "failure",
+		SHA:           "9fceb02d0ae598e95dc970b74767f19372d61af8",
+		Checks: []github.Check{
+			{Name: "build", State: "success"},
+			{Name: "unit-tests", State: "success"},
+			{Name: "e2e-tests", State: "failure"},
+		},
+		Duration:  7*time.Minute + 42*time.Second,
+		Timestamp: time.Date(2025, 12, 6, 10, 32, 15, 0, time.UTC),
+	}
+
+	switch eventType {
+	case EventMerged, EventClosed:
+		event.PreviousState, event.CurrentState = "success", "success"
+		event.Checks[2].State = "success"
+		event.Duration = 0
+	case EventApproved:
+		event.PreviousState, event.CurrentState = github.ReviewRequired, github.ReviewApproved
+		event.Duration = 0
+	case EventChangesRequested:
+		event.PreviousState, event.CurrentState = github.ReviewRequired, github.ReviewChangesRequested
+		event.Duration = 0
+	case EventReviewRequested:
+		event.PreviousState, event.CurrentState = github.ReviewNone, github.ReviewRequired
+		event.Reviewers = []string{"octocat", "hubot"}
+		event.Duration = 0
+	case EventReadyToMerge:
+		event.PreviousState, event.CurrentState = ReadyStateNotReady, ReadyStateReady
+		event.Checks[2].State = "success"
+		event.Duration = 0
+	case EventPushed, EventForcePushed:
+		event.PreviousState, event.CurrentState = "failure", "pending"
+		event.PreviousSHA = "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+		event.Commits = 2
+		event.Authors = []string{"octocat"}
+		event.Checks = nil
+		event.Duration = 0
+	case EventStuck:
+		event.PreviousState, event.CurrentState = "pending", "pending"
+		event.Checks[2].State = "pending"
+		event.Duration = 47 * time.Minute
+	}
+	return event
+}
@@ -0,0 +1,100 @@
+package notify
+
+import "testing"
+
+func TestMessageTemplateRender(t *testing.T) {
+	tmpl, err := NewMessageTemplate("console", map[string]string{
+		EventStatusChange: `{{emoji .CurrentState}} {{.Owner}}/{{.Repo}}#{{.Number}} {{.PreviousState}} → {{.CurrentState}} after {{duration .Duration}}: {{join (failing .Checks) ", "}} {{prURL .}}`,
+		TemplateDefault:   `{{upper .EventType}} {{shortSHA .SHA}} {{headline .}}`,
+	})
+	if err != nil {
+		t.Fatalf("NewMessageTemplate failed: %v", err)
+	}
+
+	got, ok, err := tmpl.Render(SampleEvent(EventStatusChange))
+	if !ok || err != nil {
+		t.Fatalf("Render = %v, %v", ok, err)
+	}
+	want := "❌ octo-org/api#1347 pending → failure after 7m42s: e2e-tests https://github.com/octo-org/api/pull/1347"
+	if got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+
+	got, ok, err = tmpl.Render(SampleEvent(EventMerged))
+	if !ok || err != nil || got != "MERGED 9fceb02 Merged" {
+		t.Errorf("expected the default template for merges, got %q, %v, %v", got, ok, err)
+	}
+
+	var none *MessageTemplate
+	if _, ok, _ := none.Render(SampleEvent(EventMerged)); ok {
+		t.Error("expected a nil template to render nothing")
+	}
+}
+
+func TestMessageTemplateFallsBackWithoutDefault(t *testing.T) {
+	tmpl, err := NewMessageTemplate("native", map[string]string{EventMerged: "merged"})
+	if err != nil {
+		t.Fatalf("NewMessageTemplate failed: %v", err)
+	}
+	if _, ok, _ := tmpl.Render(SampleEvent(EventStatusChange)); ok {
+		t.Error("expected no template for status changes")
+	}
+}
+
+func TestNewMessageTemplateErrors(t *testing.T) {
+	if tmpl, err := NewMessageTemplate("console", nil); tmpl != nil || err != nil {
+		t.Errorf("expected nil for no templates, got %v, %v", tmpl, err)
+	}
+	if _, err := NewMessageTemplate("console", map[string]string{"deployed": "x"}); err == nil {
+		t.Error("expected an unknown event type to be rejected")
+	}
+	if _, err := NewMessageTemplate("console", map[string]string{TemplateDefault: "{{.Owner"}); err == nil {
+		t.Error("expected a syntax error to be rejected")
+	}
+	if _, err := NewMessageTemplate("console", map[string]string{TemplateDefault: "{{nope .Owner}}"}); err == nil {
+		t.Error("expected an unknown function to be rejected")
+	}
+
+	tmpl, err := NewMessageTemplate("console", map[string]string{TemplateDefault: "{{.Missing}}"})
+	if err != nil {
+		t.Fatalf("NewMessageTemplate failed: %v", err)
+	}
+	if _, ok, err := tmpl.Render(SampleEvent(EventMerged)); !ok || err == nil {
+		t.Error("expected an unknown field to fail when rendering")
+	}
+}
+
+func TestWebhookNotifierTemplate(t *testing.T) {
+	tmpl, err := NewMessageTemplate("webhook", map[string]string{
+		TemplateDefault: `{"text": {{json (printf "%s/%s#%d %s" .Owner .Repo .Number .Title)}}, "state": {{json .CurrentState}}}`,
+	})
+	if err != nil {
+		t.Fatalf("NewMessageTemplate failed: %v", err)
+	}
+
+	event := SampleEvent(EventStatusChange)
+	event.Title = `Quote "this"`
+	for _, build := range []func(url string) Notifier{
+		func(url string) Notifier { n := NewWebhookNotifier(url); n.Template = tmpl; return n },
+		func(url string) Notifier { n := NewDiscordNotifier(url); n.Template = tmpl; return n },
+		func(url string) Notifier { n := NewTeamsNotifier(url); n.Template = tmpl; return n },
+	} {
+		body := capturePost(t, func(url string) error { return build(url).Notify(event) })
+		want := "{\n  \"text\": \"octo-org/api#1347 Quote \\\"this\\\"\",\n  \"state\": \"failure\"\n}\n"
+		if string(body) != want {
+			t.Errorf("posted %s, want %s", body, want)
+		}
+	}
+}
+
+func TestSampleEvents(t *testing.T) {
+	for _, eventType := range NotifiedEventTypes {
+		event := SampleEvent(eventType)
+		if event.EventType() != eventType || eventHeadline(event) == "" {
+			t.Errorf("unexpected sample event for %s: %+v", eventType, event)
+		}
+	}
+	if !SampleEvent(EventStatusChange).IsFailure() {
+		t.Error("expected the sample status change to be a failure")
+	}
+}
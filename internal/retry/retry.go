@@ -0,0 +1,212 @@
+package retry
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// secondaryRateLimitWait is how long GitHub asks clients to wait after hitting a
+// secondary rate limit when the response carries no Retry-After header.
+const secondaryRateLimitWait = time.Minute
+
+// sleep is a variable so tests can skip real waits.
+var sleep = time.Sleep
+
+// Policy describes how transient HTTP failures are retried.
+// A zero Policy performs a single attempt.
+type Policy struct {
+	MaxAttempts int           // total attempts, including the first
+	BaseDelay   time.Duration // delay before the first retry, doubled for each further retry
+	MaxDelay    time.Duration // upper bound for any single wait, including Retry-After
+	Jitter      float64       // fraction of each backoff delay that is randomized (0-1)
+}
+
+// DefaultPolicy returns the retry policy used when none is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// Backoff returns the exponential backoff delay before retry number n (starting at 1),
+// with jitter applied and capped at MaxDelay.
+func (p Policy) Backoff(n int) time.Duration {
+	if n < 1 {
+		n = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(n-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		// Spread the delay over [delay*(1-jitter), delay]
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// Do sends the request built by newRequest, retrying transient failures: network
+// timeouts and resets, 5xx responses, 429 responses, and GitHub secondary rate
+// limits. Retry-After headers are honored as long as they fit within MaxDelay.
+// newRequest is called once per attempt so request bodies can be replayed.
+func (p Policy) Do(client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	return p.do(client, newRequest, true)
+}
+
+// DoNonIdempotent is Do for requests that must not take effect twice, such as
+// re-running a job or posting a message. It only retries failures where the
+// request definitely never reached the server: connection errors while dialing,
+// and 429 or secondary rate limit responses, which GitHub and Slack reject
+// without processing. Timeouts, resets, and 5xx responses are returned as is.
+func (p Policy) DoNonIdempotent(client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	return p.do(client, newRequest, false)
+}
+
+// do implements Do and DoNonIdempotent; idempotent allows retrying failures
+// after which the request may have been processed.
+func (p Policy) do(client *http.Client, newRequest func() (*http.Request, error), idempotent bool) (*http.Response, error) {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		last := attempt >= attempts
+
+		if err != nil {
+			retryable := isNotDelivered(err) || (idempotent && isTransientError(err))
+			if last || !retryable {
+				return nil, err
+			}
+			sleep(p.Backoff(attempt))
+			continue
+		}
+
+		wait, retryable := p.retryDelay(resp, attempt)
+		if !idempotent && resp.StatusCode >= 500 {
+			retryable = false
+		}
+		if last || !retryable {
+			return resp, nil
+		}
+
+		// Drain and close the failed response so the connection can be reused.
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		sleep(wait)
+	}
+}
+
+// retryDelay reports whether a response should be retried and how long to wait first.
+func (p Policy) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch {
+	case resp.StatusCode >= 500:
+		if wait, ok := retryAfter(resp.Header); ok {
+			return wait, p.fits(wait)
+		}
+		return p.Backoff(attempt), true
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+		if wait, ok := retryAfter(resp.Header); ok {
+			return wait, p.fits(wait)
+		}
+		// A primary rate limit only resets at X-RateLimit-Reset; leave it to the caller.
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return 0, false
+		}
+		if isSecondaryRateLimit(resp) {
+			return secondaryRateLimitWait, p.fits(secondaryRateLimitWait)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return p.Backoff(attempt), true
+		}
+		return 0, false
+	default:
+		return 0, false
+	}
+}
+
+// fits reports whether a server-requested wait is within MaxDelay.
+func (p Policy) fits(wait time.Duration) bool {
+	return p.MaxDelay <= 0 || wait <= p.MaxDelay
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isSecondaryRateLimit peeks at a 403/429 body for GitHub's secondary rate limit message.
+// The body is replaced so callers can still read it.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(peek)), "secondary rate limit")
+}
+
+// isTransientError reports whether a transport error is worth retrying.
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// isNotDelivered reports whether a transport error happened before the request
+// could reach the server, so sending it again cannot duplicate it.
+func isNotDelivered(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
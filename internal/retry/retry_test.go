@@ -0,0 +1,355 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noSleep replaces sleep for the duration of a test and records requested waits.
+func noSleep(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	old := sleep
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = old })
+	return &waits
+}
+
+// flakyServer fails the first n requests with the given handler before succeeding.
+func flakyServer(n int32, fail http.HandlerFunc) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			fail(w, r)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	return server, &calls
+}
+
+func get(url string) func() (*http.Request, error) {
+	return func() (*http.Request, error) {
+		return http.NewRequest("GET", url, nil)
+	}
+}
+
+func TestDoRetriesServerErrors(t *testing.T) {
+	waits := noSleep(t)
+	server, calls := flakyServer(2, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", *calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 100*time.Millisecond || (*waits)[1] != 200*time.Millisecond {
+		t.Errorf("expected exponential backoff waits [100ms 200ms], got %v", *waits)
+	}
+}
+
+func TestDoGivesUpAfterMaxAttempts(t *testing.T) {
+	noSleep(t)
+	server, calls := flakyServer(5, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, "unavailable")
+	})
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected last 503 response, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "unavailable" {
+		t.Errorf("expected last response body to be readable, got %q", body)
+	}
+	if *calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", *calls)
+	}
+}
+
+func TestDoZeroPolicySingleAttempt(t *testing.T) {
+	noSleep(t)
+	server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+
+	resp, err := Policy{}.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if *calls != 1 {
+		t.Errorf("expected a single attempt, got %d", *calls)
+	}
+}
+
+func TestDoHonorsRetryAfter(t *testing.T) {
+	waits := noSleep(t)
+	server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Second}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if *calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", *calls)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("expected a 7s Retry-After wait, got %v", *waits)
+	}
+}
+
+func TestDoRetryAfterBeyondMaxDelay(t *testing.T) {
+	waits := noSleep(t)
+	server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Second}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || *calls != 1 || len(*waits) != 0 {
+		t.Errorf("expected to give up without waiting, got status %d after %d calls and waits %v", resp.StatusCode, *calls, *waits)
+	}
+}
+
+func TestDoSecondaryRateLimit(t *testing.T) {
+	waits := noSleep(t)
+	server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`)
+	})
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Minute}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || *calls != 2 {
+		t.Errorf("expected retry after secondary rate limit, got status %d after %d calls", resp.StatusCode, *calls)
+	}
+	if len(*waits) != 1 || (*waits)[0] != time.Minute {
+		t.Errorf("expected a one minute wait, got %v", *waits)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	noSleep(t)
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		body   string
+	}{
+		{name: "not found", status: http.StatusNotFound},
+		{name: "plain forbidden", status: http.StatusForbidden, body: `{"message": "Resource not accessible"}`},
+		{
+			name:   "primary rate limit",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Remaining": "0"},
+			body:   `{"message": "API rate limit exceeded"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+			defer server.Close()
+
+			resp, err := DefaultPolicy().Do(server.Client(), get(server.URL))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if *calls != 1 || resp.StatusCode != tt.status {
+				t.Errorf("expected one attempt returning %d, got %d calls returning %d", tt.status, *calls, resp.StatusCode)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Errorf("expected body %q to be preserved, got %q", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestDoRetriesConnectionErrors(t *testing.T) {
+	noSleep(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Drop the connection without a response.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	policy := Policy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	resp, err := policy.Do(server.Client(), get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 2 {
+		t.Errorf("expected retry after dropped connection, got %d calls", calls)
+	}
+}
+
+func TestDoNonIdempotent(t *testing.T) {
+	noSleep(t)
+	policy := Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Minute}
+	post := func(url string) func() (*http.Request, error) {
+		return func() (*http.Request, error) {
+			return http.NewRequest("POST", url, nil)
+		}
+	}
+
+	// Failures after which the server may have acted on the request are not retried.
+	ambiguous := map[string]http.HandlerFunc{
+		"server error": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		},
+		"dropped connection": func(w http.ResponseWriter, r *http.Request) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		},
+	}
+	for name, fail := range ambiguous {
+		t.Run(name, func(t *testing.T) {
+			server, calls := flakyServer(1, fail)
+			defer server.Close()
+
+			resp, err := policy.DoNonIdempotent(server.Client(), post(server.URL))
+			if err == nil {
+				resp.Body.Close()
+			}
+			if n := atomic.LoadInt32(calls); n != 1 {
+				t.Errorf("expected a single attempt, got %d", n)
+			}
+		})
+	}
+
+	// Rejections that GitHub never processed are retried.
+	server, calls := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer server.Close()
+	resp, err := policy.DoNonIdempotent(server.Client(), post(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || *calls != 2 {
+		t.Errorf("expected a retry after 429, got status %d after %d calls", resp.StatusCode, *calls)
+	}
+
+	// Connection refused: nothing was sent.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	var dials int32
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	if _, err := policy.DoNonIdempotent(client, post(closed.URL)); err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if dials != 3 {
+		t.Errorf("expected refused connections to be retried, got %d dials", dials)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := policy.Backoff(i + 1); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.Backoff(2)
+		if got < time.Second || got > 2*time.Second {
+			t.Fatalf("jittered backoff %v outside [1s, 2s]", got)
+		}
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+
+	wait, ok := retryAfter(header)
+	if !ok {
+		t.Fatal("expected Retry-After date to parse")
+	}
+	if wait <= 25*time.Second || wait > 30*time.Second {
+		t.Errorf("unexpected wait %v", wait)
+	}
+
+	header.Set("Retry-After", "soon")
+	if _, ok := retryAfter(header); ok {
+		t.Error("expected invalid Retry-After to be ignored")
+	}
+	if !strings.Contains(header.Get("Retry-After"), "soon") {
+		t.Error("header should be left untouched")
+	}
+}
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/history"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// maxEvents is the number of recent events kept in memory for the events pane.
+const maxEvents = 50
+
+// Actions performs the operations requested from the dashboard.
+type Actions interface {
+	Open(pr config.WatchedPR) error
+	Unwatch(pr config.WatchedPR) error
+	Refresh()
+	Add(url string) (config.WatchedPR, error)
+}
+
+// App runs the interactive dashboard. PRs and Actions are required; the other
+// fields have sensible defaults.
+type App struct {
+	PRs     func() []config.WatchedPR
+	Actions Actions
+	In      io.Reader
+	Out     io.Writer
+	Size    func() (width, height int)
+	Color   bool
+	Now     func() time.Time
+
+	mu      sync.Mutex
+	events  []history.Record
+	logLine string
+	redraw  chan struct{}
+	once    sync.Once
+}
+
+func (a *App) init() {
+	a.once.Do(func() {
+		a.redraw = make(chan struct{}, 1)
+		if a.Size == nil {
+			a.Size = func() (int, int) { return 80, 24 }
+		}
+		if a.Now == nil {
+			a.Now = time.Now
+		}
+	})
+}
+
+// SetEvents seeds the events pane, oldest first.
+func (a *App) SetEvents(events []history.Record) {
+	a.mu.Lock()
+	a.events = append([]history.Record(nil), events...)
+	if len(a.events) > maxEvents {
+		a.events = a.events[len(a.events)-maxEvents:]
+	}
+	a.mu.Unlock()
+	a.Update()
+}
+
+// Update asks the dashboard to redraw. It never blocks and is safe to call from
+// any goroutine, e.g. as the watcher's cycle hook.
+func (a *App) Update() {
+	a.init()
+	select {
+	case a.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// Notify implements notify.Notifier by adding the change to the events pane.
+func (a *App) Notify(event *notify.StatusChangeEvent) error {
+	a.mu.Lock()
+	a.events = append(a.events, history.FromEvent(event))
+	if len(a.events) > maxEvents {
+		a.events = a.events[len(a.events)-maxEvents:]
+	}
+	a.mu.Unlock()
+	a.Update()
+	return nil
+}
+
+// LogWriter returns a writer whose last complete line is shown in the status line.
+// Point the watcher's output at it so its messages do not scroll the screen.
+func (a *App) LogWriter() io.Writer {
+	return &logWriter{app: a}
+}
+
+type logWriter struct {
+	app *App
+	buf bytes.Buffer
+}
+
+func (l *logWriter) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+	var last string
+	for {
+		line, err := l.buf.ReadString('\n')
+		if err != nil {
+			// Keep the partial line for the next write.
+			l.buf.Reset()
+			l.buf.WriteString(line)
+			break
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			last = line
+		}
+	}
+	if last != "" {
+		l.app.mu.Lock()
+		l.app.logLine = last
+		l.app.mu.Unlock()
+		l.app.Update()
+	}
+	return len(p), nil
+}
+
+// Run draws the dashboard and handles key presses until the user quits or ctx is
+// cancelled. The caller is responsible for putting the terminal into cbreak mode.
+func (a *App) Run(ctx context.Context) error {
+	a.init()
+
+	// Switch to the alternate screen and hide the cursor; undo both on exit.
+	fmt.Fprint(a.Out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(a.Out, ansiReset+"\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []Key)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := a.In.Read(buf)
+			if n > 0 {
+				select {
+				case keys <- ParseKeys(buf[:n]):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Refresh periodically so relative times and terminal size stay current.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var m Model
+	var shownLog string
+	for {
+		m.SetPRs(a.PRs())
+		a.mu.Lock()
+		m.SetEvents(a.events)
+		// Show new watcher output, but leave action results alone until it changes.
+		if a.logLine != shownLog {
+			shownLog = a.logLine
+			m.Status = shownLog
+		}
+		a.mu.Unlock()
+
+		width, height := a.Size()
+		fmt.Fprint(a.Out, Render(&m, width, height, a.Color, a.Now()))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.redraw:
+		case <-ticker.C:
+		case batch := <-keys:
+			for _, k := range batch {
+				if quit := a.handle(&m, m.HandleKey(k)); quit {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// handle carries out a command from the model and reports whether to quit.
+func (a *App) handle(m *Model, cmd Command) bool {
+	switch cmd.Kind {
+	case CmdQuit:
+		return true
+	case CmdOpen:
+		if err := a.Actions.Open(cmd.PR); err != nil {
+			m.Status = fmt.Sprintf("Failed to open %s: %v", prLabel(cmd.PR), err)
+		} else {
+			m.Status = fmt.Sprintf("Opened %s", prLabel(cmd.PR))
+		}
+	case CmdUnwatch:
+		if err := a.Actions.Unwatch(cmd.PR); err != nil {
+			m.Status = fmt.Sprintf("Failed to unwatch %s: %v", prLabel(cmd.PR), err)
+		} else {
+			m.Status = fmt.Sprintf("Stopped watching %s", prLabel(cmd.PR))
+		}
+	case CmdRefresh:
+		a.Actions.Refresh()
+		m.Status = "Checking all PRs..."
+	case CmdAdd:
+		pr, err := a.Actions.Add(cmd.URL)
+		if err != nil {
+			m.Status = fmt.Sprintf("Failed to watch %s: %v", cmd.URL, err)
+		} else {
+			m.Status = fmt.Sprintf("Now watching %s", prLabel(pr))
+		}
+	}
+	return false
+}
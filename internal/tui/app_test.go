@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+type fakeActions struct {
+	mu       sync.Mutex
+	prs      []config.WatchedPR
+	opened   []string
+	refreshs int
+}
+
+func (f *fakeActions) list() []config.WatchedPR {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]config.WatchedPR(nil), f.prs...)
+}
+
+func (f *fakeActions) Open(pr config.WatchedPR) error {
+	f.opened = append(f.opened, prLabel(pr))
+	return nil
+}
+
+func (f *fakeActions) Unwatch(pr config.WatchedPR) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, p := range f.prs {
+		if samePR(p, pr) {
+			f.prs = append(f.prs[:i], f.prs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("not watched")
+}
+
+func (f *fakeActions) Refresh() { f.refreshs++ }
+
+func (f *fakeActions) Add(url string) (config.WatchedPR, error) {
+	if !strings.Contains(url, "/pull/") {
+		return config.WatchedPR{}, fmt.Errorf("invalid PR URL")
+	}
+	pr := config.WatchedPR{Owner: "new", Repo: "repo", Number: 1}
+	f.mu.Lock()
+	f.prs = append(f.prs, pr)
+	f.mu.Unlock()
+	return pr, nil
+}
+
+func TestAppRun(t *testing.T) {
+	actions := &fakeActions{prs: testPRs()}
+	in, input := io.Pipe()
+	var out bytes.Buffer
+	app := &App{PRs: actions.list, Actions: actions, In: in, Out: &out}
+
+	// Watcher output arrives before the key presses so it cannot overwrite their results.
+	app.Notify(&notify.StatusChangeEvent{Owner: "acme", Repo: "api", Number: 3, PreviousState: "pending", CurrentState: "success"})
+	fmt.Fprintln(app.LogWriter(), "Checking 4 PRs...")
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run(context.Background()) }()
+
+	// Keys are sent one batch at a time so each is handled before the next.
+	for _, keys := range []string{"o", "r", "j", "d", "y", "anope\r", "ahttps://github.com/new/repo/pull/1\r"} {
+		if _, err := input.Write([]byte(keys)); err != nil {
+			t.Fatalf("write keys: %v", err)
+		}
+	}
+	input.Write([]byte("q"))
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after q")
+	}
+
+	if len(actions.opened) != 1 || actions.opened[0] != "acme/api#3" {
+		t.Errorf("opened = %v", actions.opened)
+	}
+	if actions.refreshs != 1 {
+		t.Errorf("refreshes = %d, want 1", actions.refreshs)
+	}
+	var labels []string
+	for _, pr := range actions.list() {
+		labels = append(labels, prLabel(pr))
+	}
+	if strings.Contains(strings.Join(labels, " "), "acme/api#42") {
+		t.Errorf("acme/api#42 should have been unwatched: %v", labels)
+	}
+	if !strings.Contains(strings.Join(labels, " "), "new/repo#1") {
+		t.Errorf("new/repo#1 should have been added: %v", labels)
+	}
+
+	screen := out.String()
+	for _, want := range []string{
+		"\x1b[?1049h",
+		"Checking 4 PRs...",
+		"Stopped watching acme/api#42",
+		"Failed to watch nope: invalid PR URL",
+		"Now watching new/repo#1",
+		"acme/api#3 pending → success",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen output missing %q", want)
+		}
+	}
+	if !strings.HasSuffix(screen, "\x1b[?1049l") {
+		t.Error("expected the alternate screen to be restored on exit")
+	}
+}
+
+func TestAppRunCancelled(t *testing.T) {
+	in, _ := io.Pipe()
+	app := &App{PRs: func() []config.WatchedPR { return nil }, Actions: &fakeActions{}, In: in, Out: io.Discard}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := app.Run(ctx); err != context.Canceled {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestLogWriterKeepsLastLine(t *testing.T) {
+	app := &App{}
+	w := app.LogWriter()
+	fmt.Fprint(w, "first\nsecond\npart")
+	if app.logLine != "second" {
+		t.Errorf("logLine = %q, want second", app.logLine)
+	}
+	fmt.Fprint(w, "ial\n\n")
+	if app.logLine != "partial" {
+		t.Errorf("logLine = %q, want partial", app.logLine)
+	}
+}
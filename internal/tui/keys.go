@@ -0,0 +1,56 @@
+package tui
+
+import "unicode/utf8"
+
+// Special keys. Printable characters are reported through Key.Rune.
+const (
+	KeyNone = iota
+	KeyUp
+	KeyDown
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyCtrlC
+)
+
+// Key is a single key press.
+type Key struct {
+	Special int
+	Rune    rune
+}
+
+// ParseKeys decodes raw terminal input into key presses. A lone ESC byte at the
+// end of the input is reported as KeyEsc; arrow keys arrive as ESC [ A/B.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == 0x1b:
+			if i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				switch b[i+2] {
+				case 'A':
+					keys = append(keys, Key{Special: KeyUp})
+				case 'B':
+					keys = append(keys, Key{Special: KeyDown})
+				}
+				i += 2
+				continue
+			}
+			keys = append(keys, Key{Special: KeyEsc})
+		case c == '\r' || c == '\n':
+			keys = append(keys, Key{Special: KeyEnter})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, Key{Special: KeyBackspace})
+		case c == 0x03:
+			keys = append(keys, Key{Special: KeyCtrlC})
+		case c >= 0x20 && c < 0x7f:
+			keys = append(keys, Key{Rune: rune(c)})
+		case c >= 0x80:
+			// Decode UTF-8 so URLs and filters with non-ASCII characters survive.
+			r, size := utf8.DecodeRune(b[i:])
+			keys = append(keys, Key{Rune: r})
+			i += size - 1
+		}
+	}
+	return keys
+}
@@ -0,0 +1,275 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
+)
+
+// Sort orders for the PR table.
+const (
+	SortRepo = iota
+	SortStatus
+	SortChecked
+	sortCount
+)
+
+var sortNames = [...]string{"repo", "status", "last checked"}
+
+// Input modes.
+const (
+	modeNormal = iota
+	modeFilter
+	modeAdd
+	modeConfirmUnwatch
+)
+
+// Commands returned by Model.HandleKey for the app to carry out.
+const (
+	CmdNone = iota
+	CmdQuit
+	CmdOpen
+	CmdUnwatch
+	CmdRefresh
+	CmdAdd
+)
+
+// Command is an action requested by the user.
+type Command struct {
+	Kind int
+	PR   config.WatchedPR // for CmdOpen and CmdUnwatch
+	URL  string           // for CmdAdd
+}
+
+// Model holds the dashboard state. It is not safe for concurrent use.
+type Model struct {
+	prs    []config.WatchedPR
+	rows   []config.WatchedPR // prs after filtering and sorting
+	events []history.Record
+
+	sort   int
+	filter string
+	cursor int
+
+	mode  int
+	input string
+
+	// Status is a one-line message shown above the help line.
+	Status string
+}
+
+// SetPRs replaces the watched PRs, keeping the selection on the same PR when possible.
+func (m *Model) SetPRs(prs []config.WatchedPR) {
+	selected, hadSelection := m.Selected()
+	m.prs = prs
+	m.refreshRows()
+	if hadSelection {
+		for i, pr := range m.rows {
+			if samePR(pr, selected) {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	m.clampCursor()
+}
+
+// SetEvents replaces the recent events, oldest first.
+func (m *Model) SetEvents(events []history.Record) {
+	m.events = events
+}
+
+// Rows returns the PRs as currently filtered and sorted.
+func (m *Model) Rows() []config.WatchedPR {
+	return m.rows
+}
+
+// Selected returns the PR under the cursor.
+func (m *Model) Selected() (config.WatchedPR, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return config.WatchedPR{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// HandleKey applies a key press and returns the command it triggers, if any.
+func (m *Model) HandleKey(k Key) Command {
+	if k.Special == KeyCtrlC {
+		return Command{Kind: CmdQuit}
+	}
+
+	switch m.mode {
+	case modeFilter:
+		m.editInput(k, func(value string) {
+			m.filter = value
+		})
+		m.filter = m.input
+		m.refreshRows()
+		m.clampCursor()
+		return Command{}
+	case modeAdd:
+		var cmd Command
+		m.editInput(k, func(value string) {
+			if value = strings.TrimSpace(value); value != "" {
+				cmd = Command{Kind: CmdAdd, URL: value}
+			}
+		})
+		return cmd
+	case modeConfirmUnwatch:
+		m.mode = modeNormal
+		if pr, ok := m.Selected(); ok && (k.Rune == 'y' || k.Rune == 'Y') {
+			return Command{Kind: CmdUnwatch, PR: pr}
+		}
+		m.Status = "Unwatch cancelled."
+		return Command{}
+	}
+
+	switch {
+	case k.Special == KeyUp || k.Rune == 'k':
+		m.cursor--
+		m.clampCursor()
+	case k.Special == KeyDown || k.Rune == 'j':
+		m.cursor++
+		m.clampCursor()
+	case k.Special == KeyEnter || k.Rune == 'o':
+		if pr, ok := m.Selected(); ok {
+			return Command{Kind: CmdOpen, PR: pr}
+		}
+	case k.Rune == 'd' || k.Rune == 'x':
+		if pr, ok := m.Selected(); ok {
+			m.mode = modeConfirmUnwatch
+			m.Status = fmt.Sprintf("Unwatch %s? (y/n)", prLabel(pr))
+		}
+	case k.Rune == 'r':
+		return Command{Kind: CmdRefresh}
+	case k.Rune == 'a':
+		m.mode = modeAdd
+		m.input = ""
+	case k.Rune == '/':
+		m.mode = modeFilter
+		m.input = m.filter
+	case k.Rune == 's':
+		m.sort = (m.sort + 1) % sortCount
+		m.refreshRows()
+	case k.Special == KeyEsc:
+		if m.filter != "" {
+			m.filter = ""
+			m.refreshRows()
+			m.clampCursor()
+		}
+	case k.Rune == 'q':
+		return Command{Kind: CmdQuit}
+	}
+	return Command{}
+}
+
+// editInput handles typing in the filter and add prompts. submit is called on Enter.
+func (m *Model) editInput(k Key, submit func(string)) {
+	switch {
+	case k.Special == KeyEnter:
+		m.mode = modeNormal
+		submit(m.input)
+	case k.Special == KeyEsc:
+		m.mode = modeNormal
+		m.input = ""
+	case k.Special == KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case k.Rune != 0:
+		m.input += string(k.Rune)
+	}
+}
+
+func (m *Model) clampCursor() {
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// refreshRows rebuilds the visible rows from the PRs, filter, and sort order.
+func (m *Model) refreshRows() {
+	filter := strings.ToLower(strings.TrimSpace(m.filter))
+	rows := make([]config.WatchedPR, 0, len(m.prs))
+	for _, pr := range m.prs {
+		if filter == "" || strings.Contains(strings.ToLower(searchText(pr)), filter) {
+			rows = append(rows, pr)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch m.sort {
+		case SortStatus:
+			if ra, rb := statusRank(DisplayState(a)), statusRank(DisplayState(b)); ra != rb {
+				return ra < rb
+			}
+		case SortChecked:
+			if !a.LastChecked.Equal(b.LastChecked) {
+				return a.LastChecked.After(b.LastChecked)
+			}
+		}
+		if la, lb := repoLabel(a), repoLabel(b); la != lb {
+			return la < lb
+		}
+		return a.Number < b.Number
+	})
+	m.rows = rows
+}
+
+// DisplayState is the state shown for a PR: merged/closed for finished PRs,
+// otherwise the last known CI state.
+func DisplayState(pr config.WatchedPR) string {
+	if pr.IsClosed() {
+		return pr.PRState
+	}
+	if pr.LastKnownState == "" {
+		return "unknown"
+	}
+	return github.NormalizeState(pr.LastKnownState)
+}
+
+// statusRank orders states by how much attention they need.
+func statusRank(state string) int {
+	switch state {
+	case "failure":
+		return 0
+	case "error":
+		return 1
+	case "pending":
+		return 2
+	case "unknown":
+		return 3
+	case "success":
+		return 4
+	default:
+		return 5
+	}
+}
+
+func searchText(pr config.WatchedPR) string {
+	return fmt.Sprintf("%s %s %s", prLabel(pr), DisplayState(pr), pr.Title)
+}
+
+func repoLabel(pr config.WatchedPR) string {
+	label := pr.Owner + "/" + pr.Repo
+	if host := pr.HostName(); host != github.DefaultHost {
+		label = host + "/" + label
+	}
+	return label
+}
+
+func prLabel(pr config.WatchedPR) string {
+	return fmt.Sprintf("%s#%d", repoLabel(pr), pr.Number)
+}
+
+func samePR(a, b config.WatchedPR) bool {
+	return a.HostName() == b.HostName() && a.Owner == b.Owner && a.Repo == b.Repo && a.Number == b.Number
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+)
+
+func testPRs() []config.WatchedPR {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []config.WatchedPR{
+		{Owner: "octo", Repo: "web", Number: 7, Title: "Fix login", LastKnownState: "success", LastChecked: now.Add(-time.Hour)},
+		{Owner: "acme", Repo: "api", Number: 42, Title: "Add endpoint", LastKnownState: "failure", LastChecked: now.Add(-2 * time.Hour)},
+		{Owner: "acme", Repo: "api", Number: 3, Title: "Bump deps", LastKnownState: "pending", LastChecked: now},
+		{Owner: "acme", Repo: "cli", Number: 9, Title: "Old work", LastKnownState: "success", PRState: "merged", LastChecked: now.Add(-time.Minute)},
+	}
+}
+
+func rowLabels(m *Model) []string {
+	var labels []string
+	for _, pr := range m.Rows() {
+		labels = append(labels, prLabel(pr))
+	}
+	return labels
+}
+
+func typeKeys(m *Model, s string) Command {
+	var cmd Command
+	for _, k := range ParseKeys([]byte(s)) {
+		cmd = m.HandleKey(k)
+	}
+	return cmd
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("j\x1b[A\x1b[B\r\x7f\x03\x1bé"))
+	want := []Key{
+		{Rune: 'j'},
+		{Special: KeyUp},
+		{Special: KeyDown},
+		{Special: KeyEnter},
+		{Special: KeyBackspace},
+		{Special: KeyCtrlC},
+		{Special: KeyEsc},
+		{Rune: 'é'},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseKeys() = %+v, want %+v", got, want)
+	}
+}
+
+func TestModelSort(t *testing.T) {
+	var m Model
+	m.SetPRs(testPRs())
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"repo", []string{"acme/api#3", "acme/api#42", "acme/cli#9", "octo/web#7"}},
+		{"status", []string{"acme/api#42", "acme/api#3", "octo/web#7", "acme/cli#9"}},
+		{"last checked", []string{"acme/api#3", "acme/cli#9", "octo/web#7", "acme/api#42"}},
+		{"repo", []string{"acme/api#3", "acme/api#42", "acme/cli#9", "octo/web#7"}},
+	}
+	for i, tt := range tests {
+		if i > 0 {
+			typeKeys(&m, "s")
+		}
+		if got := sortNames[m.sort]; got != tt.name {
+			t.Fatalf("sort = %q, want %q", got, tt.name)
+		}
+		if got := rowLabels(&m); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %s: rows = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestModelFilter(t *testing.T) {
+	var m Model
+	m.SetPRs(testPRs())
+
+	typeKeys(&m, "/API")
+	if got, want := rowLabels(&m), []string{"acme/api#3", "acme/api#42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filter rows = %v, want %v", got, want)
+	}
+
+	// Enter keeps the filter; typing in normal mode no longer edits it.
+	typeKeys(&m, "\r")
+	if m.filter != "API" || m.mode != modeNormal {
+		t.Errorf("after enter: filter = %q, mode = %d", m.filter, m.mode)
+	}
+
+	// Filter matches status and title too.
+	typeKeys(&m, "/\x7f\x7f\x7fmerged\r")
+	if got, want := rowLabels(&m), []string{"acme/cli#9"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("status filter rows = %v, want %v", got, want)
+	}
+
+	typeKeys(&m, "\x1b")
+	if len(m.Rows()) != 4 {
+		t.Errorf("esc should clear the filter, got %d rows", len(m.Rows()))
+	}
+}
+
+func TestModelCommands(t *testing.T) {
+	var m Model
+	m.SetPRs(testPRs())
+
+	if cmd := typeKeys(&m, "jo"); cmd.Kind != CmdOpen || prLabel(cmd.PR) != "acme/api#42" {
+		t.Errorf("open = %+v", cmd)
+	}
+	if cmd := typeKeys(&m, "r"); cmd.Kind != CmdRefresh {
+		t.Errorf("refresh = %+v", cmd)
+	}
+
+	// Unwatch needs confirmation.
+	if cmd := typeKeys(&m, "dn"); cmd.Kind != CmdNone {
+		t.Errorf("cancelled unwatch = %+v", cmd)
+	}
+	if cmd := typeKeys(&m, "dy"); cmd.Kind != CmdUnwatch || prLabel(cmd.PR) != "acme/api#42" {
+		t.Errorf("unwatch = %+v", cmd)
+	}
+
+	if cmd := typeKeys(&m, "a https://github.com/a/b/pull/1 \r"); cmd.Kind != CmdAdd || cmd.URL != "https://github.com/a/b/pull/1" {
+		t.Errorf("add = %+v", cmd)
+	}
+	if cmd := typeKeys(&m, "a\r"); cmd.Kind != CmdNone {
+		t.Errorf("empty add = %+v", cmd)
+	}
+	if cmd := typeKeys(&m, "axyz\x1b"); cmd.Kind != CmdNone || m.mode != modeNormal {
+		t.Errorf("cancelled add = %+v, mode %d", cmd, m.mode)
+	}
+
+	if cmd := typeKeys(&m, "q"); cmd.Kind != CmdQuit {
+		t.Errorf("q = %+v", cmd)
+	}
+	if cmd := typeKeys(&m, "/\x03"); cmd.Kind != CmdQuit {
+		t.Errorf("ctrl+c while filtering = %+v", cmd)
+	}
+}
+
+func TestModelKeepsSelection(t *testing.T) {
+	var m Model
+	prs := testPRs()
+	m.SetPRs(prs)
+	typeKeys(&m, "jj") // acme/cli#9
+
+	// Removing an earlier row keeps the cursor on the same PR.
+	m.SetPRs(append([]config.WatchedPR{}, prs[0], prs[3]))
+	if pr, ok := m.Selected(); !ok || prLabel(pr) != "acme/cli#9" {
+		t.Errorf("selected = %v, %v", prLabel(pr), ok)
+	}
+
+	// Removing the selected row clamps the cursor.
+	m.SetPRs(prs[:1])
+	if pr, ok := m.Selected(); !ok || prLabel(pr) != "octo/web#7" {
+		t.Errorf("selected = %v, %v", prLabel(pr), ok)
+	}
+
+	m.SetPRs(nil)
+	if _, ok := m.Selected(); ok {
+		t.Error("expected no selection for an empty list")
+	}
+	if cmd := typeKeys(&m, "od"); cmd.Kind != CmdNone || m.mode != modeNormal {
+		t.Errorf("commands on empty list = %+v, mode %d", cmd, m.mode)
+	}
+}
+
+func TestDisplayState(t *testing.T) {
+	tests := []struct {
+		pr   config.WatchedPR
+		want string
+	}{
+		{config.WatchedPR{LastKnownState: "SUCCESS"}, "success"},
+		{config.WatchedPR{}, "unknown"},
+		{config.WatchedPR{LastKnownState: "success", PRState: "closed"}, "closed"},
+		{config.WatchedPR{LastKnownState: "failure", PRState: "open"}, "failure"},
+	}
+	for _, tt := range tests {
+		if got := DisplayState(tt.pr); got != tt.want {
+			t.Errorf("DisplayState(%+v) = %q, want %q", tt.pr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devblac/prw/internal/history"
+)
+
+// ANSI escape sequences used by the renderer.
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiClear = "\x1b[H\x1b[2J"
+)
+
+// eventsPaneHeight is the number of recent events shown below the table.
+const eventsPaneHeight = 6
+
+// stateColor returns the ANSI color code for a display state.
+func stateColor(state string) string {
+	switch state {
+	case "success", "approved":
+		return "32"
+	case "failure", "error", "changes_requested":
+		return "31"
+	case "pending":
+		return "33"
+	case "merged":
+		return "35"
+	default:
+		return "90"
+	}
+}
+
+// Render draws the dashboard into a string of width x height cells. Lines are
+// separated by "\r\n" so output stays aligned while the terminal is not in
+// canonical mode. now is used for relative "last checked" times.
+func Render(m *Model, width, height int, color bool, now time.Time) string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 10 {
+		height = 10
+	}
+
+	paint := func(code, s string) string {
+		if !color || code == "" {
+			return s
+		}
+		return "\x1b[" + code + "m" + s + ansiReset
+	}
+
+	var lines []string
+	add := func(s string) { lines = append(lines, s) }
+
+	header := fmt.Sprintf("prw — %d watched", len(m.prs))
+	if len(m.rows) != len(m.prs) {
+		header = fmt.Sprintf("prw — %d of %d watched", len(m.rows), len(m.prs))
+	}
+	header += fmt.Sprintf("  sort: %s", sortNames[m.sort])
+	if m.filter != "" {
+		header += fmt.Sprintf("  filter: %q", m.filter)
+	}
+	if color {
+		header = ansiBold + truncate(header, width) + ansiReset
+	}
+	add(header)
+	add("")
+
+	// Table header, then as many rows as fit above the events pane and footer.
+	tableRows := height - len(lines) - 1 - (eventsPaneHeight + 2) - 3
+	if tableRows < 1 {
+		tableRows = 1
+	}
+	add(truncate(fmt.Sprintf("  %-9s %-30s %-6s %-12s %s", "STATUS", "REPO", "PR", "CHECKED", "TITLE"), width))
+
+	start := 0
+	if m.cursor >= tableRows {
+		start = m.cursor - tableRows + 1
+	}
+	if len(m.rows) == 0 {
+		if len(m.prs) == 0 {
+			add("  No PRs being watched. Press 'a' to add one.")
+		} else {
+			add("  No PRs match the filter.")
+		}
+	}
+	for i := start; i < len(m.rows) && i < start+tableRows; i++ {
+		pr := m.rows[i]
+		state := DisplayState(pr)
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+		}
+		rest := fmt.Sprintf(" %-30s #%-5d %-12s %s",
+			truncate(repoLabel(pr), 30), pr.Number, ago(pr.LastChecked, now), pr.Title)
+		line := marker + paint(stateColor(state), fmt.Sprintf("%-9s", state)) + truncate(rest, width-11)
+		if i == m.cursor && color {
+			line = ansiBold + line + ansiReset
+		}
+		add(line)
+	}
+
+	// Pad the table so the events pane stays in place.
+	for len(lines) < height-(eventsPaneHeight+2)-3 {
+		add("")
+	}
+
+	add(paint("90", strings.Repeat("─", width)))
+	add("Recent events")
+	events := m.events
+	if len(events) > eventsPaneHeight {
+		events = events[len(events)-eventsPaneHeight:]
+	}
+	if len(events) == 0 {
+		add(paint("90", "  none yet"))
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		add(formatEvent(events[i], width, paint))
+	}
+	for len(lines) < height-3 {
+		add("")
+	}
+
+	add(paint("90", strings.Repeat("─", width)))
+	switch m.mode {
+	case modeFilter:
+		add(truncate("Filter: "+m.input+"_", width))
+	case modeAdd:
+		add(truncate("Add PR URL: "+m.input+"_", width))
+	default:
+		add(truncate(m.Status, width))
+	}
+	add(paint("90", truncate(helpText(m.mode), width)))
+
+	return ansiClear + strings.Join(lines, "\r\n")
+}
+
+func formatEvent(r history.Record, width int, paint func(code, s string) string) string {
+	label := fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+	if r.Host != "" {
+		label = r.Host + "/" + label
+	}
+	prev := r.PreviousState
+	if prev == "" {
+		prev = "unknown"
+	}
+	line := fmt.Sprintf("  %s %s %s → %s", r.Timestamp.Local().Format("15:04:05"), label, prev, r.CurrentState)
+	line = truncate(line, width)
+	return strings.Replace(line, r.CurrentState, paint(stateColor(r.CurrentState), r.CurrentState), 1)
+}
+
+func helpText(mode int) string {
+	switch mode {
+	case modeFilter:
+		return "type to filter  enter: keep  esc: clear"
+	case modeAdd:
+		return "enter: watch  esc: cancel"
+	case modeConfirmUnwatch:
+		return "y: unwatch  any other key: cancel"
+	default:
+		return "j/k: move  o: open  d: unwatch  r: refresh  a: add  s: sort  /: filter  q: quit"
+	}
+}
+
+// ago formats the time since t for the CHECKED column.
+func ago(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// truncate shortens s to at most width runes.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	if width == 1 {
+		return string(r[:1])
+	}
+	return string(r[:width-1]) + "…"
+}
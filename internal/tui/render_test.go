@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/history"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var m Model
+	m.SetPRs(testPRs())
+	m.SetEvents([]history.Record{
+		{Owner: "acme", Repo: "api", Number: 42, PreviousState: "pending", CurrentState: "failure", Timestamp: now},
+	})
+	m.Status = "Checked 4 PRs"
+
+	out := Render(&m, 100, 24, false, now)
+	if strings.Contains(out, "\x1b[3") {
+		t.Error("expected no colors when color is disabled")
+	}
+	lines := strings.Split(strings.TrimPrefix(out, ansiClear), "\r\n")
+	if len(lines) != 24 {
+		t.Errorf("rendered %d lines, want 24", len(lines))
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > 100 {
+			t.Errorf("line exceeds width (%d): %q", n, line)
+		}
+	}
+
+	for _, want := range []string{
+		"prw — 4 watched  sort: repo",
+		"> pending   acme/api",
+		"#42",
+		"2h ago",
+		"acme/api#42 pending → failure",
+		"Checked 4 PRs",
+		"q: quit",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderColorsAndPrompts(t *testing.T) {
+	now := time.Now()
+	var m Model
+	m.SetPRs(testPRs())
+
+	out := Render(&m, 80, 24, true, now)
+	for _, want := range []string{"\x1b[31mfailure", "\x1b[32msuccess", "\x1b[33mpending", "\x1b[35mmerged"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("colored output missing %q", want)
+		}
+	}
+
+	typeKeys(&m, "/zzz")
+	out = Render(&m, 80, 24, false, now)
+	for _, want := range []string{"0 of 4 watched", "No PRs match the filter.", "Filter: zzz_"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("filtered output missing %q", want)
+		}
+	}
+
+	var empty Model
+	if out := Render(&empty, 80, 24, false, now); !strings.Contains(out, "Press 'a' to add one") {
+		t.Errorf("empty output missing hint:\n%s", out)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"héllo", 2, "h…"},
+		{"hello", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
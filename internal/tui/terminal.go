@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// EnableCbreak switches the terminal attached to f into cbreak mode so key presses
+// are delivered immediately and not echoed. Signals such as Ctrl+C keep working.
+// The returned function restores the previous mode. On Windows, where stty is not
+// available, the terminal is left alone and keys arrive after Enter.
+func EnableCbreak(f *os.File) (restore func(), err error) {
+	if runtime.GOOS == "windows" {
+		return func() {}, nil
+	}
+
+	saved, err := stty(f, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal mode: %w", err)
+	}
+	if _, err := stty(f, "-icanon", "-echo", "min", "1"); err != nil {
+		return nil, fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+	return func() { stty(f, strings.TrimSpace(saved)) }, nil
+}
+
+// Size returns the width and height of the terminal attached to f, falling back
+// to $COLUMNS/$LINES and then 80x24.
+func Size(f *os.File) (width, height int) {
+	if runtime.GOOS != "windows" {
+		if out, err := stty(f, "size"); err == nil {
+			fields := strings.Fields(out)
+			if len(fields) == 2 {
+				rows, errRows := strconv.Atoi(fields[0])
+				cols, errCols := strconv.Atoi(fields[1])
+				if errRows == nil && errCols == nil && rows > 0 && cols > 0 {
+					return cols, rows
+				}
+			}
+		}
+	}
+
+	width, height = 80, 24
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		width = cols
+	}
+	if rows, err := strconv.Atoi(os.Getenv("LINES")); err == nil && rows > 0 {
+		height = rows
+	}
+	return width, height
+}
+
+// ColorEnabled reports whether ANSI colors should be used, honoring NO_COLOR.
+func ColorEnabled() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
+
+func stty(f *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = f
+	out, err := cmd.Output()
+	return string(out), err
+}
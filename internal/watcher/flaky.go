@@ -0,0 +1,28 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// recordCheckOutcomes adds the check outcomes of a snapshot to the flakiness stats.
+func (w *Watcher) recordCheckOutcomes(pr *config.WatchedPR, snapshot *prSnapshot) {
+	now := time.Now()
+	for _, check := range snapshot.checks {
+		w.checkStats.Record(pr.Host, pr.Owner, pr.Repo, check.Name, snapshot.pr.Head.SHA, check.State, now)
+	}
+}
+
+// likelyFlakyChecks returns the failing checks of a snapshot that are likely
+// flaky: listed in flaky_checks or flipping on enough recent commits.
+func (w *Watcher) likelyFlakyChecks(pr *config.WatchedPR, snapshot *prSnapshot) []string {
+	var flaky []string
+	for _, name := range notify.FailingChecks(snapshot.checks) {
+		if w.config.IsFlakyCheck(name) || w.checkStats.LikelyFlaky(pr.Host, pr.Owner, pr.Repo, name) {
+			flaky = append(flaky, name)
+		}
+	}
+	return flaky
+}
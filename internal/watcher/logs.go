@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// maxExcerptJobs bounds the number of job logs downloaded for one notification.
+const maxExcerptJobs = 3
+
+// JobLogClient is implemented by clients that can download GitHub Actions job
+// logs. Log excerpts are skipped for clients that don't implement it.
+type JobLogClient interface {
+	GetCheckRuns(owner, repo, ref string) (*github.CheckRunList, error)
+	GetJob(owner, repo string, jobID int64) (*github.Job, error)
+	GetJobLogs(owner, repo string, jobID int64) ([]byte, error)
+}
+
+// FailedJob is a failed check run of a commit. Job and Log are only set for
+// GitHub Actions jobs; Err records why they could not be fetched.
+type FailedJob struct {
+	Check github.Check
+	Job   *github.Job
+	Log   []byte
+	Err   error
+}
+
+// FailedJobs returns the failed check runs of a commit, downloading the logs of
+// at most limit GitHub Actions jobs among them (0 means no limit).
+func FailedJobs(client JobLogClient, owner, repo, sha string, limit int) ([]FailedJob, error) {
+	runs, err := client.GetCheckRuns(owner, repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check runs: %w", err)
+	}
+
+	var jobs []FailedJob
+	downloaded := 0
+	for _, run := range runs.CheckRuns {
+		state := run.State()
+		if state != "failure" && state != "error" {
+			continue
+		}
+		url := run.HTMLURL
+		if url == "" {
+			url = run.DetailsURL
+		}
+		failed := FailedJob{Check: github.Check{Name: run.Name, Source: github.CheckSourceCheckRun, State: state, URL: url}}
+
+		if run.IsActionsJob() && (limit == 0 || downloaded < limit) {
+			downloaded++
+			failed.Job, failed.Err = client.GetJob(owner, repo, run.ID)
+			if failed.Err == nil {
+				failed.Log, failed.Err = client.GetJobLogs(owner, repo, run.ID)
+			}
+		}
+		jobs = append(jobs, failed)
+	}
+	return jobs, nil
+}
+
+// LogExcerpts converts the downloaded logs of failed jobs into notification excerpts of n lines.
+func LogExcerpts(jobs []FailedJob, n int) []notify.LogExcerpt {
+	var excerpts []notify.LogExcerpt
+	for _, job := range jobs {
+		if job.Log == nil {
+			continue
+		}
+		excerpt := notify.LogExcerpt{
+			Check: job.Check.Name,
+			URL:   job.Check.URL,
+			Lines: github.LogExcerpt(job.Log, n),
+		}
+		if job.Job != nil {
+			excerpt.Step = job.Job.FailedStep()
+		}
+		excerpts = append(excerpts, excerpt)
+	}
+	return excerpts
+}
+
+// fetchLogExcerpts returns log excerpts for a commit that just started failing,
+// when log excerpts are enabled and the client can download logs. Errors are
+// reported and leave the excerpts out, since they are only an addition to the event.
+func (w *Watcher) fetchLogExcerpts(host, owner, repo, sha string) []notify.LogExcerpt {
+	lines := w.config.LogExcerptLines
+	if lines <= 0 {
+		return nil
+	}
+	client, err := w.clientFor(host)
+	if err != nil {
+		return nil
+	}
+	logClient, ok := client.(JobLogClient)
+	if !ok {
+		return nil
+	}
+
+	jobs, err := FailedJobs(logClient, owner, repo, sha, maxExcerptJobs)
+	if err != nil {
+		w.printf("Warning: failed to fetch CI logs for %s/%s: %v\n", owner, repo, err)
+		return nil
+	}
+	for _, job := range jobs {
+		if job.Err != nil {
+			w.printf("Warning: failed to fetch log of %s: %v\n", job.Check.Name, job.Err)
+		}
+	}
+	return LogExcerpts(jobs, lines)
+}
@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// ciDuration returns how long CI was pending on the head commit before reaching
+// a result, or 0 if it is unknown or CI did not just finish.
+func ciDuration(pr *config.WatchedPR, sha, previousState, currentState string, now time.Time) time.Duration {
+	if previousState != "pending" || currentState == "pending" {
+		return 0
+	}
+	if pr.PendingSince.IsZero() || headChanged(*pr, sha) {
+		return 0
+	}
+	return now.Sub(pr.PendingSince)
+}
+
+// applyPending tracks when CI on the head commit entered pending and notifies
+// once when it stays pending longer than the stuck threshold of the repo.
+func (w *Watcher) applyPending(pr *config.WatchedPR, snapshot *prSnapshot, previousState string, now time.Time) {
+	sha := snapshot.pr.Head.SHA
+	if snapshot.state != "pending" {
+		pr.PendingSince = time.Time{}
+		pr.StuckNotified = false
+		return
+	}
+	if pr.PendingSince.IsZero() || previousState != "pending" || headChanged(*pr, sha) {
+		pr.PendingSince = now
+		pr.StuckNotified = false
+		return
+	}
+
+	threshold := w.config.StuckThresholdFor(pr.Owner, pr.Repo)
+	pending := now.Sub(pr.PendingSince)
+	if threshold <= 0 || pr.StuckNotified || pending < threshold {
+		return
+	}
+	pr.StuckNotified = true
+
+	var checks []github.Check
+	for _, check := range snapshot.checks {
+		if check.State == "pending" {
+			checks = append(checks, check)
+		}
+	}
+	event := &notify.StatusChangeEvent{
+		Type:          notify.EventStuck,
+		Host:          pr.Host,
+		Owner:         pr.Owner,
+		Repo:          pr.Repo,
+		Number:        pr.Number,
+		Title:         pr.Title,
+		Author:        pr.Author,
+		Labels:        pr.Labels,
+		PreviousState: "pending",
+		CurrentState:  "pending",
+		SHA:           sha,
+		Checks:        checks,
+		Duration:      pending,
+		Timestamp:     now,
+	}
+
+	w.emit(event, shouldNotifyStuck(w.config.NotificationFilter))
+}
+
+// shouldNotifyStuck reports whether stuck events pass the notification filter.
+// A wedged CI run needs attention like a failure, so the fail filter lets them through.
+func shouldNotifyStuck(filter string) bool {
+	filter = config.NormalizeNotificationFilter(filter)
+	return filter == config.NotificationFilterChange || filter == config.NotificationFilterFail
+}
@@ -0,0 +1,106 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// CommitComparer is implemented by clients that can compare commits. Push events
+// carry no commit details for clients that don't implement it.
+type CommitComparer interface {
+	CompareCommits(owner, repo, base, head string) (*github.Comparison, error)
+}
+
+// pushInfo describes the commits pushed to a PR since the last check.
+type pushInfo struct {
+	forced  bool
+	commits int
+	authors []string
+
+	// compareFailed is set when the comparison failed, so forced and the
+	// commit details are unknown rather than false and empty
+	compareFailed bool
+}
+
+// headChanged reports whether the head commit of pr changed since the last check.
+func headChanged(pr config.WatchedPR, sha string) bool {
+	return pr.LastKnownSHA != "" && pr.LastKnownSHA != sha
+}
+
+// previousCIState returns the state a fetched CI state is compared with. A new
+// head commit starts out pending, so the state of the old commit is not reported
+// as a transition.
+func previousCIState(pr config.WatchedPR, sha string) string {
+	previous := github.NormalizeState(pr.LastKnownState)
+	if previous != "" && headChanged(pr, sha) {
+		return "pending"
+	}
+	return previous
+}
+
+// fetchPush compares the previous and current head commits of pr. Compare
+// errors are reported and mark the push as not compared.
+func (w *Watcher) fetchPush(pr config.WatchedPR, sha string) *pushInfo {
+	push := &pushInfo{}
+	client, err := w.clientFor(pr.HostName())
+	if err != nil {
+		return push
+	}
+	comparer, ok := client.(CommitComparer)
+	if !ok {
+		return push
+	}
+
+	comparison, err := comparer.CompareCommits(pr.Owner, pr.Repo, pr.LastKnownSHA, sha)
+	if err != nil {
+		w.printf("Warning: failed to compare commits of %s/%s#%d: %v\n", pr.Owner, pr.Repo, pr.Number, err)
+		push.compareFailed = true
+		return push
+	}
+	push.forced = comparison.ForcePushed()
+	push.commits = comparison.AheadBy
+	push.authors = comparison.Authors()
+	return push
+}
+
+// applyPush notifies about new commits on a PR.
+func (w *Watcher) applyPush(pr *config.WatchedPR, snapshot *prSnapshot) {
+	push := snapshot.push
+	if push == nil {
+		return
+	}
+
+	eventType := notify.EventPushed
+	if push.forced {
+		eventType = notify.EventForcePushed
+	}
+	event := &notify.StatusChangeEvent{
+		Type:          eventType,
+		Host:          pr.Host,
+		Owner:         pr.Owner,
+		Repo:          pr.Repo,
+		Number:        pr.Number,
+		Title:         pr.Title,
+		Author:        pr.Author,
+		Labels:        pr.Labels,
+		PreviousState: github.NormalizeState(pr.LastKnownState),
+		CurrentState:  snapshot.state,
+		SHA:           snapshot.pr.Head.SHA,
+		PreviousSHA:   pr.LastKnownSHA,
+		Commits:       push.commits,
+		Authors:       push.authors,
+		CompareFailed: push.compareFailed,
+		Timestamp:     time.Now(),
+	}
+
+	w.emit(event, shouldNotifyPush(w.config.NotificationFilter))
+}
+
+// shouldNotifyPush reports whether push events pass the notification filter.
+// They are neither failures nor successes, so only the change filter lets them through.
+func shouldNotifyPush(filter string) bool {
+	return config.NormalizeNotificationFilter(filter) == config.NotificationFilterChange
+}
@@ -0,0 +1,102 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+// RequestsPerPR is the number of API calls a poll cycle makes for one open PR
+// when nothing is served from the ETag cache.
+const RequestsPerPR = 5
+
+// RateLimitReporter is implemented by clients that track the GitHub API rate limit.
+// Clients that don't implement it are polled at the configured interval.
+type RateLimitReporter interface {
+	RateLimit() github.RateLimit
+}
+
+// SearchRateLimitReporter is implemented by clients that track the GitHub search
+// API budget, which is separate from and much smaller than the core budget.
+type SearchRateLimitReporter interface {
+	SearchRateLimit() github.RateLimit
+}
+
+// rateLimit returns the last known rate limit budget, if the client reports one.
+// Only the github.com budget is tracked; Enterprise instances usually run without
+// a rate limit or with a separate one.
+func (w *Watcher) rateLimit() (github.RateLimit, bool) {
+	w.clientsMu.RLock()
+	client := w.client
+	w.clientsMu.RUnlock()
+
+	reporter, ok := client.(RateLimitReporter)
+	if !ok {
+		return github.RateLimit{}, false
+	}
+	limit := reporter.RateLimit()
+	return limit, limit.Known()
+}
+
+// recordCycleCost remembers how many requests the last cycle consumed so the
+// next interval can be planned from observed usage rather than a worst-case guess.
+func (w *Watcher) recordCycleCost(before github.RateLimit, hadBefore bool) {
+	after, ok := w.rateLimit()
+	if !ok || !hadBefore || !before.Reset.Equal(after.Reset) {
+		return
+	}
+	cost := before.Remaining - after.Remaining
+	if cost < 0 {
+		cost = 0
+	}
+	w.lastCycleCost = cost
+}
+
+// cycleCost estimates the number of requests the next poll cycle will consume.
+func (w *Watcher) cycleCost() int {
+	if w.lastCycleCost > 0 {
+		return w.lastCycleCost
+	}
+	w.mu.Lock()
+	cost := len(w.config.WatchedPRs) * RequestsPerPR
+	w.mu.Unlock()
+	if cost == 0 {
+		cost = 1
+	}
+	return cost
+}
+
+// nextInterval returns how long to wait before the next poll cycle. The base
+// interval is stretched when the remaining budget would run out before the rate
+// limit window resets, and polling pauses until the reset when it is exhausted.
+func (w *Watcher) nextInterval(base time.Duration, now time.Time) time.Duration {
+	limit, ok := w.rateLimit()
+	if !ok {
+		return base
+	}
+
+	untilReset := limit.Reset.Sub(now)
+	if untilReset <= 0 {
+		return base
+	}
+
+	cost := w.cycleCost()
+	if limit.Remaining < cost {
+		w.printf("GitHub API budget nearly exhausted (%s); pausing until reset.\n", limit)
+		return untilReset + time.Second
+	}
+
+	cycles := limit.Remaining / cost
+	if spread := untilReset / time.Duration(cycles); spread > base {
+		w.printf("GitHub API budget is low (%s); slowing poll interval to %s.\n", limit, spread.Round(time.Second))
+		return spread
+	}
+	return base
+}
+
+// reportRateLimit prints the current API budget after a poll cycle.
+func (w *Watcher) reportRateLimit() {
+	if limit, ok := w.rateLimit(); ok {
+		w.printf("GitHub API budget: %s\n", limit)
+	}
+}
@@ -0,0 +1,139 @@
+package watcher
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+)
+
+// rateLimitedClient is a mock client that reports a fixed rate limit budget.
+type rateLimitedClient struct {
+	mockGitHubClient
+	limit github.RateLimit
+}
+
+func (c *rateLimitedClient) RateLimit() github.RateLimit {
+	return c.limit
+}
+
+func TestNextInterval(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	base := 20 * time.Second
+	prs := make([]config.WatchedPR, 8) // 40 requests per cycle
+
+	tests := []struct {
+		name     string
+		limit    github.RateLimit
+		lastCost int
+		expected time.Duration
+	}{
+		{
+			name:     "unknown budget uses base interval",
+			expected: base,
+		},
+		{
+			name:     "plenty of budget",
+			limit:    github.RateLimit{Limit: 5000, Remaining: 4000, Reset: now.Add(30 * time.Minute)},
+			expected: base,
+		},
+		{
+			name:     "low budget stretches interval",
+			limit:    github.RateLimit{Limit: 5000, Remaining: 400, Reset: now.Add(30 * time.Minute)},
+			expected: 3 * time.Minute, // 10 cycles left over 30 minutes
+		},
+		{
+			name:     "observed cost replaces estimate",
+			limit:    github.RateLimit{Limit: 5000, Remaining: 400, Reset: now.Add(30 * time.Minute)},
+			lastCost: 4,
+			expected: base,
+		},
+		{
+			name:     "exhausted budget pauses until reset",
+			limit:    github.RateLimit{Limit: 5000, Remaining: 10, Reset: now.Add(5 * time.Minute)},
+			expected: 5*time.Minute + time.Second,
+		},
+		{
+			name:     "reset in the past uses base interval",
+			limit:    github.RateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(-time.Minute)},
+			expected: base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &rateLimitedClient{limit: tt.limit}
+			w := New(client, &config.Config{WatchedPRs: prs}, &mockNotifier{})
+			w.lastCycleCost = tt.lastCost
+
+			if got := w.nextInterval(base, now); got != tt.expected {
+				t.Errorf("nextInterval() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNextIntervalWithoutReporter(t *testing.T) {
+	w := New(&mockGitHubClient{}, &config.Config{}, &mockNotifier{})
+	if got := w.nextInterval(time.Minute, time.Now()); got != time.Minute {
+		t.Errorf("expected base interval for clients without rate limit reporting, got %v", got)
+	}
+}
+
+func TestRecordCycleCost(t *testing.T) {
+	reset := time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)
+	client := &rateLimitedClient{limit: github.RateLimit{Limit: 5000, Remaining: 4990, Reset: reset}}
+	w := New(client, &config.Config{}, &mockNotifier{})
+
+	w.recordCycleCost(github.RateLimit{Limit: 5000, Remaining: 5000, Reset: reset}, true)
+	if w.lastCycleCost != 10 {
+		t.Errorf("expected cycle cost 10, got %d", w.lastCycleCost)
+	}
+
+	// A new rate limit window makes the comparison meaningless.
+	w.lastCycleCost = 0
+	w.recordCycleCost(github.RateLimit{Limit: 5000, Remaining: 5000, Reset: reset.Add(-time.Hour)}, true)
+	if w.lastCycleCost != 0 {
+		t.Errorf("expected cycle cost to be ignored across windows, got %d", w.lastCycleCost)
+	}
+}
+
+// TestRateLimitConcurrentChanges runs under -race in CI: the loop plans its
+// intervals while the serve and ui handlers add PRs and replace the client.
+func TestRateLimitConcurrentChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	newClient := func() *rateLimitedClient {
+		return &rateLimitedClient{limit: github.RateLimit{Limit: 5000, Remaining: 4000, Reset: time.Now().Add(time.Hour)}}
+	}
+	w := New(newClient(), &config.Config{}, &mockNotifier{})
+	w.SetOutput(io.Discard)
+	w.SetFallbackInterval(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.RunBackground(ctx) }()
+
+	for i := 1; i <= 20; i++ {
+		if _, err := w.AddPR(config.WatchedPR{Owner: "owner", Repo: "repo", Number: i}); err != nil {
+			t.Fatalf("AddPR: %v", err)
+		}
+		w.SetClient(github.DefaultHost, newClient())
+		w.CheckNow()
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package watcher
+
+import (
+	"time"
+
+	"github.com/devblac/prw/internal/config"
+	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/notify"
+)
+
+// Rerunner is implemented by clients that can re-run GitHub Actions jobs.
+// Flaky checks are not re-run automatically for clients that don't implement it.
+type Rerunner interface {
+	RerunJob(owner, repo string, jobID int64) error
+}
+
+// rerunFlakyChecks re-runs the failed checks of a commit that just started failing
+// when every one of them is a GitHub Actions job on the known flaky list with
+// reruns left. It reports whether the failure is handled by reruns, in which case
+// no failure should be notified yet. Checks whose rerun is still starting count
+// as handled without being re-run again.
+func (w *Watcher) rerunFlakyChecks(pr config.WatchedPR, snapshot *prSnapshot) ([]config.AutoRerun, bool) {
+	if len(w.config.FlakyChecks) == 0 {
+		return nil, false
+	}
+	for _, check := range snapshot.checks {
+		failing := check.State == "failure" || check.State == "error"
+		if failing && check.Source != github.CheckSourceCheckRun {
+			return nil, false
+		}
+	}
+
+	client, err := w.clientFor(pr.HostName())
+	if err != nil {
+		return nil, false
+	}
+	rerunner, ok := client.(Rerunner)
+	if !ok {
+		return nil, false
+	}
+
+	// The check runs were already fetched with the commit state this cycle
+	sha := snapshot.pr.Head.SHA
+	limit := w.config.FlakyRerunLimit()
+	var candidates []github.CheckRun
+	awaiting := 0
+	for _, run := range snapshot.runs {
+		state := run.State()
+		if state != "failure" && state != "error" {
+			continue
+		}
+		if pr.WasRerun(run.ID) {
+			awaiting++
+			continue
+		}
+		if !run.IsActionsJob() || !w.config.IsFlakyCheck(run.Name) || pr.RerunCount(run.Name, sha) >= limit {
+			return nil, false
+		}
+		candidates = append(candidates, run)
+	}
+	if len(candidates) == 0 && awaiting == 0 {
+		return nil, false
+	}
+
+	var reruns []config.AutoRerun
+	for _, run := range candidates {
+		if err := rerunner.RerunJob(pr.Owner, pr.Repo, run.ID); err != nil {
+			w.printf("Warning: failed to re-run %s on %s/%s#%d: %v\n", run.Name, pr.Owner, pr.Repo, pr.Number, err)
+			return reruns, false
+		}
+		reruns = append(reruns, config.AutoRerun{Check: run.Name, SHA: sha, JobID: run.ID, At: time.Now()})
+	}
+	return reruns, true
+}
+
+// applyReruns stores the automatic reruns of a snapshot and records them in the history.
+func (w *Watcher) applyReruns(pr *config.WatchedPR, snapshot *prSnapshot) {
+	if len(snapshot.reruns) == 0 {
+		return
+	}
+
+	var checks []github.Check
+	for _, rerun := range snapshot.reruns {
+		pr.Reruns = append(pr.Reruns, rerun)
+		attempt := pr.RerunCount(rerun.Check, rerun.SHA)
+		w.printf("Re-running flaky check %s on %s/%s#%d (attempt %d/%d).\n",
+			rerun.Check, pr.Owner, pr.Repo, pr.Number, attempt, w.config.FlakyRerunLimit())
+		checks = append(checks, github.Check{Name: rerun.Check, Source: github.CheckSourceCheckRun, State: "failure"})
+	}
+
+	w.emit(&notify.StatusChangeEvent{
+		Type:          notify.EventAutoRerun,
+		Host:          pr.Host,
+		Owner:         pr.Owner,
+		Repo:          pr.Repo,
+		Number:        pr.Number,
+		Title:         pr.Title,
+		PreviousState: "failure",
+		CurrentState:  "pending",
+		SHA:           snapshot.pr.Head.SHA,
+		Checks:        checks,
+		Timestamp:     time.Now(),
+	}, false)
+}
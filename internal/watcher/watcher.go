@@ -2,11 +2,17 @@ package watcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/devblac/prw/internal/config"
 	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
 	"github.com/devblac/prw/internal/notify"
 )
 
@@ -14,61 +20,453 @@ import (
 type GitHubClient interface {
 	GetPullRequest(owner, repo string, number int) (*github.PullRequest, error)
 	GetCombinedStatus(owner, repo, ref string) (*github.CombinedStatus, error)
+	GetCheckSuites(owner, repo, ref string) (*github.CheckSuiteList, error)
+	GetCheckRuns(owner, repo, ref string) (*github.CheckRunList, error)
+	GetReviews(owner, repo string, number int) ([]github.Review, error)
+	SearchPullRequests(query string) ([]github.PullRequestRef, error)
 }
 
 // ConfigStore defines the interface for config persistence.
 type ConfigStore interface {
-	UpdatePR(owner, repo string, number int, sha, state string)
+	UpdatePR(host, owner, repo string, number int, sha, state string)
 	Save() error
 }
 
 // Watcher polls GitHub PRs and triggers notifications on status changes.
 type Watcher struct {
-	client   GitHubClient
+	client   GitHubClient // github.com
 	config   *config.Config
 	notifier notify.Notifier
+
+	// clients holds the clients for GitHub Enterprise Server hosts.
+	clients map[string]GitHubClient
+
+	// history, when set, receives every detected event and optionally every poll result.
+	history     *history.Store
+	recordPolls bool
+
+	// checkStats, when set, keeps the per-commit check outcomes flakiness
+	// detection works from.
+	checkStats *history.CheckStatsStore
+
+	// mu guards config while a cycle applies results, so the watch list can be
+	// read and changed from other goroutines through the exported methods.
+	mu sync.Mutex
+	// clientsMu guards client and clients.
+	clientsMu sync.RWMutex
+
+	trigger chan struct{}
+	onCycle func()
+
+	// refresh signals that pending holds PRs to check outside the poll schedule,
+	// e.g. after a webhook delivery.
+	refresh   chan struct{}
+	pendingMu sync.Mutex
+	pending   []config.WatchedPR
+
+	// fallback, when enabled, replaces the configured poll interval because
+	// webhooks deliver changes; 0 disables polling altogether.
+	fallback        time.Duration
+	fallbackEnabled bool
+
+	// out receives progress and warning messages; nil means stdout.
+	out io.Writer
+
+	// lastCycleCost is the number of API requests consumed by the previous poll cycle.
+	lastCycleCost int
+
+	// loopDone is closed when the running loop stops, and nil outside the loop,
+	// e.g. in once mode. rechecks counts the mergeability rechecks scheduled
+	// per PR. Both are guarded by mu.
+	loopDone <-chan struct{}
+	rechecks map[string]int
+
+	// nextSearch holds when each subscription's search is due next, keyed by
+	// Subscription.Key; missing keys are due right away. Guarded by mu.
+	nextSearch map[string]time.Time
+
+	// outbox holds the events detected while results are applied under mu.
+	// They are recorded and notified once mu is released, so slow notifiers
+	// don't block the watch list. Guarded by mu.
+	outbox []queuedEvent
+
+	// saveMu orders config writes made outside mu: saveGen numbers the copies
+	// taken under mu, and savedGen is the newest one written, guarded by saveMu.
+	saveMu   sync.Mutex
+	saveGen  uint64
+	savedGen uint64
+
+	// maxConcurrency, when positive, overrides the configured max_concurrency
+	// for this run without saving it.
+	maxConcurrency int
 }
 
-// New creates a new Watcher.
+// New creates a new Watcher. client is used for PRs on github.com; use SetClient
+// to add clients for GitHub Enterprise Server hosts.
 func New(client GitHubClient, cfg *config.Config, notifier notify.Notifier) *Watcher {
 	return &Watcher{
 		client:   client,
 		config:   cfg,
 		notifier: notifier,
+		trigger:  make(chan struct{}, 1),
+		refresh:  make(chan struct{}, 1),
 	}
 }
 
-// Run starts the watcher loop and runs until context is cancelled.
-func (w *Watcher) Run(ctx context.Context) error {
-	interval := time.Duration(w.config.PollIntervalSeconds) * time.Second
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+// SetFallbackInterval switches the loop to webhook mode: changes arrive through
+// Refresh and all PRs are only reconciled by polling every interval. An interval
+// of 0 disables polling.
+func (w *Watcher) SetFallbackInterval(interval time.Duration) {
+	w.fallback = interval
+	w.fallbackEnabled = true
+}
+
+// SetConcurrency overrides the configured maximum number of PRs checked in
+// parallel for this watcher only; the config keeps its own value. A value of 0
+// restores the configured one.
+func (w *Watcher) SetConcurrency(n int) {
+	w.maxConcurrency = n
+}
+
+// SetClient sets the client used for PRs on host.
+func (w *Watcher) SetClient(host string, client GitHubClient) {
+	w.clientsMu.Lock()
+	defer w.clientsMu.Unlock()
 
-	fmt.Printf("Starting watcher with %d second poll interval...\n", w.config.PollIntervalSeconds)
-	if len(w.config.WatchedPRs) == 0 {
-		fmt.Println("No PRs being watched. Add some with 'prw watch <PR_URL>'.")
+	host = github.NormalizeHost(host)
+	if host == github.DefaultHost {
+		w.client = client
+		return
+	}
+	if w.clients == nil {
+		w.clients = make(map[string]GitHubClient)
+	}
+	w.clients[host] = client
+}
+
+// SetHistory records detected events, including those suppressed by the
+// notification filter, in store. With recordPolls every check result is kept too.
+func (w *Watcher) SetHistory(store *history.Store, recordPolls bool) {
+	w.history = store
+	w.recordPolls = recordPolls
+}
+
+// SetCheckStats keeps the outcomes of checks in stats, which is saved after
+// every cycle that changed it.
+func (w *Watcher) SetCheckStats(stats *history.CheckStatsStore) {
+	w.checkStats = stats
+}
+
+// HasClient reports whether a client is set for PRs on host.
+func (w *Watcher) HasClient(host string) bool {
+	_, err := w.clientFor(host)
+	return err == nil
+}
+
+// History returns the store events are recorded in, or nil.
+func (w *Watcher) History() *history.Store {
+	return w.history
+}
+
+// clientFor returns the client for PRs on host.
+func (w *Watcher) clientFor(host string) (GitHubClient, error) {
+	w.clientsMu.RLock()
+	defer w.clientsMu.RUnlock()
+
+	host = github.NormalizeHost(host)
+	client := w.client
+	if host != github.DefaultHost {
+		client = w.clients[host]
+	}
+	if client == nil {
+		return nil, fmt.Errorf("no GitHub client configured for %s", host)
+	}
+	return client, nil
+}
+
+// Run starts the watcher loop and runs until context is cancelled. Like
+// RunBackground and RunOnce, it flushes the notifier when it returns.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer w.flush()
+	w.printStart()
+	if !w.watching() {
+		w.printf("No PRs being watched. Add some with 'prw watch <PR_URL>' or 'prw subscribe'.\n")
 		return nil
 	}
+	return w.loop(ctx)
+}
+
+// watching reports whether any PR or subscription is watched.
+func (w *Watcher) watching() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.config.WatchedPRs) > 0 || len(w.config.Subscriptions) > 0
+}
+
+// RunBackground is like Run but keeps polling while nothing is watched, for
+// long-running modes where PRs are added through AddPR.
+func (w *Watcher) RunBackground(ctx context.Context) error {
+	defer w.flush()
+	w.printStart()
+	return w.loop(ctx)
+}
+
+func (w *Watcher) printStart() {
+	switch {
+	case !w.fallbackEnabled:
+		w.printf("Starting watcher with %d second poll interval...\n", w.config.PollIntervalSeconds)
+	case w.fallback > 0:
+		w.printf("Starting watcher in webhook mode, reconciling every %s...\n", w.fallback)
+	default:
+		w.printf("Starting watcher in webhook mode without polling...\n")
+	}
+}
+
+func (w *Watcher) loop(ctx context.Context) error {
+	w.mu.Lock()
+	w.loopDone = ctx.Done()
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.loopDone = nil
+		w.mu.Unlock()
+	}()
+
+	interval := time.Duration(w.config.PollIntervalSeconds) * time.Second
+	polling := true
+	if w.fallbackEnabled {
+		interval, polling = w.fallback, w.fallback > 0
+	}
 
 	// Check immediately on startup
 	w.checkAllPRs()
 
+	timer := time.NewTimer(w.nextInterval(interval, time.Now()))
+	defer timer.Stop()
+	if !polling {
+		timer.Stop()
+	}
+	reset := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if polling {
+			timer.Reset(w.nextInterval(interval, time.Now()))
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Println("\nWatcher stopped.")
+			w.printf("\nWatcher stopped.\n")
 			return ctx.Err()
-		case <-ticker.C:
+		case <-timer.C:
+			w.checkAllPRs()
+			timer.Reset(w.nextInterval(interval, time.Now()))
+		case <-w.trigger:
 			w.checkAllPRs()
+			reset()
+		case <-w.refresh:
+			if prs := w.takePending(); len(prs) > 0 {
+				w.checkPRs(prs, nil)
+			}
+		}
+	}
+}
+
+// Refresh asks a running loop to check the given PRs as soon as possible,
+// outside the poll schedule. Requests for the same PR made before the loop gets
+// to them are merged.
+func (w *Watcher) Refresh(prs ...config.WatchedPR) {
+	if len(prs) == 0 {
+		return
+	}
+	w.pendingMu.Lock()
+	for _, pr := range prs {
+		queued := false
+		for _, p := range w.pending {
+			if p.HostName() == pr.HostName() && p.Owner == pr.Owner && p.Repo == pr.Repo && p.Number == pr.Number {
+				queued = true
+				break
+			}
+		}
+		if !queued {
+			w.pending = append(w.pending, pr)
+		}
+	}
+	w.pendingMu.Unlock()
+
+	select {
+	case w.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) takePending() []config.WatchedPR {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	prs := w.pending
+	w.pending = nil
+	return prs
+}
+
+// MatchPRs returns the watched PRs in owner/repo on host that have one of the
+// given numbers or whose last known head commit is sha. It is used to map
+// webhook deliveries, which identify commits and PRs in different ways, to the
+// watch list.
+func (w *Watcher) MatchPRs(host, owner, repo, sha string, numbers []int) []config.WatchedPR {
+	host = github.NormalizeHost(host)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var matched []config.WatchedPR
+	for _, pr := range w.config.WatchedPRs {
+		if pr.HostName() != host || !strings.EqualFold(pr.Owner, owner) || !strings.EqualFold(pr.Repo, repo) {
+			continue
+		}
+		match := sha != "" && pr.LastKnownSHA == sha
+		for _, n := range numbers {
+			match = match || n == pr.Number
+		}
+		if match {
+			matched = append(matched, pr)
 		}
 	}
+	return matched
+}
+
+// CheckNow asks a running loop to check all PRs immediately instead of waiting
+// for the next poll. Requests made while a check is pending are merged.
+func (w *Watcher) CheckNow() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// SetOutput redirects the watcher's progress and warning messages, which go to
+// stdout by default.
+func (w *Watcher) SetOutput(out io.Writer) {
+	w.out = out
+}
+
+func (w *Watcher) printf(format string, a ...interface{}) {
+	out := w.out
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintf(out, format, a...)
+}
+
+// OnCycle registers fn to be called after every poll cycle.
+func (w *Watcher) OnCycle(fn func()) {
+	w.onCycle = fn
+}
+
+// WatchedPRs returns a copy of the watch list.
+func (w *Watcher) WatchedPRs() []config.WatchedPR {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]config.WatchedPR(nil), w.config.WatchedPRs...)
+}
+
+// AddPR adds a PR to the watch list and saves the config. It reports false if
+// the PR was already watched.
+func (w *Watcher) AddPR(pr config.WatchedPR) (bool, error) {
+	w.mu.Lock()
+	if !w.config.AddPR(pr) {
+		w.mu.Unlock()
+		return false, nil
+	}
+	save := w.prepareSave()
+	w.mu.Unlock()
+	return true, save()
+}
+
+// RemovePR removes a PR from the watch list and saves the config. It reports
+// false if the PR was not watched.
+func (w *Watcher) RemovePR(host, owner, repo string, number int) (bool, error) {
+	w.mu.Lock()
+	if !w.config.RemovePR(host, owner, repo, number) {
+		w.mu.Unlock()
+		return false, nil
+	}
+	save := w.prepareSave()
+	w.mu.Unlock()
+	return true, save()
+}
+
+// prepareSave copies the config for saving and returns the function that
+// writes the copy. It must be called with mu held and the returned function
+// after mu is released. A copy older than one already written is skipped.
+func (w *Watcher) prepareSave() func() error {
+	cfg := *w.config
+	cfg.WatchedPRs = append([]config.WatchedPR(nil), w.config.WatchedPRs...)
+	cfg.Subscriptions = append([]config.Subscription(nil), w.config.Subscriptions...)
+	w.saveGen++
+	gen := w.saveGen
+
+	return func() error {
+		w.saveMu.Lock()
+		defer w.saveMu.Unlock()
+		if gen < w.savedGen {
+			return nil
+		}
+		w.savedGen = gen
+		return cfg.Save()
+	}
+}
+
+// queuedEvent is an event waiting in the outbox.
+type queuedEvent struct {
+	event *notify.StatusChangeEvent
+	send  bool // false records the event in the history only
+}
+
+// emit queues an event detected while applying results. It must be called
+// with mu held; dispatch delivers the queued events.
+func (w *Watcher) emit(event *notify.StatusChangeEvent, send bool) {
+	w.outbox = append(w.outbox, queuedEvent{event: event, send: send})
+}
+
+// takeEvents empties the outbox. It must be called with mu held.
+func (w *Watcher) takeEvents() []queuedEvent {
+	events := w.outbox
+	w.outbox = nil
+	return events
+}
+
+// dispatch records events in the history and notifies them. It must be called
+// without mu held.
+func (w *Watcher) dispatch(events []queuedEvent) {
+	for _, queued := range events {
+		w.recordEvent(queued.event)
+		if !queued.send {
+			continue
+		}
+		if err := w.notifier.Notify(queued.event); err != nil {
+			w.printf("Warning: notification failed: %v\n", err)
+		}
+	}
+}
+
+// flush delivers the notifications the notifier holds back, such as email
+// digests, so they are not lost when the watcher returns.
+func (w *Watcher) flush() {
+	if err := notify.Flush(w.notifier); err != nil {
+		w.printf("Warning: notification failed: %v\n", err)
+	}
 }
 
 // RunOnce checks all watched PRs a single time and returns.
 func (w *Watcher) RunOnce(ctx context.Context) error {
-	fmt.Printf("Running one-time check with %d second poll interval...\n", w.config.PollIntervalSeconds)
-	if len(w.config.WatchedPRs) == 0 {
-		fmt.Println("No PRs being watched. Add some with 'prw watch <PR_URL>'.")
+	defer w.flush()
+	w.printf("Running one-time check with %d second poll interval...\n", w.config.PollIntervalSeconds)
+	if !w.watching() {
+		w.printf("No PRs being watched. Add some with 'prw watch <PR_URL>' or 'prw subscribe'.\n")
 		return nil
 	}
 	w.checkAllPRs()
@@ -76,66 +474,671 @@ func (w *Watcher) RunOnce(ctx context.Context) error {
 }
 
 func (w *Watcher) checkAllPRs() {
-	for i := range w.config.WatchedPRs {
-		pr := &w.config.WatchedPRs[i]
-		if err := w.checkPR(pr); err != nil {
-			fmt.Printf("Error checking PR %s/%s#%d: %v\n", pr.Owner, pr.Repo, pr.Number, err)
+	w.mu.Lock()
+	subs := append([]config.Subscription(nil), w.config.Subscriptions...)
+	w.mu.Unlock()
+
+	// Search before fetching the PRs, so a PR that leaves a subscription because
+	// it was merged is seen as merged before it is dropped
+	found := w.searchSubscriptions(subs, time.Now())
+	w.checkPRs(w.WatchedPRs(), found)
+}
+
+// searchSubscriptions runs the search of every subscription that is due and
+// returns the results keyed by Subscription.Key. Each search runs at most once
+// per subscription interval, and waits for the reset while the search budget of
+// its host is exhausted. Skipped and failed searches are left out; failed ones
+// are reported and retried next cycle.
+func (w *Watcher) searchSubscriptions(subs []config.Subscription, now time.Time) map[string][]github.PullRequestRef {
+	if len(subs) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	interval := w.config.SubscriptionInterval()
+	w.mu.Unlock()
+
+	found := make(map[string][]github.PullRequestRef, len(subs))
+	for _, sub := range subs {
+		key := sub.Key()
+		if !w.searchDue(key, now) {
+			continue
+		}
+		client, err := w.clientFor(sub.HostName())
+		if err == nil {
+			if reporter, ok := client.(SearchRateLimitReporter); ok {
+				if limit := reporter.SearchRateLimit(); limit.Known() && limit.Remaining == 0 && limit.Reset.After(now) {
+					w.printf("GitHub search budget exhausted (%s); searching %s after the reset.\n", limit, key)
+					w.setNextSearch(key, limit.Reset)
+					continue
+				}
+			}
+			var refs []github.PullRequestRef
+			refs, err = client.SearchPullRequests(sub.Query())
+			if err == nil {
+				found[key] = refs
+				w.setNextSearch(key, now.Add(interval))
+				continue
+			}
+			var rateLimitErr *github.RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				w.setNextSearch(key, rateLimitErr.Reset)
+			}
 		}
+		w.printf("Error searching subscription %s: %v\n", key, err)
 	}
+	return found
+}
+
+// searchDue reports whether the search of the subscription with key is due.
+func (w *Watcher) searchDue(key string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !now.Before(w.nextSearch[key])
+}
+
+// setNextSearch schedules the next search of the subscription with key.
+func (w *Watcher) setNextSearch(key string, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.nextSearch == nil {
+		w.nextSearch = make(map[string]time.Time)
+	}
+	w.nextSearch[key] = at
+}
+
+// checkPRs fetches and applies the state of prs, reconciles subscriptions with
+// the search results in found, if any, then sends the resulting notifications
+// and saves the config. Only applying the results holds mu.
+func (w *Watcher) checkPRs(prs []config.WatchedPR, found map[string][]github.PullRequestRef) {
+	before, hadBefore := w.rateLimit()
+	results := w.fetchAll(prs)
+	w.recordCycleCost(before, hadBefore)
+
+	w.mu.Lock()
+
+	// Apply results in watch-list order so notifications stay deterministic
+	for i, res := range results {
+		pr := w.config.FindPR(prs[i].HostName(), prs[i].Owner, prs[i].Repo, prs[i].Number)
+		if pr == nil {
+			// Unwatched while the cycle was running
+			continue
+		}
+		if res.err != nil {
+			w.printf("Error checking PR %s/%s#%d: %v\n", pr.Owner, pr.Repo, pr.Number, res.err)
+			continue
+		}
+		w.applySnapshot(pr, res.snapshot)
+	}
+
+	// Drop merged/closed PRs according to the configured policy
+	for _, pr := range w.config.PruneClosedPRs(time.Now()) {
+		w.printf("Stopped watching %s/%s#%d (%s).\n", pr.Owner, pr.Repo, pr.Number, pr.PRState)
+	}
+
+	var added []config.WatchedPR
+	if found != nil {
+		var removed []config.WatchedPR
+		added, removed = w.config.ReconcileSubscriptions(found)
+		for _, pr := range removed {
+			w.printf("Stopped watching %s/%s#%d (no longer matches %s).\n", pr.Owner, pr.Repo, pr.Number, pr.Subscription)
+		}
+		for _, pr := range added {
+			w.printf("Now watching %s/%s#%d (%s).\n", pr.Owner, pr.Repo, pr.Number, pr.Subscription)
+		}
+	}
+
+	events := w.takeEvents()
+	save := w.prepareSave()
+	w.mu.Unlock()
+
+	w.dispatch(events)
 
 	// Save config after checking all PRs
-	if err := w.config.Save(); err != nil {
-		fmt.Printf("Warning: failed to save config: %v\n", err)
+	if err := save(); err != nil {
+		w.printf("Warning: failed to save config: %v\n", err)
+	}
+	if err := w.checkStats.Save(); err != nil {
+		w.printf("Warning: failed to save check stats: %v\n", err)
+	}
+
+	// Record the initial state of newly subscribed PRs right away
+	if len(added) > 0 {
+		w.checkPRs(added, nil)
+		return
+	}
+
+	w.reportRateLimit()
+	if w.onCycle != nil {
+		w.onCycle()
 	}
 }
 
-func (w *Watcher) checkPR(pr *config.WatchedPR) error {
+// prSnapshot is the GitHub state of a watched PR fetched during one poll cycle.
+type prSnapshot struct {
+	pr     *github.PullRequest
+	state  string
+	checks []github.Check
+	runs   []github.CheckRun // raw check runs of the head commit
+
+	reviewDecision string
+	reviewers      []string // requested reviewers
+
+	// logs holds excerpts of failed job logs when the PR just started failing
+	logs []notify.LogExcerpt
+
+	// reruns holds the flaky checks re-run automatically during this cycle
+	reruns []config.AutoRerun
+
+	// push describes the new commits when the head commit changed
+	push *pushInfo
+}
+
+type fetchResult struct {
+	snapshot *prSnapshot
+	err      error
+}
+
+// fetchAll fetches the GitHub state of every PR using a bounded pool of workers.
+// Results are returned in the same order as prs.
+func (w *Watcher) fetchAll(prs []config.WatchedPR) []fetchResult {
+	results := make([]fetchResult, len(prs))
+	jobs := make(chan int)
+
+	workers := w.concurrency()
+	if workers > len(prs) {
+		workers = len(prs)
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				snapshot, err := w.fetchWatchedPR(prs[i])
+				results[i] = fetchResult{snapshot: snapshot, err: err}
+			}
+		}()
+	}
+
+	for i := range prs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// concurrency returns the maximum number of PRs fetched at the same time.
+func (w *Watcher) concurrency() int {
+	if w.maxConcurrency > 0 {
+		return w.maxConcurrency
+	}
+	if w.config.MaxConcurrency > 0 {
+		return w.config.MaxConcurrency
+	}
+	return config.DefaultMaxConcurrency
+}
+
+// fetchPR reads the current state of a PR from GitHub. It does not touch the
+// config, so it is safe to call from multiple goroutines.
+func (w *Watcher) fetchPR(host, owner, repo string, number int) (*prSnapshot, error) {
+	client, err := w.clientFor(host)
+	if err != nil {
+		return nil, err
+	}
+
 	// Fetch the PR to get current head SHA
-	ghPR, err := w.client.GetPullRequest(pr.Owner, pr.Repo, pr.Number)
+	ghPR, err := client.GetPullRequest(owner, repo, number)
 	if err != nil {
-		return fmt.Errorf("failed to fetch PR: %w", err)
+		return nil, fmt.Errorf("failed to fetch PR: %w", err)
 	}
 
-	currentSHA := ghPR.Head.SHA
+	snapshot := &prSnapshot{pr: ghPR}
+	if ghPR.Lifecycle() != github.PRStateOpen {
+		return snapshot, nil
+	}
+
+	// Fetch statuses and check runs for the head commit
+	snapshot.state, snapshot.checks, snapshot.runs, err = commitState(client, owner, repo, ghPR.Head.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := client.GetReviews(owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	snapshot.reviewers = ghPR.RequestedReviewerNames()
+	snapshot.reviewDecision = github.ReviewDecision(reviews, snapshot.reviewers)
+
+	return snapshot, nil
+}
+
+// fetchWatchedPR fetches the state of pr, compares its head commits after a push,
+// and, when its CI just started failing, re-runs known flaky checks or fetches
+// excerpts of the failed job logs for the notification. A failure handled by
+// reruns is reported as pending.
+func (w *Watcher) fetchWatchedPR(pr config.WatchedPR) (*prSnapshot, error) {
+	snapshot, err := w.fetchPR(pr.HostName(), pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.pr.Lifecycle() != github.PRStateOpen {
+		return snapshot, nil
+	}
+
+	if headChanged(pr, snapshot.pr.Head.SHA) {
+		snapshot.push = w.fetchPush(pr, snapshot.pr.Head.SHA)
+	}
+
+	previous := previousCIState(pr, snapshot.pr.Head.SHA)
+	failing := snapshot.state == "failure" || snapshot.state == "error"
+	if failing && previous != "" && previous != snapshot.state {
+		var handled bool
+		snapshot.reruns, handled = w.rerunFlakyChecks(pr, snapshot)
+		if handled {
+			snapshot.state = "pending"
+			return snapshot, nil
+		}
+		snapshot.logs = w.fetchLogExcerpts(pr.HostName(), pr.Owner, pr.Repo, snapshot.pr.Head.SHA)
+	}
+	return snapshot, nil
+}
 
-	// Fetch combined status for the head commit
-	status, err := w.client.GetCombinedStatus(pr.Owner, pr.Repo, currentSHA)
+func (w *Watcher) checkPR(pr *config.WatchedPR) error {
+	snapshot, err := w.fetchWatchedPR(*pr)
 	if err != nil {
-		return fmt.Errorf("failed to fetch status: %w", err)
+		return err
+	}
+	w.mu.Lock()
+	w.applySnapshot(pr, snapshot)
+	events := w.takeEvents()
+	w.mu.Unlock()
+	w.dispatch(events)
+	return nil
+}
+
+// applySnapshot compares a fetched snapshot with the stored state, queues the
+// resulting events, and updates the watched PR.
+func (w *Watcher) applySnapshot(pr *config.WatchedPR, snapshot *prSnapshot) {
+	defer w.recordPoll(pr)
+
+	ghPR := snapshot.pr
+	currentSHA := ghPR.Head.SHA
+	pr.Draft = ghPR.Draft
+
+	if lifecycle := ghPR.Lifecycle(); lifecycle != github.PRStateOpen {
+		w.recordClosed(pr, ghPR, lifecycle)
+		return
 	}
+	pr.PRState = github.PRStateOpen
+	pr.ClosedAt = time.Time{}
+
+	currentState := snapshot.state
+	previousState := previousCIState(*pr, currentSHA)
+	now := time.Now()
 
-	currentState := github.NormalizeState(status.State)
-	previousState := github.NormalizeState(pr.LastKnownState)
+	// Reruns only count against the limit of the commit they were made on
+	pr.Reruns = pr.RerunsFor(currentSHA)
+	w.applyReruns(pr, snapshot)
+	w.recordCheckOutcomes(pr, snapshot)
 
 	// Refresh title when available
 	if ghPR.Title != "" && ghPR.Title != pr.Title {
 		pr.Title = ghPR.Title
 	}
+	refreshAuthorAndLabels(pr, ghPR)
+
+	w.applyPush(pr, snapshot)
 
 	// Check if status changed
-	if previousState != "" && previousState != currentState && shouldNotify(w.config.NotificationFilter, currentState) {
+	if previousState != "" && previousState != currentState {
 		event := &notify.StatusChangeEvent{
+			Host:          pr.Host,
 			Owner:         pr.Owner,
 			Repo:          pr.Repo,
 			Number:        pr.Number,
 			Title:         pr.Title,
+			Author:        pr.Author,
+			Labels:        pr.Labels,
 			PreviousState: previousState,
 			CurrentState:  currentState,
 			SHA:           currentSHA,
-			Timestamp:     time.Now(),
+			Checks:        snapshot.checks,
+			Logs:          snapshot.logs,
+			FlakyChecks:   w.likelyFlakyChecks(pr, snapshot),
+			Duration:      ciDuration(pr, currentSHA, previousState, currentState, now),
+			Timestamp:     now,
 		}
 
-		if err := w.notifier.Notify(event); err != nil {
-			fmt.Printf("Warning: notification failed: %v\n", err)
-		}
+		w.emit(event, shouldNotify(w.config.NotificationFilter, currentState))
 	}
 
+	w.applyPending(pr, snapshot, previousState, now)
+	w.applyReviews(pr, snapshot)
+	w.applyMergeability(pr, snapshot)
+
 	// Update stored state
 	pr.LastKnownSHA = currentSHA
 	pr.LastKnownState = currentState
+	pr.LastChecked = now
+}
+
+// applyReviews notifies when a PR gets approved, gets changes requested, or has
+// new reviewers requested, and stores the review state. Nothing is reported the
+// first time reviews are seen.
+func (w *Watcher) applyReviews(pr *config.WatchedPR, snapshot *prSnapshot) {
+	previous := pr.ReviewDecision
+	current := snapshot.reviewDecision
+	known := previous != ""
+
+	newEvent := func(eventType string) *notify.StatusChangeEvent {
+		return &notify.StatusChangeEvent{
+			Type:          eventType,
+			Host:          pr.Host,
+			Owner:         pr.Owner,
+			Repo:          pr.Repo,
+			Number:        pr.Number,
+			Title:         pr.Title,
+			Author:        pr.Author,
+			Labels:        pr.Labels,
+			PreviousState: previous,
+			CurrentState:  current,
+			SHA:           snapshot.pr.Head.SHA,
+			Timestamp:     time.Now(),
+		}
+	}
+
+	var events []*notify.StatusChangeEvent
+	if known {
+		if added := newReviewers(pr.RequestedReviewers, snapshot.reviewers); len(added) > 0 {
+			event := newEvent(notify.EventReviewRequested)
+			event.Reviewers = added
+			events = append(events, event)
+		}
+		if current != previous {
+			switch current {
+			case github.ReviewApproved:
+				events = append(events, newEvent(notify.EventApproved))
+			case github.ReviewChangesRequested:
+				events = append(events, newEvent(notify.EventChangesRequested))
+			}
+		}
+	}
+
+	for _, event := range events {
+		w.emit(event, shouldNotifyReview(w.config.NotificationFilter))
+	}
+
+	pr.ReviewDecision = current
+	pr.RequestedReviewers = snapshot.reviewers
+}
+
+// mergeabilityRecheckDelay is how long to wait before checking a PR again when
+// GitHub is still computing its mergeability; it doubles with every further
+// recheck, up to maxMergeabilityRechecks in a row. After that the PR waits for
+// the next regular check.
+var mergeabilityRecheckDelay = 5 * time.Second
+
+const maxMergeabilityRechecks = 4
+
+// applyMergeability evaluates the PR against its merge rule and notifies once
+// when it becomes ready to merge. While GitHub has not computed mergeability yet
+// the previous result is kept and the PR is checked again shortly. Nothing is
+// reported the first time readiness is evaluated.
+func (w *Watcher) applyMergeability(pr *config.WatchedPR, snapshot *prSnapshot) {
+	ghPR := snapshot.pr
+	rule := w.config.MergeRuleFor(pr.Owner, pr.Repo)
+
+	known := ghPR.MergeabilityKnown()
+	if !known && needsMergeability(rule) {
+		// Readiness can still be lost on the requirements that do not
+		// depend on mergeability, e.g. after a push restarts CI
+		if !meetsMergeRule(withoutMergeability(rule), snapshot) {
+			pr.ReadyToMerge = false
+		}
+		w.scheduleRecheck(*pr)
+		return
+	}
+	delete(w.rechecks, recheckKey(*pr))
+
+	evaluated := pr.MergeableState != ""
+	if known {
+		pr.MergeableState = github.NormalizeState(ghPR.MergeableState)
+	} else if !evaluated {
+		pr.MergeableState = github.MergeableStateUnknown
+	}
+
+	ready := meetsMergeRule(rule, snapshot)
+	if ready && !pr.ReadyToMerge && evaluated {
+		event := &notify.StatusChangeEvent{
+			Type:          notify.EventReadyToMerge,
+			Host:          pr.Host,
+			Owner:         pr.Owner,
+			Repo:          pr.Repo,
+			Number:        pr.Number,
+			Title:         pr.Title,
+			Author:        pr.Author,
+			Labels:        pr.Labels,
+			PreviousState: notify.ReadyStateNotReady,
+			CurrentState:  notify.ReadyStateReady,
+			SHA:           ghPR.Head.SHA,
+			Checks:        snapshot.checks,
+			Timestamp:     time.Now(),
+		}
+
+		w.emit(event, shouldNotifyReady(w.config.NotificationFilter))
+	}
+	pr.ReadyToMerge = ready
+}
+
+// scheduleRecheck asks the running loop to check pr again after a backoff
+// delay. Nothing is scheduled outside the loop, or once the PR was rechecked
+// maxMergeabilityRechecks times in a row, and a pending recheck is dropped
+// when the loop stops.
+func (w *Watcher) scheduleRecheck(pr config.WatchedPR) {
+	done := w.loopDone
+	if done == nil {
+		return
+	}
+	key := recheckKey(pr)
+	attempt := w.rechecks[key]
+	if attempt >= maxMergeabilityRechecks {
+		return
+	}
+	if w.rechecks == nil {
+		w.rechecks = make(map[string]int)
+	}
+	w.rechecks[key] = attempt + 1
+
+	timer := time.NewTimer(mergeabilityRecheckDelay << attempt)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			w.Refresh(pr)
+		case <-done:
+		}
+	}()
+}
+
+func recheckKey(pr config.WatchedPR) string {
+	return fmt.Sprintf("%s/%s/%s#%d", pr.HostName(), strings.ToLower(pr.Owner), strings.ToLower(pr.Repo), pr.Number)
+}
+
+// needsMergeability reports whether a rule depends on GitHub's mergeability result.
+func needsMergeability(rule []string) bool {
+	for _, req := range rule {
+		switch req {
+		case config.MergeRequireMergeable, config.MergeRequireUpToDate, config.MergeRequireProtection:
+			return true
+		}
+	}
+	return false
+}
+
+// withoutMergeability returns the requirements of rule that do not depend on
+// GitHub's mergeability result.
+func withoutMergeability(rule []string) []string {
+	var reqs []string
+	for _, req := range rule {
+		if !needsMergeability([]string{req}) {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// meetsMergeRule reports whether snapshot satisfies every requirement of rule.
+func meetsMergeRule(rule []string, snapshot *prSnapshot) bool {
+	ghPR := snapshot.pr
+	mergeableState := github.NormalizeState(ghPR.MergeableState)
+	for _, req := range rule {
+		var ok bool
+		switch req {
+		case config.MergeRequireChecks:
+			ok = snapshot.state == "success"
+		case config.MergeRequireApproval:
+			ok = snapshot.reviewDecision == github.ReviewApproved
+		case config.MergeRequireMergeable:
+			ok = ghPR.Mergeable != nil && *ghPR.Mergeable && mergeableState != github.MergeableStateDirty
+		case config.MergeRequireNotDraft:
+			ok = !ghPR.Draft
+		case config.MergeRequireUpToDate:
+			ok = mergeableState != github.MergeableStateBehind
+		case config.MergeRequireProtection:
+			ok = mergeableState != github.MergeableStateBlocked
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// newReviewers returns the reviewers in current that are not in previous.
+func newReviewers(previous, current []string) []string {
+	seen := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		seen[name] = true
+	}
+	var added []string
+	for _, name := range current {
+		if !seen[name] {
+			added = append(added, name)
+		}
+	}
+	return added
+}
+
+// recordClosed stores a merged or closed PR and notifies once when it leaves the open state.
+// PRs that were already closed when first checked are recorded silently.
+func (w *Watcher) recordClosed(pr *config.WatchedPR, ghPR *github.PullRequest, lifecycle string) {
+	previous := pr.PRState
+	if previous == "" && pr.LastKnownState != "" {
+		previous = github.PRStateOpen
+	}
+
+	closedAt := time.Now()
+	if ghPR.MergedAt != nil {
+		closedAt = *ghPR.MergedAt
+	} else if ghPR.ClosedAt != nil {
+		closedAt = *ghPR.ClosedAt
+	}
+
+	if ghPR.Title != "" && ghPR.Title != pr.Title {
+		pr.Title = ghPR.Title
+	}
+	refreshAuthorAndLabels(pr, ghPR)
+
+	if previous != "" && previous != lifecycle {
+		eventType := notify.EventClosed
+		if lifecycle == github.PRStateMerged {
+			eventType = notify.EventMerged
+		}
+		event := &notify.StatusChangeEvent{
+			Type:          eventType,
+			Host:          pr.Host,
+			Owner:         pr.Owner,
+			Repo:          pr.Repo,
+			Number:        pr.Number,
+			Title:         pr.Title,
+			Author:        pr.Author,
+			Labels:        pr.Labels,
+			PreviousState: previous,
+			CurrentState:  lifecycle,
+			SHA:           ghPR.Head.SHA,
+			Timestamp:     closedAt,
+		}
+
+		w.emit(event, shouldNotifyClosed(w.config.NotificationFilter))
+	}
+
+	pr.PRState = lifecycle
+	if pr.ClosedAt.IsZero() {
+		pr.ClosedAt = closedAt
+	}
+	pr.LastKnownSHA = ghPR.Head.SHA
 	pr.LastChecked = time.Now()
+}
 
-	return nil
+// refreshAuthorAndLabels stores the author and labels of ghPR, which routes match on.
+func refreshAuthorAndLabels(pr *config.WatchedPR, ghPR *github.PullRequest) {
+	if ghPR.User.Login != "" {
+		pr.Author = ghPR.User.Login
+	}
+	pr.Labels = ghPR.LabelNames()
+}
+
+// recordEvent appends an event to the history, if enabled.
+func (w *Watcher) recordEvent(event *notify.StatusChangeEvent) {
+	if w.history == nil {
+		return
+	}
+	if err := w.history.RecordEvent(event); err != nil {
+		w.printf("Warning: failed to record history: %v\n", err)
+	}
+}
+
+// recordPoll appends the latest check result of pr to the history, if enabled.
+func (w *Watcher) recordPoll(pr *config.WatchedPR) {
+	if w.history == nil || !w.recordPolls {
+		return
+	}
+	if err := w.history.RecordPoll(*pr); err != nil {
+		w.printf("Warning: failed to record history: %v\n", err)
+	}
+}
+
+// CommitState fetches the legacy combined status, check suites, and check runs for a
+// commit and returns the aggregate state along with the per-check breakdown.
+func CommitState(client GitHubClient, owner, repo, sha string) (string, []github.Check, error) {
+	state, checks, _, err := commitState(client, owner, repo, sha)
+	return state, checks, err
+}
+
+// commitState is CommitState that also returns the raw check runs, so callers
+// that need them don't fetch them a second time.
+func commitState(client GitHubClient, owner, repo, sha string) (string, []github.Check, []github.CheckRun, error) {
+	status, err := client.GetCombinedStatus(owner, repo, sha)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to fetch status: %w", err)
+	}
+
+	suites, err := client.GetCheckSuites(owner, repo, sha)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to fetch check suites: %w", err)
+	}
+
+	runs, err := client.GetCheckRuns(owner, repo, sha)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to fetch check runs: %w", err)
+	}
+
+	state := github.AggregateState(status, suites.CheckSuites, runs.CheckRuns)
+	return state, github.CollectChecks(status, runs.CheckRuns), runs.CheckRuns, nil
 }
 
 func shouldNotify(filter, currentState string) bool {
@@ -147,7 +1150,30 @@ func shouldNotify(filter, currentState string) bool {
 		return currentState == "failure" || currentState == "error"
 	case config.NotificationFilterSuccess:
 		return currentState == "success"
+	case config.NotificationFilterReview:
+		return false
 	default:
 		return true
 	}
 }
+
+// shouldNotifyReview reports whether review events pass the notification filter.
+// The fail and success filters are about CI, so they leave review events out.
+func shouldNotifyReview(filter string) bool {
+	filter = config.NormalizeNotificationFilter(filter)
+	return filter == config.NotificationFilterChange || filter == config.NotificationFilterReview
+}
+
+// shouldNotifyClosed reports whether merged and closed events pass the
+// notification filter. They end the PR rather than report on CI or reviews, so
+// only the change filter lets them through.
+func shouldNotifyClosed(filter string) bool {
+	return config.NormalizeNotificationFilter(filter) == config.NotificationFilterChange
+}
+
+// shouldNotifyReady reports whether ready-to-merge events pass the notification
+// filter. Being ready implies passing CI, so the success filter lets them through.
+func shouldNotifyReady(filter string) bool {
+	filter = config.NormalizeNotificationFilter(filter)
+	return filter == config.NotificationFilterChange || filter == config.NotificationFilterSuccess
+}
@@ -1,14 +1,20 @@
 package watcher
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/devblac/prw/internal/config"
 	"github.com/devblac/prw/internal/github"
+	"github.com/devblac/prw/internal/history"
 	"github.com/devblac/prw/internal/notify"
 )
 
@@ -16,6 +22,10 @@ import (
 type mockGitHubClient struct {
 	prs      map[string]*github.PullRequest
 	statuses map[string]*github.CombinedStatus
+	suites   map[string]*github.CheckSuiteList
+	runs     map[string]*github.CheckRunList
+	reviews  map[string][]github.Review
+	searches map[string][]github.PullRequestRef
 	err      error
 }
 
@@ -42,6 +52,46 @@ func (m *mockGitHubClient) GetCombinedStatus(owner, repo, ref string) (*github.C
 	return status, nil
 }
 
+func (m *mockGitHubClient) GetCheckSuites(owner, repo, ref string) (*github.CheckSuiteList, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	suites, ok := m.suites[ref]
+	if !ok {
+		return &github.CheckSuiteList{}, nil
+	}
+	return suites, nil
+}
+
+func (m *mockGitHubClient) GetCheckRuns(owner, repo, ref string) (*github.CheckRunList, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	runs, ok := m.runs[ref]
+	if !ok {
+		return &github.CheckRunList{}, nil
+	}
+	return runs, nil
+}
+
+func (m *mockGitHubClient) GetReviews(owner, repo string, number int) ([]github.Review, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.reviews[fmt.Sprintf("%s/%s/%d", owner, repo, number)], nil
+}
+
+func (m *mockGitHubClient) SearchPullRequests(query string) ([]github.PullRequestRef, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	refs, ok := m.searches[query]
+	if !ok {
+		return nil, fmt.Errorf("unexpected search %q", query)
+	}
+	return refs, nil
+}
+
 // mockNotifier implements Notifier for testing.
 type mockNotifier struct {
 	events []*notify.StatusChangeEvent
@@ -347,6 +397,54 @@ func TestWatcherRunOnce(t *testing.T) {
 	}
 }
 
+// digestNotifier holds events back until it is flushed.
+type digestNotifier struct {
+	held    []*notify.StatusChangeEvent
+	flushed []*notify.StatusChangeEvent
+}
+
+func (d *digestNotifier) Notify(event *notify.StatusChangeEvent) error {
+	d.held = append(d.held, event)
+	return nil
+}
+
+func (d *digestNotifier) Flush() error {
+	d.flushed = append(d.flushed, d.held...)
+	d.held = nil
+	return nil
+}
+
+func TestWatcherFlushesNotifierOnReturn(t *testing.T) {
+	for _, mode := range []string{"once", "loop"} {
+		t.Run(mode, func(t *testing.T) {
+			pr := &github.PullRequest{Number: 1, Title: "Test PR"}
+			pr.Head.SHA = "sha123"
+			client := &mockGitHubClient{
+				prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+				statuses: map[string]*github.CombinedStatus{"sha123": {State: "failure", SHA: "sha123"}},
+			}
+			cfg := &config.Config{
+				PollIntervalSeconds: 1,
+				WatchedPRs:          []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"}},
+			}
+			notifier := &digestNotifier{}
+			w := New(client, cfg, notifier)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if mode == "once" {
+				w.RunOnce(ctx)
+			} else {
+				w.Run(ctx)
+			}
+
+			if len(notifier.held) != 0 || len(notifier.flushed) != 1 {
+				t.Errorf("expected the held event to be flushed on return, held %d, flushed %d", len(notifier.held), len(notifier.flushed))
+			}
+		})
+	}
+}
+
 func TestWatcherUpdateTitle(t *testing.T) {
 	pr := &github.PullRequest{
 		Number: 1,
@@ -648,3 +746,1566 @@ func TestShouldNotify(t *testing.T) {
 		})
 	}
 }
+
+func TestWatcherCheckRunsOnly(t *testing.T) {
+	pr := &github.PullRequest{
+		Number: 1,
+		Title:  "Actions PR",
+	}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": pr,
+		},
+		statuses: map[string]*github.CombinedStatus{
+			// No legacy statuses: GitHub reports pending with zero total_count.
+			"sha123": {State: "pending", SHA: "sha123"},
+		},
+		runs: map[string]*github.CheckRunList{
+			"sha123": {
+				TotalCount: 2,
+				CheckRuns: []github.CheckRun{
+					{Name: "build", Status: "completed", Conclusion: "success"},
+					{Name: "test", Status: "completed", Conclusion: "failure", HTMLURL: "https://github.com/owner/repo/runs/2"},
+				},
+			},
+		},
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.CurrentState != "failure" {
+		t.Errorf("expected aggregate state 'failure', got %q", event.CurrentState)
+	}
+	if len(event.Checks) != 2 {
+		t.Fatalf("expected 2 checks on event, got %d", len(event.Checks))
+	}
+	if event.Checks[1].Name != "test" || event.Checks[1].State != "failure" {
+		t.Errorf("unexpected check breakdown: %+v", event.Checks[1])
+	}
+}
+
+func TestWatcherCheckRunsPendingSuite(t *testing.T) {
+	pr := &github.PullRequest{Number: 1}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": pr,
+		},
+		statuses: map[string]*github.CombinedStatus{
+			"sha123": {State: "success", SHA: "sha123", TotalCount: 1, Statuses: []github.Status{{Context: "ci/legacy", State: "success"}}},
+		},
+		suites: map[string]*github.CheckSuiteList{
+			"sha123": {CheckSuites: []github.CheckSuite{{Status: "in_progress", LatestCheckRunsCount: 1}}},
+		},
+		runs: map[string]*github.CheckRunList{
+			"sha123": {CheckRuns: []github.CheckRun{{Name: "lint", Status: "in_progress"}}},
+		},
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1},
+		},
+	}
+
+	w := New(client, cfg, &mockNotifier{})
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+
+	if cfg.WatchedPRs[0].LastKnownState != "pending" {
+		t.Errorf("expected state 'pending' while check runs are in progress, got %q", cfg.WatchedPRs[0].LastKnownState)
+	}
+}
+
+func TestWatcherMergedPR(t *testing.T) {
+	mergedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Number:   1,
+		Title:    "Test PR",
+		State:    "closed",
+		Merged:   true,
+		MergedAt: &mergedAt,
+	}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": pr,
+		},
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "success"},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.Type != notify.EventMerged {
+		t.Errorf("expected merged event, got %q", event.Type)
+	}
+	if !event.Timestamp.Equal(mergedAt) {
+		t.Errorf("expected event timestamp to be merge time, got %v", event.Timestamp)
+	}
+
+	updated := cfg.WatchedPRs[0]
+	if updated.PRState != github.PRStateMerged || !updated.ClosedAt.Equal(mergedAt) {
+		t.Errorf("expected merged PR state to be recorded, got %+v", updated)
+	}
+	if updated.LastKnownState != "success" {
+		t.Errorf("CI state should be left untouched, got %q", updated.LastKnownState)
+	}
+
+	// A second check must not notify again
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 1 {
+		t.Errorf("expected no repeated notification, got %d", len(notifier.events))
+	}
+}
+
+func TestWatcherClosedPRNotificationFilter(t *testing.T) {
+	for _, filter := range []string{config.NotificationFilterFail, config.NotificationFilterSuccess, config.NotificationFilterReview} {
+		pr := &github.PullRequest{Number: 1, Title: "Test PR", State: "closed"}
+		pr.Head.SHA = "sha123"
+		client := &mockGitHubClient{prs: map[string]*github.PullRequest{"owner/repo/1": pr}}
+
+		cfg := &config.Config{
+			NotificationFilter: filter,
+			WatchedPRs:         []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "failure"}},
+		}
+		notifier := &mockNotifier{}
+		w := New(client, cfg, notifier)
+		store := history.NewStore(filepath.Join(t.TempDir(), history.FileName))
+		w.SetHistory(store, false)
+
+		if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+			t.Fatalf("checkPR failed: %v", err)
+		}
+		if len(notifier.events) != 0 {
+			t.Errorf("filter %s: expected the close to be filtered out, got %+v", filter, notifier.events)
+		}
+		if cfg.WatchedPRs[0].PRState != github.PRStateClosed {
+			t.Errorf("filter %s: expected the close to be recorded, got %q", filter, cfg.WatchedPRs[0].PRState)
+		}
+		if records, _ := store.Query(history.Filter{}); len(records) != 1 || records[0].Type != notify.EventClosed {
+			t.Errorf("filter %s: expected the close in the history, got %+v", filter, records)
+		}
+	}
+}
+
+func TestWatcherClosedPRFirstCheck(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "closed"}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": pr,
+		},
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+
+	if len(notifier.events) != 0 {
+		t.Errorf("expected no notification for a PR that was already closed, got %d", len(notifier.events))
+	}
+	if cfg.WatchedPRs[0].PRState != github.PRStateClosed {
+		t.Errorf("expected closed state to be recorded, got %q", cfg.WatchedPRs[0].PRState)
+	}
+}
+
+func TestWatcherUnwatchesClosedPRs(t *testing.T) {
+	open := &github.PullRequest{Number: 1, State: "open"}
+	open.Head.SHA = "sha1"
+	closed := &github.PullRequest{Number: 2, State: "closed"}
+	closed.Head.SHA = "sha2"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": open,
+			"owner/repo/2": closed,
+		},
+		statuses: map[string]*github.CombinedStatus{
+			"sha1": {State: "success", SHA: "sha1"},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	cfg := &config.Config{
+		ClosedPRPolicy: config.ClosedPRPolicyUnwatch,
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"},
+			{Owner: "owner", Repo: "repo", Number: 2, LastKnownState: "pending"},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.checkAllPRs()
+
+	if len(notifier.events) != 2 {
+		t.Fatalf("expected status and closed notifications, got %d", len(notifier.events))
+	}
+	if notifier.events[1].Type != notify.EventClosed {
+		t.Errorf("expected closed event, got %q", notifier.events[1].Type)
+	}
+	if len(cfg.WatchedPRs) != 1 || cfg.WatchedPRs[0].Number != 1 {
+		t.Errorf("expected closed PR to be unwatched, got %+v", cfg.WatchedPRs)
+	}
+}
+
+// slowGitHubClient delays every PR fetch and records how many run at once.
+type slowGitHubClient struct {
+	mockGitHubClient
+	delay    func(number int) time.Duration
+	inFlight int32
+	maxSeen  int32
+}
+
+func (s *slowGitHubClient) GetPullRequest(owner, repo string, number int) (*github.PullRequest, error) {
+	n := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		seen := atomic.LoadInt32(&s.maxSeen)
+		if n <= seen || atomic.CompareAndSwapInt32(&s.maxSeen, seen, n) {
+			break
+		}
+	}
+	time.Sleep(s.delay(number))
+	return s.mockGitHubClient.GetPullRequest(owner, repo, number)
+}
+
+// syncNotifier is a goroutine-safe notifier that records events in order.
+type syncNotifier struct {
+	mu     sync.Mutex
+	events []*notify.StatusChangeEvent
+}
+
+func (n *syncNotifier) Notify(event *notify.StatusChangeEvent) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+	return nil
+}
+
+func newSlowClient(count int, delay func(number int) time.Duration) (*slowGitHubClient, []config.WatchedPR) {
+	client := &slowGitHubClient{
+		mockGitHubClient: mockGitHubClient{
+			prs:      map[string]*github.PullRequest{},
+			statuses: map[string]*github.CombinedStatus{},
+		},
+		delay: delay,
+	}
+	var prs []config.WatchedPR
+	for i := 1; i <= count; i++ {
+		sha := fmt.Sprintf("sha%d", i)
+		pr := &github.PullRequest{Number: i, Title: fmt.Sprintf("PR %d", i)}
+		pr.Head.SHA = sha
+		client.prs[fmt.Sprintf("owner/repo/%d", i)] = pr
+		client.statuses[sha] = &github.CombinedStatus{State: "success", SHA: sha}
+		prs = append(prs, config.WatchedPR{Owner: "owner", Repo: "repo", Number: i, LastKnownState: "pending"})
+	}
+	return client, prs
+}
+
+// blockingNotifier blocks every notification until release is closed.
+type blockingNotifier struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (n *blockingNotifier) Notify(event *notify.StatusChangeEvent) error {
+	n.started <- struct{}{}
+	<-n.release
+	return nil
+}
+
+func TestWatcherNotifiesWithoutHoldingLock(t *testing.T) {
+	client, prs := newSlowClient(1, func(int) time.Duration { return 0 })
+
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	cfg := &config.Config{WatchedPRs: prs}
+	notifier := &blockingNotifier{started: make(chan struct{}), release: make(chan struct{})}
+	w := New(client, cfg, notifier)
+	w.SetOutput(io.Discard)
+
+	done := make(chan struct{})
+	go func() {
+		w.checkAllPRs()
+		close(done)
+	}()
+	<-notifier.started
+
+	// The watch list stays usable while a notification is in flight.
+	unblocked := make(chan struct{})
+	go func() {
+		w.WatchedPRs()
+		w.AddPR(config.WatchedPR{Owner: "owner", Repo: "repo", Number: 99})
+		close(unblocked)
+	}()
+	select {
+	case <-unblocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch list blocked while a notification was being sent")
+	}
+
+	close(notifier.release)
+	<-done
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.WatchedPRs) != 2 || loaded.WatchedPRs[0].LastKnownState != "success" {
+		t.Errorf("expected the cycle and the added PR to be saved, got %+v", loaded.WatchedPRs)
+	}
+}
+
+func TestWatcherConcurrencyLimit(t *testing.T) {
+	client, prs := newSlowClient(12, func(int) time.Duration { return 20 * time.Millisecond })
+
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	cfg := &config.Config{MaxConcurrency: 3, WatchedPRs: prs}
+	notifier := &syncNotifier{}
+	w := New(client, cfg, notifier)
+
+	w.checkAllPRs()
+
+	if max := atomic.LoadInt32(&client.maxSeen); max > 3 {
+		t.Errorf("expected at most 3 concurrent fetches, saw %d", max)
+	} else if max < 2 {
+		t.Errorf("expected fetches to run concurrently, saw %d at most", max)
+	}
+	if len(notifier.events) != 12 {
+		t.Fatalf("expected 12 notifications, got %d", len(notifier.events))
+	}
+	for i := range cfg.WatchedPRs {
+		if cfg.WatchedPRs[i].LastKnownState != "success" {
+			t.Errorf("PR #%d state not updated: %q", cfg.WatchedPRs[i].Number, cfg.WatchedPRs[i].LastKnownState)
+		}
+	}
+}
+
+func TestWatcherConcurrentNotificationOrder(t *testing.T) {
+	// Earlier PRs finish last, so completion order is the reverse of the watch list.
+	client, prs := newSlowClient(8, func(number int) time.Duration {
+		return time.Duration(9-number) * 5 * time.Millisecond
+	})
+
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	cfg := &config.Config{MaxConcurrency: 8, WatchedPRs: prs}
+	notifier := &syncNotifier{}
+	w := New(client, cfg, notifier)
+
+	w.checkAllPRs()
+
+	if len(notifier.events) != 8 {
+		t.Fatalf("expected 8 notifications, got %d", len(notifier.events))
+	}
+	for i, event := range notifier.events {
+		if event.Number != i+1 {
+			t.Errorf("notification %d is for PR #%d, expected watch-list order", i, event.Number)
+		}
+	}
+}
+
+func TestWatcherDefaultConcurrency(t *testing.T) {
+	w := New(&mockGitHubClient{}, &config.Config{}, &mockNotifier{})
+	if got := w.concurrency(); got != config.DefaultMaxConcurrency {
+		t.Errorf("expected default concurrency %d, got %d", config.DefaultMaxConcurrency, got)
+	}
+
+	w = New(&mockGitHubClient{}, &config.Config{MaxConcurrency: 1}, &mockNotifier{})
+	if got := w.concurrency(); got != 1 {
+		t.Errorf("expected configured concurrency 1, got %d", got)
+	}
+}
+
+func TestWatcherConcurrencyOverrideNotSaved(t *testing.T) {
+	client, prs := newSlowClient(2, func(int) time.Duration { return 0 })
+
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	cfg := &config.Config{MaxConcurrency: 2, WatchedPRs: prs}
+	w := New(client, cfg, &syncNotifier{})
+	w.SetConcurrency(8)
+	if got := w.concurrency(); got != 8 {
+		t.Errorf("expected the override to apply, got %d", got)
+	}
+
+	w.checkAllPRs()
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if saved.MaxConcurrency != 2 || cfg.MaxConcurrency != 2 {
+		t.Errorf("expected max_concurrency to stay 2, saved %d, in memory %d", saved.MaxConcurrency, cfg.MaxConcurrency)
+	}
+}
+
+func TestWatcherUsesClientPerHost(t *testing.T) {
+	newClient := func(state string) *mockGitHubClient {
+		pr := &github.PullRequest{Number: 1, Title: "PR", State: "open"}
+		pr.Head.SHA = "sha-" + state
+		return &mockGitHubClient{
+			prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+			statuses: map[string]*github.CombinedStatus{pr.Head.SHA: {State: state, SHA: pr.Head.SHA}},
+		}
+	}
+
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"},
+			{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(newClient("success"), cfg, notifier)
+	w.SetClient("GitHub.Example.com", newClient("failure"))
+
+	for i := range cfg.WatchedPRs {
+		if err := w.checkPR(&cfg.WatchedPRs[i]); err != nil {
+			t.Fatalf("checkPR failed: %v", err)
+		}
+	}
+
+	if cfg.WatchedPRs[0].LastKnownState != "success" || cfg.WatchedPRs[1].LastKnownState != "failure" {
+		t.Errorf("expected each PR to be checked against its own host, got %q and %q",
+			cfg.WatchedPRs[0].LastKnownState, cfg.WatchedPRs[1].LastKnownState)
+	}
+	if len(notifier.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(notifier.events))
+	}
+	if url := notifier.events[1].URL(); url != "https://github.example.com/owner/repo/pull/1" {
+		t.Errorf("expected enterprise PR URL, got %s", url)
+	}
+}
+
+func TestWatcherMissingHostClient(t *testing.T) {
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{
+			{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 1},
+		},
+	}
+
+	w := New(&mockGitHubClient{}, cfg, &mockNotifier{})
+	err := w.checkPR(&cfg.WatchedPRs[0])
+	if err == nil {
+		t.Fatal("expected error for host without client")
+	}
+	if err.Error() != "no GitHub client configured for github.example.com" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWatcherRecordsHistory(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, Title: "Test PR", State: "open"}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+	}
+
+	cfg := &config.Config{
+		NotificationFilter: config.NotificationFilterFail,
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"},
+		},
+	}
+
+	store := history.NewStore(filepath.Join(t.TempDir(), history.FileName))
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.SetHistory(store, false)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 0 {
+		t.Errorf("expected the fail filter to suppress the notification, got %d events", len(notifier.events))
+	}
+
+	records, err := store.Query(history.Filter{IncludePolls: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(records) != 1 || records[0].PreviousState != "pending" || records[0].CurrentState != "success" {
+		t.Fatalf("expected the filtered transition to be recorded, got %+v", records)
+	}
+
+	// With poll results enabled, unchanged checks are recorded too
+	w.SetHistory(store, true)
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	records, _ = store.Query(history.Filter{IncludePolls: true})
+	if len(records) != 2 || records[1].Kind != history.KindPoll || records[1].CurrentState != "success" {
+		t.Errorf("expected a poll record, got %+v", records)
+	}
+}
+
+func TestWatcherRunBackgroundCheckNow(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	pr := &github.PullRequest{Number: 1, Title: "Added later", State: "open"}
+	pr.Head.SHA = "sha123"
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+	}
+
+	// An hour-long interval means only CheckNow can trigger the second cycle
+	cfg := &config.Config{PollIntervalSeconds: 3600, WatchedPRs: []config.WatchedPR{}}
+	w := New(client, cfg, &mockNotifier{})
+
+	cycles := make(chan struct{}, 4)
+	w.OnCycle(func() { cycles <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.RunBackground(ctx) }()
+
+	select {
+	case <-cycles:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an initial cycle with an empty watch list")
+	}
+
+	if added, err := w.AddPR(config.WatchedPR{Owner: "owner", Repo: "repo", Number: 1}); !added || err != nil {
+		t.Fatalf("AddPR = %v, %v", added, err)
+	}
+	w.CheckNow()
+
+	select {
+	case <-cycles:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected CheckNow to trigger a cycle")
+	}
+
+	prs := w.WatchedPRs()
+	if len(prs) != 1 || prs[0].LastKnownState != "success" || prs[0].Title != "Added later" {
+		t.Errorf("expected the added PR to be checked, got %+v", prs)
+	}
+
+	if removed, err := w.RemovePR("", "owner", "repo", 1); !removed || err != nil {
+		t.Errorf("RemovePR = %v, %v", removed, err)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatcherMatchPRs(t *testing.T) {
+	cfg := &config.Config{WatchedPRs: []config.WatchedPR{
+		{Owner: "owner", Repo: "repo", Number: 1, LastKnownSHA: "sha1"},
+		{Owner: "owner", Repo: "repo", Number: 2, LastKnownSHA: "sha2"},
+		{Owner: "owner", Repo: "other", Number: 3, LastKnownSHA: "sha1"},
+		{Host: "ghe.example.com", Owner: "owner", Repo: "repo", Number: 1, LastKnownSHA: "sha1"},
+	}}
+	w := New(&mockGitHubClient{}, cfg, &mockNotifier{})
+
+	numbers := func(prs []config.WatchedPR) []string {
+		var out []string
+		for _, pr := range prs {
+			out = append(out, fmt.Sprintf("%s/%s/%s#%d", pr.HostName(), pr.Owner, pr.Repo, pr.Number))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		owner   string
+		repo    string
+		sha     string
+		numbers []int
+		want    []string
+	}{
+		{"by sha", "github.com", "owner", "repo", "sha2", nil, []string{"github.com/owner/repo#2"}},
+		{"by number", "", "Owner", "Repo", "", []int{1}, []string{"github.com/owner/repo#1"}},
+		{"sha or number", "github.com", "owner", "repo", "sha2", []int{1}, []string{"github.com/owner/repo#1", "github.com/owner/repo#2"}},
+		{"enterprise host", "GHE.example.com", "owner", "repo", "sha1", nil, []string{"ghe.example.com/owner/repo#1"}},
+		{"unknown sha", "github.com", "owner", "repo", "nope", nil, nil},
+		{"unwatched repo", "github.com", "someone", "repo", "sha1", []int{1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := numbers(w.MatchPRs(tt.host, tt.owner, tt.repo, tt.sha, tt.numbers))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("MatchPRs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatcherRefreshWithoutPolling(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	pr1 := &github.PullRequest{Number: 1, State: "open"}
+	pr1.Head.SHA = "sha1"
+	pr2 := &github.PullRequest{Number: 2, State: "open"}
+	pr2.Head.SHA = "sha2"
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{"owner/repo/1": pr1, "owner/repo/2": pr2},
+		statuses: map[string]*github.CombinedStatus{
+			"sha1": {State: "pending", SHA: "sha1"},
+			"sha2": {State: "pending", SHA: "sha2"},
+		},
+	}
+
+	cfg := &config.Config{PollIntervalSeconds: 1, WatchedPRs: []config.WatchedPR{
+		{Owner: "owner", Repo: "repo", Number: 1},
+		{Owner: "owner", Repo: "repo", Number: 2},
+	}}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.SetOutput(io.Discard)
+	w.SetFallbackInterval(0)
+
+	cycles := make(chan struct{}, 4)
+	w.OnCycle(func() { cycles <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.RunBackground(ctx) }()
+
+	select {
+	case <-cycles:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an initial cycle")
+	}
+
+	// Both PRs go green, but only the refreshed one is checked.
+	client.statuses["sha1"] = &github.CombinedStatus{State: "success", SHA: "sha1"}
+	client.statuses["sha2"] = &github.CombinedStatus{State: "success", SHA: "sha2"}
+	matched := w.MatchPRs("github.com", "owner", "repo", "sha1", nil)
+	w.Refresh(matched...)
+	w.Refresh(matched...)
+
+	select {
+	case <-cycles:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Refresh to trigger a check")
+	}
+
+	// The interval is 1s, so a poll would have happened by now if polling were enabled.
+	select {
+	case <-cycles:
+		t.Fatal("expected no polling in webhook mode without a fallback interval")
+	case <-time.After(1500 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if len(notifier.events) != 1 || notifier.events[0].Number != 1 || notifier.events[0].CurrentState != "success" {
+		t.Errorf("expected one success event for PR 1, got %+v", notifier.events)
+	}
+	prs := w.WatchedPRs()
+	if prs[0].LastKnownState != "success" || prs[1].LastKnownState != "pending" {
+		t.Errorf("expected only PR 1 to be refreshed, got %+v", prs)
+	}
+}
+
+func TestWatcherReviewEvents(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, Title: "Review me", State: "open"}
+	pr.Head.SHA = "sha123"
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+		reviews:  map[string][]github.Review{},
+	}
+	cfg := &config.Config{WatchedPRs: []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1}}}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	check := func() []string {
+		t.Helper()
+		notifier.events = nil
+		if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+			t.Fatalf("checkPR failed: %v", err)
+		}
+		var got []string
+		for _, e := range notifier.events {
+			got = append(got, fmt.Sprintf("%s:%s->%s%v", e.EventType(), e.PreviousState, e.CurrentState, e.Reviewers))
+		}
+		return got
+	}
+
+	// The first check only records the review state.
+	pr.RequestedReviewers = []github.User{{Login: "alice"}}
+	if got := check(); len(got) != 0 {
+		t.Errorf("first check: expected no events, got %v", got)
+	}
+	if cfg.WatchedPRs[0].ReviewDecision != github.ReviewRequired {
+		t.Errorf("expected review_required, got %q", cfg.WatchedPRs[0].ReviewDecision)
+	}
+
+	// A new reviewer is assigned.
+	pr.RequestedReviewers = []github.User{{Login: "alice"}, {Login: "bob"}}
+	if got, want := check(), []string{"review_requested:review_required->review_required[bob]"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("new reviewer: events = %v, want %v", got, want)
+	}
+
+	// Alice requests changes.
+	pr.RequestedReviewers = []github.User{{Login: "bob"}}
+	client.reviews["owner/repo/1"] = []github.Review{{User: github.User{Login: "alice"}, State: "CHANGES_REQUESTED"}}
+	if got, want := check(), []string{"changes_requested:review_required->changes_requested[]"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("changes requested: events = %v, want %v", got, want)
+	}
+
+	// Alice approves after the fix; nothing else changed, so no repeat events.
+	client.reviews["owner/repo/1"] = append(client.reviews["owner/repo/1"], github.Review{User: github.User{Login: "alice"}, State: "APPROVED"})
+	if got, want := check(), []string{"approved:changes_requested->approved[]"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("approved: events = %v, want %v", got, want)
+	}
+	if got := check(); len(got) != 0 {
+		t.Errorf("unchanged reviews: expected no events, got %v", got)
+	}
+}
+
+func TestWatcherReviewNotificationFilter(t *testing.T) {
+	tests := []struct {
+		filter    string
+		wantTypes []string
+	}{
+		{config.NotificationFilterChange, []string{notify.EventStatusChange, notify.EventApproved}},
+		{config.NotificationFilterReview, []string{notify.EventApproved}},
+		{config.NotificationFilterSuccess, []string{notify.EventStatusChange}},
+		{config.NotificationFilterFail, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			pr := &github.PullRequest{Number: 1, State: "open"}
+			pr.Head.SHA = "sha123"
+			client := &mockGitHubClient{
+				prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+				statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+				reviews: map[string][]github.Review{
+					"owner/repo/1": {{User: github.User{Login: "alice"}, State: "APPROVED"}},
+				},
+			}
+			cfg := &config.Config{
+				NotificationFilter: tt.filter,
+				WatchedPRs: []config.WatchedPR{{
+					Owner: "owner", Repo: "repo", Number: 1,
+					LastKnownState: "pending", ReviewDecision: github.ReviewRequired,
+				}},
+			}
+			notifier := &mockNotifier{}
+			w := New(client, cfg, notifier)
+
+			if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+				t.Fatalf("checkPR failed: %v", err)
+			}
+
+			var got []string
+			for _, e := range notifier.events {
+				got = append(got, e.EventType())
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantTypes) {
+				t.Errorf("notified %v, want %v", got, tt.wantTypes)
+			}
+			if cfg.WatchedPRs[0].ReviewDecision != github.ReviewApproved {
+				t.Errorf("expected the review decision to be stored regardless of the filter, got %q", cfg.WatchedPRs[0].ReviewDecision)
+			}
+		})
+	}
+}
+
+func TestWatcherReadyToMerge(t *testing.T) {
+	mergeable := true
+	pr := &github.PullRequest{Number: 1, Title: "Ship it", State: "open", Mergeable: &mergeable, MergeableState: "blocked"}
+	pr.Head.SHA = "sha123"
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{"sha123": {State: "pending", SHA: "sha123"}},
+		reviews:  map[string][]github.Review{},
+	}
+	cfg := &config.Config{WatchedPRs: []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1}}}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	readyEvents := func() int {
+		t.Helper()
+		notifier.events = nil
+		if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+			t.Fatalf("checkPR failed: %v", err)
+		}
+		n := 0
+		for _, e := range notifier.events {
+			if e.EventType() == notify.EventReadyToMerge {
+				n++
+				if e.PreviousState != notify.ReadyStateNotReady || e.CurrentState != notify.ReadyStateReady {
+					t.Errorf("unexpected ready states %s -> %s", e.PreviousState, e.CurrentState)
+				}
+			}
+		}
+		return n
+	}
+
+	if n := readyEvents(); n != 0 {
+		t.Errorf("first check: expected no ready event, got %d", n)
+	}
+	if cfg.WatchedPRs[0].MergeableState != "blocked" || cfg.WatchedPRs[0].ReadyToMerge {
+		t.Errorf("expected blocked and not ready, got %q ready=%v", cfg.WatchedPRs[0].MergeableState, cfg.WatchedPRs[0].ReadyToMerge)
+	}
+
+	// CI passes but the PR is not approved yet.
+	client.statuses["sha123"] = &github.CombinedStatus{State: "success", SHA: "sha123"}
+	if n := readyEvents(); n != 0 {
+		t.Errorf("unapproved: expected no ready event, got %d", n)
+	}
+
+	// Approval completes the default rule; exactly one event fires.
+	client.reviews["owner/repo/1"] = []github.Review{{User: github.User{Login: "alice"}, State: "APPROVED"}}
+	pr.MergeableState = "clean"
+	if n := readyEvents(); n != 1 {
+		t.Errorf("ready: expected one ready event, got %d", n)
+	}
+	if n := readyEvents(); n != 0 {
+		t.Errorf("still ready: expected no repeat event, got %d", n)
+	}
+
+	// A conflict resets readiness, so resolving it notifies again.
+	mergeable = false
+	pr.MergeableState = "dirty"
+	if n := readyEvents(); n != 0 || cfg.WatchedPRs[0].ReadyToMerge {
+		t.Errorf("conflict: expected not ready and no event, got %d events ready=%v", n, cfg.WatchedPRs[0].ReadyToMerge)
+	}
+	mergeable = true
+	pr.MergeableState = "clean"
+	if n := readyEvents(); n != 1 {
+		t.Errorf("resolved: expected one ready event, got %d", n)
+	}
+}
+
+func TestWatcherReadyToMergeUnknownMergeability(t *testing.T) {
+	oldDelay := mergeabilityRecheckDelay
+	mergeabilityRecheckDelay = time.Millisecond
+	defer func() { mergeabilityRecheckDelay = oldDelay }()
+
+	pr := &github.PullRequest{Number: 1, State: "open", MergeableState: "unknown"}
+	pr.Head.SHA = "sha123"
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+		reviews: map[string][]github.Review{
+			"owner/repo/1": {{User: github.User{Login: "alice"}, State: "APPROVED"}},
+		},
+	}
+	cfg := &config.Config{WatchedPRs: []config.WatchedPR{{
+		Owner: "owner", Repo: "repo", Number: 1,
+		LastKnownState: "success", ReviewDecision: github.ReviewApproved, MergeableState: "blocked",
+	}}}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+
+	// Outside the loop, e.g. in once mode, nothing would pick up a recheck
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	select {
+	case <-w.refresh:
+		t.Fatal("expected no recheck outside the loop")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	done := make(chan struct{})
+	w.loopDone = done
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 0 {
+		t.Errorf("expected no events while mergeability is unknown, got %d", len(notifier.events))
+	}
+	if cfg.WatchedPRs[0].MergeableState != "blocked" {
+		t.Errorf("expected the previous mergeable state to be kept, got %q", cfg.WatchedPRs[0].MergeableState)
+	}
+
+	// The PR is queued for another check once GitHub has had time to compute it.
+	select {
+	case <-w.refresh:
+	case <-time.After(time.Second):
+		t.Fatal("expected a refresh to be scheduled")
+	}
+	if pending := w.takePending(); len(pending) != 1 || pending[0].Number != 1 {
+		t.Errorf("expected PR #1 to be pending, got %v", pending)
+	}
+
+	// Rechecks stop after maxMergeabilityRechecks in a row
+	for i := 1; i < maxMergeabilityRechecks; i++ {
+		w.checkPR(&cfg.WatchedPRs[0])
+		<-w.refresh
+	}
+	w.checkPR(&cfg.WatchedPRs[0])
+	select {
+	case <-w.refresh:
+		t.Fatal("expected no recheck past the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A known result resets the count, and stopping the loop drops a pending recheck
+	mergeable := true
+	pr.Mergeable, pr.MergeableState = &mergeable, "clean"
+	w.checkPR(&cfg.WatchedPRs[0])
+	pr.Mergeable, pr.MergeableState = nil, "unknown"
+	mergeabilityRecheckDelay = 20 * time.Millisecond
+	w.checkPR(&cfg.WatchedPRs[0])
+	close(done)
+	select {
+	case <-w.refresh:
+		t.Fatal("expected the pending recheck to be dropped when the loop stops")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if w.rechecks[recheckKey(cfg.WatchedPRs[0])] != 1 {
+		t.Errorf("expected the recheck count to restart, got %d", w.rechecks[recheckKey(cfg.WatchedPRs[0])])
+	}
+}
+
+func TestWatcherReadyToMergeRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     map[string][]string
+		filter    string
+		wantReady bool
+	}{
+		{"default rule needs approval", nil, "", false},
+		{"repo rule", map[string][]string{"owner/repo": {config.MergeRequireChecks}}, "", true},
+		{"owner rule", map[string][]string{"owner/*": {config.MergeRequireChecks, config.MergeRequireNotDraft}}, "", true},
+		{"success filter", map[string][]string{"*": {config.MergeRequireChecks}}, config.NotificationFilterSuccess, true},
+		{"fail filter", map[string][]string{"*": {config.MergeRequireChecks}}, config.NotificationFilterFail, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Mergeability is still unknown, which rules without mergeability requirements don't need.
+			pr := &github.PullRequest{Number: 1, State: "open"}
+			pr.Head.SHA = "sha123"
+			client := &mockGitHubClient{
+				prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+				statuses: map[string]*github.CombinedStatus{"sha123": {State: "success", SHA: "sha123"}},
+				reviews:  map[string][]github.Review{},
+			}
+			cfg := &config.Config{
+				NotificationFilter: tt.filter,
+				MergeRules:         tt.rules,
+				WatchedPRs: []config.WatchedPR{{
+					Owner: "owner", Repo: "repo", Number: 1,
+					LastKnownState: "success", ReviewDecision: github.ReviewNone, MergeableState: "unknown",
+				}},
+			}
+			notifier := &mockNotifier{}
+			w := New(client, cfg, notifier)
+
+			if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+				t.Fatalf("checkPR failed: %v", err)
+			}
+			gotReady := len(notifier.events) == 1 && notifier.events[0].EventType() == notify.EventReadyToMerge
+			if gotReady != tt.wantReady {
+				t.Errorf("ready notification = %v, want %v (events: %d)", gotReady, tt.wantReady, len(notifier.events))
+			}
+		})
+	}
+}
+
+func TestWatcherReconcilesSubscriptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigPath := config.ConfigPath
+	defer func() { config.ConfigPath = oldConfigPath }()
+	config.ConfigPath = func() (string, error) {
+		return filepath.Join(tmpDir, "config.json"), nil
+	}
+
+	newPR := func(number int, state string) *github.PullRequest {
+		pr := &github.PullRequest{Number: number, Title: fmt.Sprintf("PR %d", number), State: state}
+		pr.Head.SHA = fmt.Sprintf("sha%d", number)
+		return pr
+	}
+	sub, _ := config.NewSubscription("", config.SubscriptionRepo, "owner/repo")
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": newPR(1, "open"),
+			"owner/repo/2": newPR(2, "open"),
+		},
+		statuses: map[string]*github.CombinedStatus{},
+		reviews:  map[string][]github.Review{},
+		searches: map[string][]github.PullRequestRef{
+			sub.Query(): {{Owner: "owner", Repo: "repo", Number: 1}, {Owner: "owner", Repo: "repo", Number: 2}},
+		},
+	}
+	cfg := &config.Config{PollIntervalSeconds: 1, Subscriptions: []config.Subscription{sub}}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	var out bytes.Buffer
+	w.SetOutput(&out)
+
+	w.checkAllPRs()
+	if len(cfg.WatchedPRs) != 2 {
+		t.Fatalf("expected both PRs to be watched, got %+v", cfg.WatchedPRs)
+	}
+	for _, pr := range cfg.WatchedPRs {
+		if pr.Subscription != sub.Key() || pr.LastKnownState != "pending" || pr.Title == "" {
+			t.Errorf("expected a checked PR owned by the subscription, got %+v", pr)
+		}
+	}
+	if len(notifier.events) != 0 {
+		t.Errorf("expected newly subscribed PRs to be recorded silently, got %d events", len(notifier.events))
+	}
+
+	// PR 2 is merged and leaves the search results: the merge is still reported.
+	merged := newPR(2, "closed")
+	merged.Merged = true
+	client.prs["owner/repo/2"] = merged
+	client.searches[sub.Query()] = client.searches[sub.Query()][:1]
+
+	w.nextSearch = nil // searches are throttled; run the next one right away
+	w.checkAllPRs()
+	if len(cfg.WatchedPRs) != 1 || cfg.WatchedPRs[0].Number != 1 {
+		t.Errorf("expected the merged PR to be dropped, got %+v", cfg.WatchedPRs)
+	}
+	if len(notifier.events) != 1 || notifier.events[0].EventType() != notify.EventMerged {
+		t.Errorf("expected a merged notification before dropping the PR, got %v", notifier.events)
+	}
+	if !strings.Contains(out.String(), "Stopped watching owner/repo#2 (no longer matches repo:owner/repo)") {
+		t.Errorf("expected the removal to be reported, got %q", out.String())
+	}
+
+	// A failing search leaves the watch list alone.
+	client.searches = map[string][]github.PullRequestRef{}
+	w.nextSearch = nil
+	w.checkAllPRs()
+	if len(cfg.WatchedPRs) != 1 {
+		t.Errorf("expected the watch list to be kept when the search fails, got %+v", cfg.WatchedPRs)
+	}
+}
+
+// searchMockClient counts searches and reports a search budget.
+type searchMockClient struct {
+	mockGitHubClient
+	searchLimit github.RateLimit
+	searchCount int
+}
+
+func (m *searchMockClient) SearchPullRequests(query string) ([]github.PullRequestRef, error) {
+	m.searchCount++
+	return m.mockGitHubClient.SearchPullRequests(query)
+}
+
+func (m *searchMockClient) SearchRateLimit() github.RateLimit {
+	return m.searchLimit
+}
+
+func TestWatcherThrottlesSubscriptionSearches(t *testing.T) {
+	sub, _ := config.NewSubscription("", config.SubscriptionRepo, "owner/repo")
+	client := &searchMockClient{mockGitHubClient: mockGitHubClient{
+		searches: map[string][]github.PullRequestRef{sub.Query(): nil},
+	}}
+	cfg := &config.Config{SubscriptionIntervalSeconds: 60, Subscriptions: []config.Subscription{sub}}
+	w := New(client, cfg, &mockNotifier{})
+	var out bytes.Buffer
+	w.SetOutput(&out)
+	now := time.Now()
+
+	if found := w.searchSubscriptions(cfg.Subscriptions, now); client.searchCount != 1 || found == nil {
+		t.Fatalf("expected the first search to run, got %d searches", client.searchCount)
+	}
+
+	// Within the interval the search is skipped.
+	if found := w.searchSubscriptions(cfg.Subscriptions, now.Add(30*time.Second)); client.searchCount != 1 || len(found) != 0 {
+		t.Errorf("expected the search to be throttled, got %d searches and %v", client.searchCount, found)
+	}
+
+	// Once due, an exhausted search budget postpones the search until the reset.
+	reset := now.Add(2 * time.Minute)
+	client.searchLimit = github.RateLimit{Limit: 30, Remaining: 0, Reset: reset}
+	w.searchSubscriptions(cfg.Subscriptions, now.Add(time.Minute))
+	if client.searchCount != 1 || !strings.Contains(out.String(), "GitHub search budget exhausted") {
+		t.Errorf("expected the search to wait for the budget, got %d searches and %q", client.searchCount, out.String())
+	}
+	client.searchLimit.Remaining = 30
+	w.searchSubscriptions(cfg.Subscriptions, now.Add(90*time.Second))
+	if client.searchCount != 1 {
+		t.Errorf("expected no search before the reset, got %d searches", client.searchCount)
+	}
+	if _, ok := w.searchSubscriptions(cfg.Subscriptions, reset)[sub.Key()]; !ok || client.searchCount != 2 {
+		t.Errorf("expected the search to run at the reset, got %d searches", client.searchCount)
+	}
+
+	// A search rejected for the rate limit is retried after its reset, not next cycle.
+	client.err = &github.RateLimitError{StatusCode: 403, Reset: reset.Add(5 * time.Minute)}
+	w.searchSubscriptions(cfg.Subscriptions, reset.Add(time.Minute))
+	w.searchSubscriptions(cfg.Subscriptions, reset.Add(2*time.Minute))
+	if client.searchCount != 3 {
+		t.Errorf("expected the rejected search not to be retried before the reset, got %d searches", client.searchCount)
+	}
+}
+
+// logMockClient adds GitHub Actions job logs to mockGitHubClient.
+type logMockClient struct {
+	mockGitHubClient
+	jobs     map[int64]*github.Job
+	logs     map[int64]string
+	logCalls int
+}
+
+func (m *logMockClient) GetJob(owner, repo string, jobID int64) (*github.Job, error) {
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("job %d not found", jobID)
+	}
+	return job, nil
+}
+
+func (m *logMockClient) GetJobLogs(owner, repo string, jobID int64) ([]byte, error) {
+	m.logCalls++
+	log, ok := m.logs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("logs of job %d expired", jobID)
+	}
+	return []byte(log), nil
+}
+
+func TestWatcherAttachesLogExcerpts(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "open"}
+	pr.Head.SHA = "sha123"
+	actionsRun := func(id int64, name string) github.CheckRun {
+		run := github.CheckRun{ID: id, Name: name, Status: "completed", Conclusion: "failure", HTMLURL: fmt.Sprintf("https://github.com/owner/repo/runs/%d", id)}
+		run.App.Slug = github.ActionsAppSlug
+		return run
+	}
+	external := github.CheckRun{ID: 3, Name: "external", Status: "completed", Conclusion: "failure"}
+	client := &logMockClient{
+		mockGitHubClient: mockGitHubClient{
+			prs:     map[string]*github.PullRequest{"owner/repo/1": pr},
+			runs:    map[string]*github.CheckRunList{"sha123": {CheckRuns: []github.CheckRun{actionsRun(1, "test"), actionsRun(2, "lint"), external}}},
+			reviews: map[string][]github.Review{},
+		},
+		jobs: map[int64]*github.Job{
+			1: {ID: 1, Steps: []github.JobStep{{Name: "Run tests", Conclusion: "failure"}}},
+			2: {ID: 2},
+		},
+		logs: map[int64]string{1: "ok\n##[error]tests failed\ncleanup\n"},
+	}
+	cfg := &config.Config{
+		LogExcerptLines: 5,
+		WatchedPRs:      []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"}},
+	}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	var out bytes.Buffer
+	w.SetOutput(&out)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(notifier.events))
+	}
+	logs := notifier.events[0].Logs
+	if len(logs) != 1 || logs[0].Check != "test" || logs[0].Step != "Run tests" || fmt.Sprint(logs[0].Lines) != "[ok ##[error]tests failed]" {
+		t.Errorf("unexpected log excerpts: %+v", logs)
+	}
+	if !strings.Contains(out.String(), "failed to fetch log of lint") {
+		t.Errorf("expected the missing log to be reported, got %q", out.String())
+	}
+
+	// Still failing: no new event, so no logs are downloaded again.
+	client.logCalls = 0
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if client.logCalls != 0 {
+		t.Errorf("expected no log downloads without a state change, got %d", client.logCalls)
+	}
+}
+
+// rerunMockClient adds GitHub Actions job reruns to mockGitHubClient.
+type rerunMockClient struct {
+	mockGitHubClient
+	rerunJobs    []int64
+	checkRunGets int
+}
+
+func (m *rerunMockClient) GetCheckRuns(owner, repo, ref string) (*github.CheckRunList, error) {
+	m.checkRunGets++
+	return m.mockGitHubClient.GetCheckRuns(owner, repo, ref)
+}
+
+func (m *rerunMockClient) RerunJob(owner, repo string, jobID int64) error {
+	m.rerunJobs = append(m.rerunJobs, jobID)
+	return nil
+}
+
+func TestWatcherRerunsFlakyChecks(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "open"}
+	pr.Head.SHA = "sha123"
+	failedRun := func(id int64, name string) *github.CheckRunList {
+		run := github.CheckRun{ID: id, Name: name, Status: "completed", Conclusion: "failure"}
+		run.App.Slug = github.ActionsAppSlug
+		return &github.CheckRunList{CheckRuns: []github.CheckRun{run}}
+	}
+	client := &rerunMockClient{mockGitHubClient: mockGitHubClient{
+		prs:     map[string]*github.PullRequest{"owner/repo/1": pr},
+		runs:    map[string]*github.CheckRunList{"sha123": failedRun(1, "e2e-tests")},
+		reviews: map[string][]github.Review{},
+	}}
+	cfg := &config.Config{
+		FlakyChecks:    []string{"E2E-*"},
+		FlakyMaxReruns: 1,
+		WatchedPRs:     []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"}},
+	}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	var out bytes.Buffer
+	w.SetOutput(&out)
+	watched := &cfg.WatchedPRs[0]
+
+	// A flaky check failing is re-run instead of notified.
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if fmt.Sprint(client.rerunJobs) != "[1]" || len(notifier.events) != 0 {
+		t.Fatalf("expected job 1 to be re-run without a notification, got reruns %v and %d events", client.rerunJobs, len(notifier.events))
+	}
+	if client.checkRunGets != 1 {
+		t.Errorf("expected the check runs to be fetched once per cycle, got %d fetches", client.checkRunGets)
+	}
+	if watched.LastKnownState != "pending" || len(watched.Reruns) != 1 || watched.Reruns[0].Check != "e2e-tests" {
+		t.Errorf("unexpected state after rerun: %+v", watched)
+	}
+	if !strings.Contains(out.String(), "Re-running flaky check e2e-tests on owner/repo#1 (attempt 1/1)") {
+		t.Errorf("expected the rerun to be reported, got %q", out.String())
+	}
+
+	// The failed run is still reported while the rerun starts.
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(client.rerunJobs) != 1 || len(notifier.events) != 0 {
+		t.Errorf("expected no action while the rerun starts, got reruns %v and %d events", client.rerunJobs, len(notifier.events))
+	}
+
+	// The rerun failed too and the limit is reached: the failure is notified.
+	client.runs["sha123"] = failedRun(2, "e2e-tests")
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(client.rerunJobs) != 1 || len(notifier.events) != 1 || notifier.events[0].CurrentState != "failure" {
+		t.Errorf("expected the failure to be notified once the limit is reached, got reruns %v and events %+v", client.rerunJobs, notifier.events)
+	}
+
+	// Checks that are not on the flaky list are notified right away.
+	pr.Head.SHA = "sha456"
+	watched.LastKnownState = "pending"
+	client.runs["sha456"] = failedRun(3, "unit-tests")
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	last := notifier.events[len(notifier.events)-1]
+	if len(client.rerunJobs) != 1 || last.CurrentState != "failure" || len(last.FlakyChecks) != 0 {
+		t.Errorf("expected a non-flaky failure to be notified without a rerun, got reruns %v and event %+v", client.rerunJobs, last)
+	}
+	if len(watched.Reruns) != 0 {
+		t.Errorf("expected reruns of the previous commit to be dropped, got %+v", watched.Reruns)
+	}
+}
+
+func TestWatcherTagsLikelyFlakyFailures(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "open"}
+	pr.Head.SHA = "sha3"
+	runs := func(conclusion string) *github.CheckRunList {
+		return &github.CheckRunList{CheckRuns: []github.CheckRun{{ID: 1, Name: "e2e", Status: "completed", Conclusion: conclusion}}}
+	}
+	client := &mockGitHubClient{
+		prs:     map[string]*github.PullRequest{"owner/repo/1": pr},
+		runs:    map[string]*github.CheckRunList{"sha3": runs("failure")},
+		reviews: map[string][]github.Review{},
+	}
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownState: "pending"}},
+	}
+	stats := history.NewCheckStatsStore(filepath.Join(t.TempDir(), history.CheckStatsFileName))
+	for _, sha := range []string{"sha1", "sha2"} {
+		stats.Record("", "owner", "repo", "e2e", sha, "failure", time.Now())
+		stats.Record("", "owner", "repo", "e2e", sha, "success", time.Now())
+	}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.SetOutput(io.Discard)
+	w.SetCheckStats(stats)
+
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 1 || !notifier.events[0].LikelyFlaky() {
+		t.Fatalf("expected a likely flaky failure event, got %+v", notifier.events)
+	}
+
+	// Passing on the same commit afterwards is recorded as another flip.
+	client.runs["sha3"] = runs("success")
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	e2e, _ := stats.Find("", "owner", "repo", "e2e")
+	if e2e.FlakyCommits() != 3 || len(e2e.Recent) != 3 {
+		t.Errorf("expected the flip on sha3 to be recorded, got %+v", e2e)
+	}
+	if len(notifier.events) != 2 || notifier.events[1].FlakyChecks != nil {
+		t.Errorf("expected an untagged success event, got %+v", notifier.events)
+	}
+}
+
+// compareMockClient adds commit comparisons to mockGitHubClient.
+type compareMockClient struct {
+	mockGitHubClient
+	comparisons map[string]*github.Comparison
+}
+
+func (m *compareMockClient) CompareCommits(owner, repo, base, head string) (*github.Comparison, error) {
+	comparison, ok := m.comparisons[base+"..."+head]
+	if !ok {
+		return nil, fmt.Errorf("no comparison for %s...%s", base, head)
+	}
+	return comparison, nil
+}
+
+func TestWatcherNotifiesPushes(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "open"}
+	pr.Head.SHA = "sha2"
+	commit := func(login string) github.CompareCommit {
+		return github.CompareCommit{Author: &github.User{Login: login}}
+	}
+	client := &compareMockClient{
+		mockGitHubClient: mockGitHubClient{
+			prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+			statuses: map[string]*github.CombinedStatus{},
+			reviews:  map[string][]github.Review{},
+		},
+		comparisons: map[string]*github.Comparison{
+			"sha1...sha2": {Status: github.CompareAhead, AheadBy: 2, Commits: []github.CompareCommit{commit("alice"), commit("bob"), commit("alice")}},
+			"sha2...sha3": {Status: github.CompareDiverged, AheadBy: 1, BehindBy: 2, Commits: []github.CompareCommit{commit("alice")}},
+		},
+	}
+	cfg := &config.Config{
+		WatchedPRs: []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1, LastKnownSHA: "sha1", LastKnownState: "success"}},
+	}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.SetOutput(io.Discard)
+	watched := &cfg.WatchedPRs[0]
+
+	// A push is notified, but the new commit's pending CI is not a transition.
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected only a push event, got %+v", notifier.events)
+	}
+	pushed := notifier.events[0]
+	if pushed.Type != notify.EventPushed || pushed.PreviousSHA != "sha1" || pushed.SHA != "sha2" || pushed.Commits != 2 || strings.Join(pushed.Authors, ",") != "alice,bob" {
+		t.Errorf("unexpected push event: %+v", pushed)
+	}
+	if watched.LastKnownSHA != "sha2" || watched.LastKnownState != "pending" {
+		t.Errorf("expected the new commit to be tracked as pending, got %s/%s", watched.LastKnownSHA, watched.LastKnownState)
+	}
+
+	// A force-push straight to a failing commit reports both.
+	pr.Head.SHA = "sha3"
+	client.statuses["sha3"] = &github.CombinedStatus{State: "failure", SHA: "sha3", TotalCount: 1, Statuses: []github.Status{{Context: "ci", State: "failure"}}}
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 3 || notifier.events[1].Type != notify.EventForcePushed || notifier.events[2].PreviousState != "pending" || notifier.events[2].CurrentState != "failure" {
+		t.Errorf("expected a force-push and a failure event, got %+v", notifier.events[1:])
+	}
+
+	// A failed comparison is still notified, marked as not compared.
+	pr.Head.SHA = "sha4"
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	last := notifier.events[len(notifier.events)-1]
+	if last.Type != notify.EventPushed || last.Commits != 0 || last.PreviousSHA != "sha3" || !last.CompareFailed {
+		t.Errorf("expected a push event marked as not compared, got %+v", last)
+	}
+	if pushed.CompareFailed || notifier.events[1].CompareFailed {
+		t.Error("expected compared pushes not to be marked as failed")
+	}
+}
+
+func TestWatcherStuckPendingAndDurations(t *testing.T) {
+	pr := &github.PullRequest{Number: 1, State: "open"}
+	pr.Head.SHA = "sha123"
+	client := &mockGitHubClient{
+		prs:      map[string]*github.PullRequest{"owner/repo/1": pr},
+		statuses: map[string]*github.CombinedStatus{},
+		reviews:  map[string][]github.Review{},
+	}
+	cfg := &config.Config{
+		StuckAfterMinutes: map[string]int{"*": 60},
+		WatchedPRs:        []config.WatchedPR{{Owner: "owner", Repo: "repo", Number: 1}},
+	}
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	w.SetOutput(io.Discard)
+	watched := &cfg.WatchedPRs[0]
+
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if watched.PendingSince.IsZero() {
+		t.Fatal("expected the start of pending to be recorded")
+	}
+
+	// Pending for longer than the threshold is reported once.
+	watched.PendingSince = time.Now().Add(-90 * time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := w.checkPR(watched); err != nil {
+			t.Fatalf("checkPR failed: %v", err)
+		}
+	}
+	if len(notifier.events) != 1 || notifier.events[0].Type != notify.EventStuck || notifier.events[0].Duration < 90*time.Minute {
+		t.Fatalf("expected one stuck event, got %+v", notifier.events)
+	}
+
+	// The result carries the total time spent pending.
+	client.statuses["sha123"] = &github.CombinedStatus{State: "success", SHA: "sha123", TotalCount: 1, Statuses: []github.Status{{Context: "ci", State: "success"}}}
+	if err := w.checkPR(watched); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+	if len(notifier.events) != 2 || notifier.events[1].CurrentState != "success" || notifier.events[1].Duration < 90*time.Minute {
+		t.Errorf("expected a success event with the CI duration, got %+v", notifier.events)
+	}
+	if !watched.PendingSince.IsZero() || watched.StuckNotified {
+		t.Errorf("expected pending tracking to be cleared, got %+v", watched)
+	}
+}
+
+func TestWatcherEventAuthorAndLabels(t *testing.T) {
+	pr := &github.PullRequest{
+		Number: 1,
+		Title:  "Test PR",
+		User:   github.User{Login: "octocat"},
+		Labels: []github.Label{{Name: "bug"}, {Name: "area/api"}},
+	}
+	pr.Head.SHA = "sha123"
+
+	client := &mockGitHubClient{
+		prs: map[string]*github.PullRequest{
+			"owner/repo/1": pr,
+		},
+		statuses: map[string]*github.CombinedStatus{
+			"sha123": {State: "failure", SHA: "sha123"},
+		},
+	}
+	cfg := &config.Config{
+		PollIntervalSeconds: 1,
+		WatchedPRs: []config.WatchedPR{
+			{Owner: "owner", Repo: "repo", Number: 1, LastKnownSHA: "sha123", LastKnownState: "pending", Labels: []string{"stale"}},
+		},
+	}
+
+	notifier := &mockNotifier{}
+	w := New(client, cfg, notifier)
+	if err := w.checkPR(&cfg.WatchedPRs[0]); err != nil {
+		t.Fatalf("checkPR failed: %v", err)
+	}
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.Author != "octocat" || len(event.Labels) != 2 || event.Labels[1] != "area/api" {
+		t.Errorf("expected the event to carry the PR author and current labels, got %q %v", event.Author, event.Labels)
+	}
+	if stored := cfg.WatchedPRs[0]; stored.Author != "octocat" || len(stored.Labels) != 2 {
+		t.Errorf("expected the author and labels to be stored, got %q %v", stored.Author, stored.Labels)
+	}
+}
@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/devblac/prw/internal/github"
+)
+
+// maxPayloadBytes is the largest delivery GitHub sends (25 MB).
+const maxPayloadBytes = 25 << 20
+
+// Events that can change the state of a watched PR.
+const (
+	EventStatus      = "status"
+	EventCheckRun    = "check_run"
+	EventCheckSuite  = "check_suite"
+	EventPullRequest = "pull_request"
+	EventPing        = "ping"
+
+	EventPullRequestReview = "pull_request_review"
+)
+
+// ErrIgnored is returned by Parse for events prw does not act on.
+var ErrIgnored = errors.New("event ignored")
+
+// Delivery is the part of a webhook delivery needed to find the affected PRs.
+type Delivery struct {
+	ID      string // X-GitHub-Delivery
+	Event   string // X-GitHub-Event
+	Action  string
+	Host    string // normalized, e.g. github.com
+	Owner   string
+	Repo    string
+	SHA     string // head commit, when known
+	Numbers []int  // pull request numbers, when known
+}
+
+// payload covers the fields used from status, check_run, check_suite, and
+// pull_request payloads.
+type payload struct {
+	Action     string `json:"action"`
+	SHA        string `json:"sha"` // status
+	Number     int    `json:"number"`
+	Repository struct {
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+		Owner   struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+	CheckRun    *checkPayload `json:"check_run"`
+	CheckSuite  *checkPayload `json:"check_suite"`
+	PullRequest *struct {
+		Number int `json:"number"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+type checkPayload struct {
+	HeadSHA      string `json:"head_sha"`
+	PullRequests []struct {
+		Number int `json:"number"`
+	} `json:"pull_requests"`
+}
+
+// Parse decodes a delivery of the given event type. Events other than status,
+// check_run, check_suite, pull_request, and pull_request_review return ErrIgnored.
+func Parse(event string, body []byte) (*Delivery, error) {
+	switch event {
+	case EventStatus, EventCheckRun, EventCheckSuite, EventPullRequest, EventPullRequestReview:
+	default:
+		return nil, ErrIgnored
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("invalid %s payload: %w", event, err)
+	}
+	if p.Repository.Owner.Login == "" || p.Repository.Name == "" {
+		return nil, fmt.Errorf("invalid %s payload: missing repository", event)
+	}
+
+	d := &Delivery{
+		Event:  event,
+		Action: p.Action,
+		Host:   repositoryHost(p.Repository.HTMLURL),
+		Owner:  p.Repository.Owner.Login,
+		Repo:   p.Repository.Name,
+	}
+
+	switch event {
+	case EventStatus:
+		d.SHA = p.SHA
+	case EventCheckRun, EventCheckSuite:
+		check := p.CheckRun
+		if event == EventCheckSuite {
+			check = p.CheckSuite
+		}
+		if check == nil {
+			return nil, fmt.Errorf("invalid %s payload: missing %s", event, event)
+		}
+		d.SHA = check.HeadSHA
+		for _, pr := range check.PullRequests {
+			d.Numbers = append(d.Numbers, pr.Number)
+		}
+	case EventPullRequest, EventPullRequestReview:
+		if p.PullRequest == nil {
+			return nil, fmt.Errorf("invalid %s payload: missing pull_request", event)
+		}
+		d.SHA = p.PullRequest.Head.SHA
+		number := p.Number
+		if number == 0 {
+			number = p.PullRequest.Number
+		}
+		d.Numbers = []int{number}
+	}
+
+	if d.SHA == "" && len(d.Numbers) == 0 {
+		return nil, fmt.Errorf("invalid %s payload: no commit or pull request", event)
+	}
+	return d, nil
+}
+
+// repositoryHost derives the GitHub host from a repository's html_url, so
+// deliveries from Enterprise Server instances map to their own host.
+func repositoryHost(htmlURL string) string {
+	u, err := url.Parse(htmlURL)
+	if err != nil || u.Host == "" {
+		return github.DefaultHost
+	}
+	return github.NormalizeHost(u.Host)
+}
+
+// VerifySignature checks an X-Hub-Signature-256 header against the HMAC-SHA256
+// of body keyed with secret.
+func VerifySignature(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return errors.New("missing X-Hub-Signature-256 header")
+	}
+	hexDigest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return errors.New("unsupported signature format")
+	}
+	got, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 header value GitHub sends for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Handler is an http.Handler that verifies deliveries and passes the relevant
+// ones to Deliver. Deliver is called synchronously and should not block, since
+// GitHub gives up on deliveries that take longer than 10 seconds.
+type Handler struct {
+	Secret  string
+	Deliver func(*Delivery)
+}
+
+// NewHandler creates a handler for deliveries signed with secret.
+func NewHandler(secret string, deliver func(*Delivery)) *Handler {
+	return &Handler{Secret: secret, Deliver: deliver}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Without a secret anyone could trigger API calls, so deliveries are never
+	// accepted unsigned.
+	if h.Secret == "" {
+		http.Error(w, "webhook secret not configured", http.StatusForbidden)
+		return
+	}
+	if err := VerifySignature(h.Secret, body, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	if event == EventPing {
+		fmt.Fprintln(w, "pong")
+		return
+	}
+
+	delivery, err := Parse(event, body)
+	if errors.Is(err, ErrIgnored) {
+		fmt.Fprintf(w, "ignored %s event\n", event)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	delivery.ID = r.Header.Get("X-GitHub-Delivery")
+
+	if h.Deliver != nil {
+		h.Deliver(delivery)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSecret = "It's a Secret to Everybody"
+
+func TestVerifySignature(t *testing.T) {
+	// Example from GitHub's webhook validation documentation.
+	body := []byte("Hello, World!")
+	valid := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	if got := Sign(testSecret, body); got != valid {
+		t.Errorf("Sign() = %s, want %s", got, valid)
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   string
+	}{
+		{"valid", valid, ""},
+		{"missing", "", "missing"},
+		{"sha1", "sha1=757107ea0eb2509fc211221cce984b8a37570b6d", "unsupported"},
+		{"not hex", "sha256=zz", "malformed"},
+		{"wrong", "sha256=" + strings.Repeat("0", 64), "mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(testSecret, body, tt.signature)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifySignature() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifySignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const repository = `"repository": {"name": "repo", "html_url": "https://github.com/owner/repo", "owner": {"login": "owner"}}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		event string
+		body  string
+		want  Delivery
+	}{
+		{
+			event: EventStatus,
+			body:  `{"sha": "abc", "state": "success", "context": "ci", ` + repository + `}`,
+			want:  Delivery{Event: EventStatus, Host: "github.com", Owner: "owner", Repo: "repo", SHA: "abc"},
+		},
+		{
+			event: EventCheckRun,
+			body:  `{"action": "completed", "check_run": {"head_sha": "abc", "pull_requests": [{"number": 1}, {"number": 2}]}, ` + repository + `}`,
+			want:  Delivery{Event: EventCheckRun, Action: "completed", Host: "github.com", Owner: "owner", Repo: "repo", SHA: "abc", Numbers: []int{1, 2}},
+		},
+		{
+			event: EventCheckSuite,
+			body:  `{"action": "requested", "check_suite": {"head_sha": "abc", "pull_requests": []}, ` + repository + `}`,
+			want:  Delivery{Event: EventCheckSuite, Action: "requested", Host: "github.com", Owner: "owner", Repo: "repo", SHA: "abc"},
+		},
+		{
+			event: EventPullRequest,
+			body:  `{"action": "synchronize", "number": 7, "pull_request": {"number": 7, "head": {"sha": "def"}}, ` + repository + `}`,
+			want:  Delivery{Event: EventPullRequest, Action: "synchronize", Host: "github.com", Owner: "owner", Repo: "repo", SHA: "def", Numbers: []int{7}},
+		},
+		{
+			event: EventPullRequestReview,
+			body:  `{"action": "submitted", "pull_request": {"number": 8, "head": {"sha": "def"}}, ` + repository + `}`,
+			want:  Delivery{Event: EventPullRequestReview, Action: "submitted", Host: "github.com", Owner: "owner", Repo: "repo", SHA: "def", Numbers: []int{8}},
+		},
+		{
+			event: EventStatus,
+			body:  `{"sha": "abc", "repository": {"name": "repo", "html_url": "https://GHE.example.com/owner/repo", "owner": {"login": "owner"}}}`,
+			want:  Delivery{Event: EventStatus, Host: "ghe.example.com", Owner: "owner", Repo: "repo", SHA: "abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event+" "+tt.want.Host, func(t *testing.T) {
+			got, err := Parse(tt.event, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		body  string
+	}{
+		{"invalid json", EventStatus, `{`},
+		{"missing repository", EventStatus, `{"sha": "abc"}`},
+		{"missing check run", EventCheckRun, `{` + repository + `}`},
+		{"missing pull request", EventPullRequest, `{"number": 1, ` + repository + `}`},
+		{"no commit", EventStatus, `{` + repository + `}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.event, []byte(tt.body)); err == nil || err == ErrIgnored {
+				t.Errorf("Parse() error = %v, want a payload error", err)
+			}
+		})
+	}
+
+	if _, err := Parse("issues", []byte(`{}`)); err != ErrIgnored {
+		t.Errorf("Parse(issues) error = %v, want ErrIgnored", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	statusBody := `{"sha": "abc", ` + repository + `}`
+
+	tests := []struct {
+		name       string
+		secret     string
+		method     string
+		event      string
+		body       string
+		signature  string
+		wantStatus int
+		wantCalled bool
+	}{
+		{"accepted", testSecret, http.MethodPost, EventStatus, statusBody, Sign(testSecret, []byte(statusBody)), http.StatusAccepted, true},
+		{"bad signature", testSecret, http.MethodPost, EventStatus, statusBody, Sign("other", []byte(statusBody)), http.StatusUnauthorized, false},
+		{"unsigned", testSecret, http.MethodPost, EventStatus, statusBody, "", http.StatusUnauthorized, false},
+		{"no secret configured", "", http.MethodPost, EventStatus, statusBody, Sign("", []byte(statusBody)), http.StatusForbidden, false},
+		{"ping", testSecret, http.MethodPost, EventPing, `{"zen": "hi"}`, Sign(testSecret, []byte(`{"zen": "hi"}`)), http.StatusOK, false},
+		{"ignored event", testSecret, http.MethodPost, "issues", `{}`, Sign(testSecret, []byte(`{}`)), http.StatusOK, false},
+		{"bad payload", testSecret, http.MethodPost, EventStatus, `{}`, Sign(testSecret, []byte(`{}`)), http.StatusBadRequest, false},
+		{"wrong method", testSecret, http.MethodGet, EventStatus, "", "", http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Delivery
+			handler := NewHandler(tt.secret, func(d *Delivery) { got = d })
+
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-GitHub-Delivery", "delivery-1")
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("Deliver called = %v, want %v", got != nil, tt.wantCalled)
+			}
+			if got != nil && (got.ID != "delivery-1" || got.SHA != "abc") {
+				t.Errorf("delivery = %+v", got)
+			}
+		})
+	}
+}